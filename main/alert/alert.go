@@ -0,0 +1,176 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kinds of alerts.
+const (
+	Opportunity = "opportunity" // an opportunity worth more than the threshold
+	Trade       = "trade"       // a trade reached a final state
+	Revert      = "revert"      // a trade reverted
+	KillSwitch  = "kill_switch" // the kill switch tripped
+	Outage      = "outage"      // a subscription stayed down
+)
+
+// Severities of alerts.
+const (
+	Info     = "info"
+	Warning  = "warning"
+	Critical = "critical" // never rate limited
+)
+
+// Alert is a notification for operators.
+type Alert struct {
+	Kind     string    `json:"kind"`
+	Severity string    `json:"severity"`
+	Chain    string    `json:"chain"`
+	Title    string    `json:"title"`
+	Text     string    `json:"text"`
+	Key      string    `json:"key"` // alerts of a kind and chain with the same key are duplicates
+	Time     time.Time `json:"time"`
+}
+
+// Sink delivers alerts somewhere.
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, a Alert) error
+}
+
+// Alerter delivers alerts to its sinks in the background. An alert repeating
+// one sent within Dedup is dropped; beyond MaxPerMinute, alerts that are not
+// critical are dropped too, and the next one sent says how many were. A nil
+// Alerter drops everything, so that pipelines can run without one.
+type Alerter struct {
+	Sinks        []Sink
+	Dedup        time.Duration
+	MaxPerMinute int // 0 for no limit
+
+	// Thresholds of the alerts raised by pipelines.
+	MinOpportunityUSD float64       // 0 for no opportunity alerts
+	OutageAfter       time.Duration // subscription downtime before an alert
+
+	Log *slog.Logger
+
+	queue chan Alert
+
+	mu         sync.Mutex
+	sent       map[string]time.Time // by kind, chain and key
+	window     time.Time            // start of the current rate limit minute
+	count      int                  // alerts sent in the window
+	suppressed int                  // alerts dropped by the rate limit since the last one sent
+	now        func() time.Time
+}
+
+// New creates an alerter delivering to sinks.
+func New(sinks []Sink, dedup time.Duration, maxPerMinute int, logger *slog.Logger) *Alerter {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Alerter{
+		Sinks:        sinks,
+		Dedup:        dedup,
+		MaxPerMinute: maxPerMinute,
+		Log:          logger,
+		queue:        make(chan Alert, 100),
+		sent:         make(map[string]time.Time),
+		now:          time.Now,
+	}
+}
+
+// Notify queues an alert unless it is a duplicate or over the rate limit.
+// It never blocks; if the queue is full the alert is dropped.
+func (a *Alerter) Notify(alert Alert) {
+	if a == nil {
+		return
+	}
+	if alert.Time.IsZero() {
+		alert.Time = a.now()
+	}
+	if !a.admit(&alert) {
+		return
+	}
+	select {
+	case a.queue <- alert:
+	default:
+		a.Log.Warn("alert queue full, alert dropped", "kind", alert.Kind, "title", alert.Title)
+	}
+}
+
+// admit applies deduplication and the rate limit, noting suppressed alerts
+// in the text of the one admitted.
+func (a *Alerter) admit(alert *Alert) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := a.now()
+
+	key := alert.Kind + "|" + alert.Chain + "|" + alert.Key
+	if last, ok := a.sent[key]; ok && now.Sub(last) < a.Dedup {
+		return false
+	}
+	for k, at := range a.sent {
+		if now.Sub(at) >= a.Dedup {
+			delete(a.sent, k)
+		}
+	}
+
+	if now.Sub(a.window) >= time.Minute {
+		a.window, a.count = now, 0
+	}
+	if a.MaxPerMinute > 0 && a.count >= a.MaxPerMinute && alert.Severity != Critical {
+		a.suppressed++
+		return false
+	}
+	a.count++
+	a.sent[key] = now
+	if a.suppressed > 0 {
+		alert.Text = strings.TrimSpace(alert.Text + fmt.Sprintf("\n(%d alert(s) suppressed by the rate limit)", a.suppressed))
+		a.suppressed = 0
+	}
+	return true
+}
+
+// Run delivers queued alerts to every sink until ctx is cancelled.
+func (a *Alerter) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case alert := <-a.queue:
+			a.send(ctx, alert)
+		}
+	}
+}
+
+// Flush delivers the alerts still queued, once Run has returned, until the
+// queue is empty or ctx ends.
+func (a *Alerter) Flush(ctx context.Context) {
+	if a == nil {
+		return
+	}
+	for ctx.Err() == nil {
+		select {
+		case alert := <-a.queue:
+			a.send(ctx, alert)
+		default:
+			return
+		}
+	}
+}
+
+// send delivers an alert to every sink. The URL of a failed request is left
+// out of the log, as sinks' URLs may hold their credentials.
+func (a *Alerter) send(ctx context.Context, alert Alert) {
+	for _, sink := range a.Sinks {
+		sendCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		if err := sink.Send(sendCtx, alert); err != nil {
+			a.Log.Warn("failed to send alert", "sink", sink.Name(), "kind", alert.Kind, "err", stripURL(err))
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,165 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// queued drains the alerts an alerter has admitted.
+func queued(a *Alerter) []Alert {
+	var alerts []Alert
+	for {
+		select {
+		case alert := <-a.queue:
+			alerts = append(alerts, alert)
+		default:
+			return alerts
+		}
+	}
+}
+
+func newTestAlerter(dedup time.Duration, maxPerMinute int) (*Alerter, *time.Time) {
+	a := New(nil, dedup, maxPerMinute, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	a.now = func() time.Time { return now }
+	return a, &now
+}
+
+func TestDedup(t *testing.T) {
+	a, now := newTestAlerter(5*time.Minute, 0)
+	alert := Alert{Kind: Opportunity, Chain: "eth", Key: "ETH->DAI->ETH", Title: "cycle"}
+
+	a.Notify(alert)
+	a.Notify(alert)
+	a.Notify(Alert{Kind: Opportunity, Chain: "bsc", Key: alert.Key, Title: "cycle"})
+	if got := len(queued(a)); got != 2 {
+		t.Fatalf("%d alerts admitted, want the first and the other chain's", got)
+	}
+
+	*now = now.Add(4 * time.Minute)
+	a.Notify(alert)
+	if got := len(queued(a)); got != 0 {
+		t.Fatalf("repeat within the dedup window admitted")
+	}
+	*now = now.Add(time.Minute)
+	a.Notify(alert)
+	if got := len(queued(a)); got != 1 {
+		t.Fatalf("repeat after the dedup window dropped")
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	a, now := newTestAlerter(0, 2)
+	for _, key := range []string{"a", "b", "c", "d"} {
+		a.Notify(Alert{Kind: Trade, Severity: Info, Chain: "eth", Key: key, Title: key})
+	}
+	if got := len(queued(a)); got != 2 {
+		t.Fatalf("%d alerts admitted, want 2", got)
+	}
+
+	// Critical alerts pass the limit, and report what it dropped
+	a.Notify(Alert{Kind: KillSwitch, Severity: Critical, Chain: "eth", Title: "halted"})
+	alerts := queued(a)
+	if len(alerts) != 1 {
+		t.Fatalf("critical alert dropped by the rate limit")
+	}
+	if !strings.Contains(alerts[0].Text, "(2 alert(s) suppressed by the rate limit)") {
+		t.Errorf("text %q does not report the suppressed alerts", alerts[0].Text)
+	}
+
+	a.Notify(Alert{Kind: Trade, Severity: Info, Chain: "eth", Key: "e", Title: "e"})
+	if got := len(queued(a)); got != 0 {
+		t.Fatalf("alert over the limit admitted")
+	}
+	*now = now.Add(time.Minute)
+	a.Notify(Alert{Kind: Trade, Severity: Info, Chain: "eth", Key: "f", Title: "f"})
+	alerts = queued(a)
+	if len(alerts) != 1 {
+		t.Fatalf("alert in the next minute dropped")
+	}
+	if !strings.Contains(alerts[0].Text, "(1 alert(s) suppressed by the rate limit)") {
+		t.Errorf("text %q does not report the suppressed alert", alerts[0].Text)
+	}
+}
+
+func TestSinks(t *testing.T) {
+	recorder := &Recorder{}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+	alert := Alert{Kind: Revert, Severity: Critical, Chain: "eth", Title: "trade reverted", Text: "tx 0x01", Key: "0x01", Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	sinks := []Sink{
+		&Webhook{URL: server.URL + "/hook"},
+		&Slack{URL: server.URL + "/services/T0/B0/secret"},
+		&Telegram{URL: server.URL + "/", Token: "123:secret", ChatID: "42"},
+	}
+	for _, sink := range sinks {
+		if err := sink.Send(context.Background(), alert); err != nil {
+			t.Fatalf("%s: %v", sink.Name(), err)
+		}
+	}
+	received := recorder.Received()
+	if len(received) != 3 {
+		t.Fatalf("%d requests received, want 3", len(received))
+	}
+
+	var webhook Alert
+	if err := json.Unmarshal(received[0].Body, &webhook); err != nil {
+		t.Fatal(err)
+	}
+	if received[0].Path != "/hook" || webhook != alert {
+		t.Errorf("webhook got %s %+v", received[0].Path, webhook)
+	}
+
+	var slack map[string]string
+	if err := json.Unmarshal(received[1].Body, &slack); err != nil {
+		t.Fatal(err)
+	}
+	if want := "🚨 *[eth] trade reverted*\ntx 0x01"; received[1].Path != "/services/T0/B0/secret" || slack["text"] != want || len(slack) != 1 {
+		t.Errorf("slack got %s %v", received[1].Path, slack)
+	}
+
+	var telegram map[string]string
+	if err := json.Unmarshal(received[2].Body, &telegram); err != nil {
+		t.Fatal(err)
+	}
+	if want := "🚨 [eth] trade reverted\ntx 0x01"; received[2].Path != "/bot123:secret/sendMessage" || telegram["chat_id"] != "42" || telegram["text"] != want {
+		t.Errorf("telegram got %s %v", received[2].Path, telegram)
+	}
+}
+
+// TestSinkErrorsHideURL checks that a failed send reports neither a Slack
+// webhook's path nor a Telegram token.
+func TestSinkErrorsHideURL(t *testing.T) {
+	recorder := &Recorder{Status: http.StatusInternalServerError}
+	server := httptest.NewServer(recorder)
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	defer server.Close()
+
+	for _, base := range []string{server.URL, down.URL} {
+		sinks := []Sink{
+			&Slack{URL: base + "/services/T0/B0/secret"},
+			&Telegram{URL: base, Token: "123:secret", ChatID: "42"},
+		}
+		for _, sink := range sinks {
+			err := sink.Send(context.Background(), Alert{Title: "test"})
+			if err == nil {
+				t.Fatalf("%s: send to a failing endpoint succeeded", sink.Name())
+			}
+			if strings.Contains(err.Error(), "secret") {
+				t.Errorf("%s: error %q reveals the URL", sink.Name(), err)
+			}
+		}
+	}
+	if err := (&Slack{URL: "http://in valid/secret"}).Send(context.Background(), Alert{}); err == nil || strings.Contains(err.Error(), "secret") {
+		t.Errorf("invalid URL error %v", err)
+	}
+}
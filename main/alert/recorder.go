@@ -0,0 +1,52 @@
+package alert
+
+import (
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Received is a request recorded by a Recorder.
+type Received struct {
+	Path string
+	Body []byte
+}
+
+// Recorder is a local stand-in for webhook, Slack and Telegram endpoints,
+// served over HTTP, e.g. with httptest. It accepts and records every POST, or
+// fails them with Status if set.
+type Recorder struct {
+	Status int
+
+	mu       sync.Mutex
+	received []Received
+}
+
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	r.mu.Lock()
+	r.received = append(r.received, Received{Path: req.URL.Path, Body: body})
+	status := r.Status
+	r.mu.Unlock()
+	if status != 0 {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	io.WriteString(w, `{"ok":true}`)
+}
+
+// Received returns the requests recorded so far.
+func (r *Recorder) Received() []Received {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Received(nil), r.received...)
+}
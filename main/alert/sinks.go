@@ -0,0 +1,106 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Webhook posts alerts as JSON objects to a URL.
+type Webhook struct {
+	URL    string
+	Client *http.Client // http.DefaultClient if nil
+}
+
+func (w *Webhook) Name() string { return "webhook" }
+
+func (w *Webhook) Send(ctx context.Context, a Alert) error {
+	return post(ctx, w.Client, w.URL, a)
+}
+
+// Slack posts alerts to a Slack incoming webhook, or anything accepting its
+// payload.
+type Slack struct {
+	URL    string
+	Client *http.Client
+}
+
+func (s *Slack) Name() string { return "slack" }
+
+func (s *Slack) Send(ctx context.Context, a Alert) error {
+	return post(ctx, s.Client, s.URL, map[string]string{"text": fmt.Sprintf("%s *[%s] %s*\n%s", emoji(a.Severity), a.Chain, a.Title, a.Text)})
+}
+
+// Telegram sends alerts to a chat through the Telegram Bot API at URL, by
+// default https://api.telegram.org.
+type Telegram struct {
+	URL    string
+	Token  string
+	ChatID string
+	Client *http.Client
+}
+
+func (t *Telegram) Name() string { return "telegram" }
+
+func (t *Telegram) Send(ctx context.Context, a Alert) error {
+	base := t.URL
+	if base == "" {
+		base = "https://api.telegram.org"
+	}
+	return post(ctx, t.Client, strings.TrimSuffix(base, "/")+"/bot"+t.Token+"/sendMessage", map[string]string{
+		"chat_id": t.ChatID,
+		"text":    fmt.Sprintf("%s [%s] %s\n%s", emoji(a.Severity), a.Chain, a.Title, a.Text),
+	})
+}
+
+func emoji(severity string) string {
+	switch severity {
+	case Critical:
+		return "🚨"
+	case Warning:
+		return "⚠️"
+	}
+	return "ℹ️"
+}
+
+// post sends a JSON body and fails on any status but 2xx. Its errors never
+// carry the target, which holds the credentials of Slack and Telegram.
+func post(ctx context.Context, client *http.Client, target string, body any) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("invalid URL: %v", stripURL(err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := client.Do(req)
+	if err != nil {
+		return stripURL(err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode/100 != 2 {
+		text, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return fmt.Errorf("%s: %s", res.Status, strings.TrimSpace(string(text)))
+	}
+	return nil
+}
+
+// stripURL returns the error behind a *url.Error, without its URL.
+func stripURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return fmt.Errorf("%s: %v", urlErr.Op, urlErr.Err)
+	}
+	return err
+}
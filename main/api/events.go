@@ -0,0 +1,115 @@
+package api
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"bb/feed"
+	"bb/ledger"
+)
+
+//go:embed dashboard.html
+var dashboardHTML []byte
+
+func (s *Server) dashboard(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(dashboardHTML)
+}
+
+// replayed is how many of the latest opportunities and trades of each chain
+// a new stream starts with.
+const replayed = 20
+
+// events streams the feed as Server-Sent Events, optionally for one ?chain.
+// A stream starts with the latest graph of each chain and its latest
+// opportunities and trades from the ledger.
+func (s *Server) events(w http.ResponseWriter, r *http.Request) {
+	chain := r.URL.Query().Get("chain")
+	if chain != "" && s.Pipelines[chain] == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown chain %q", chain))
+		return
+	}
+	if s.Feed == nil {
+		writeError(w, http.StatusServiceUnavailable, fmt.Errorf("no event feed"))
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("streaming unsupported"))
+		return
+	}
+
+	events, unsubscribe := s.Feed.Subscribe(256)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	for _, event := range s.replay(chain) {
+		if err := writeEvent(w, event); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	keepalive := time.NewTicker(15 * time.Second)
+	defer keepalive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		case event := <-events:
+			if chain != "" && event.Chain != chain {
+				continue
+			}
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// replay returns the events a new stream starts with.
+func (s *Server) replay(chain string) []feed.Event {
+	var events []feed.Event
+	for name, p := range s.Pipelines {
+		if chain != "" && name != chain {
+			continue
+		}
+		if graph := p.Strategy.Graph(); graph != nil {
+			events = append(events, feed.Event{Type: feed.Graph, Chain: name, Time: graph.Time, Data: graph})
+		}
+		if p.Ledger == nil {
+			continue
+		}
+		for _, kind := range []struct{ kind, typ string }{{ledger.Simulated, feed.Opportunity}, {ledger.Executed, feed.Trade}} {
+			entries, err := p.Ledger.Recent(p.Name, kind.kind, replayed)
+			if err != nil {
+				s.Log.Warn("failed to replay ledger", "err", err)
+				break
+			}
+			for _, e := range entries {
+				events = append(events, feed.Event{Type: kind.typ, Chain: p.Name, Time: e.Time, Data: e})
+			}
+		}
+	}
+	return events
+}
+
+func writeEvent(w http.ResponseWriter, event feed.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+	return err
+}
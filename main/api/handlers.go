@@ -0,0 +1,324 @@
+package api
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"bb/config"
+	"bb/inventory"
+	"bb/pipeline"
+	"bb/strategy"
+	"bb/types"
+)
+
+type chainView struct {
+	Name          string `json:"name"`
+	ChainID       string `json:"chainId"`
+	Paused        bool   `json:"paused"`
+	Halted        bool   `json:"halted"`
+	HaltReason    string `json:"haltReason,omitempty"`
+	Pairs         int    `json:"pairs"`
+	SwapEvents    uint64 `json:"swapEvents"`
+	PendingSwaps  uint64 `json:"pendingSwaps"`
+	Evaluations   uint64 `json:"evaluations"`
+	Opportunities uint64 `json:"opportunities"`
+	Trades        uint64 `json:"trades"`
+	Rejected      uint64 `json:"rejected"`
+}
+
+func (s *Server) chains(w http.ResponseWriter, r *http.Request) {
+	views := make([]chainView, 0, len(s.Pipelines))
+	for _, p := range s.Pipelines {
+		halted, reason := p.Risk.Status()
+		views = append(views, chainView{
+			Name:          p.Name,
+			ChainID:       p.ChainID.String(),
+			Paused:        p.Paused(),
+			Halted:        halted,
+			HaltReason:    reason,
+			Pairs:         len(p.Strategy.Pairs()),
+			SwapEvents:    p.Stats.SwapEvents.Load(),
+			PendingSwaps:  p.Stats.PendingSwaps.Load(),
+			Evaluations:   p.Stats.Evaluations.Load(),
+			Opportunities: p.Stats.Opportunities.Load(),
+			Trades:        p.Stats.Trades.Load(),
+			Rejected:      p.Stats.Rejected.Load(),
+		})
+	}
+	sort.Slice(views, func(i, j int) bool { return views[i].Name < views[j].Name })
+	writeJSON(w, http.StatusOK, views)
+}
+
+type pairView struct {
+	DEX      string `json:"dex"`
+	Asset1   string `json:"asset1"`
+	Asset2   string `json:"asset2"`
+	Address  string `json:"address"`
+	Excluded string `json:"excluded,omitempty"` // why the filter keeps it out of the graph
+
+	// From the latest swap event, in whole tokens; absent before the first.
+	Block    uint64     `json:"block,omitempty"`
+	Reserve1 *big.Float `json:"reserve1,omitempty"`
+	Reserve2 *big.Float `json:"reserve2,omitempty"`
+	Rate12   *big.Float `json:"rate12,omitempty"` // Asset2 out per Asset1 in
+	Rate21   *big.Float `json:"rate21,omitempty"` // Asset1 out per Asset2 in
+}
+
+func newPairView(p *pipeline.Pipeline, pair types.Pair, excluded map[string]string) pairView {
+	view := pairView{
+		DEX:      pair.DEX(),
+		Asset1:   pair.Asset1(),
+		Asset2:   pair.Asset2(),
+		Address:  pair.Address(),
+		Excluded: excluded[strings.ToLower(pair.Address())+":"+pair.Asset1()+"/"+pair.Asset2()],
+	}
+	if event, ok := p.LatestEvent(pair); ok {
+		view.Block = event.BlockNumber
+		view.Reserve1, view.Reserve2 = event.Reserve1, event.Reserve2
+		view.Rate12, view.Rate21 = event.AmountOut.Amount1, event.AmountOut.Amount2
+	}
+	return view
+}
+
+func (s *Server) pairs(w http.ResponseWriter, r *http.Request, p *pipeline.Pipeline) {
+	excluded := make(map[string]string)
+	for _, e := range p.Strategy.Filter.Report() {
+		excluded[strings.ToLower(e.Address)+":"+e.Asset1+"/"+e.Asset2] = e.Reason
+	}
+	pairs := p.Strategy.Pairs()
+	views := make([]pairView, 0, len(pairs))
+	for _, pair := range pairs {
+		views = append(views, newPairView(p, pair, excluded))
+	}
+	writeJSON(w, http.StatusOK, views)
+}
+
+// addPair takes a pair in the format of the config file.
+func (s *Server) addPair(w http.ResponseWriter, r *http.Request, p *pipeline.Pipeline) {
+	var pair config.Pair
+	if err := readJSON(w, r, &pair); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	added, err := p.AddPair(r.Context(), pair)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	views := make([]pairView, 0, len(added))
+	for _, a := range added {
+		views = append(views, newPairView(p, a, nil))
+	}
+	writeJSON(w, http.StatusCreated, views)
+}
+
+func (s *Server) removePair(w http.ResponseWriter, r *http.Request, p *pipeline.Pipeline) {
+	removed, err := p.RemovePair(r.PathValue("address"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	views := make([]pairView, 0, len(removed))
+	for _, pair := range removed {
+		views = append(views, newPairView(p, pair, nil))
+	}
+	writeJSON(w, http.StatusOK, views)
+}
+
+type quoteView struct {
+	Pair      string `json:"pair"`
+	AssetIn   string `json:"assetIn"`
+	AssetOut  string `json:"assetOut"`
+	AmountIn  string `json:"amountIn"` // raw token units
+	AmountOut string `json:"amountOut"`
+}
+
+// quote prices ?amount raw units of ?assetIn through a pair at its last known
+// state. ?assetOut picks the pair among those of a pool with more than two
+// assets.
+func (s *Server) quote(w http.ResponseWriter, r *http.Request, p *pipeline.Pipeline) {
+	assetIn, assetOut := r.URL.Query().Get("assetIn"), r.URL.Query().Get("assetOut")
+	amountIn, ok := new(big.Int).SetString(r.URL.Query().Get("amount"), 10)
+	if !ok || amountIn.Sign() <= 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("amount must be a positive integer of raw token units"))
+		return
+	}
+
+	for _, pair := range p.Strategy.Pairs() {
+		if !strings.EqualFold(pair.Address(), r.PathValue("address")) {
+			continue
+		}
+		out := pair.Asset2()
+		if pair.Asset2() == assetIn {
+			out = pair.Asset1()
+		} else if pair.Asset1() != assetIn {
+			continue
+		}
+		if assetOut != "" && out != assetOut {
+			continue
+		}
+		amountOut, err := pair.Quote(assetIn, amountIn)
+		if err != nil {
+			writeError(w, http.StatusConflict, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, quoteView{Pair: pair.Address(), AssetIn: assetIn, AssetOut: out, AmountIn: amountIn.String(), AmountOut: amountOut.String()})
+		return
+	}
+	writeError(w, http.StatusNotFound, fmt.Errorf("no pair at %s trades %s", r.PathValue("address"), assetIn))
+}
+
+// graph returns the graph of the latest evaluation, as JSON or, with
+// ?format=dot, for Graphviz.
+func (s *Server) graph(w http.ResponseWriter, r *http.Request, p *pipeline.Pipeline) {
+	graph := p.Strategy.Graph()
+	if graph == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no graph evaluated yet"))
+		return
+	}
+	switch format := r.URL.Query().Get("format"); format {
+	case "", strategy.JSON:
+		writeJSON(w, http.StatusOK, graph)
+	case strategy.DOT:
+		w.Header().Set("Content-Type", "text/vnd.graphviz")
+		graph.WriteDOT(w)
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("unknown graph format %q, expected dot or json", format))
+	}
+}
+
+type opportunityView struct {
+	Path     []strategy.AssetDEX `json:"path"`
+	AmountIn float64             `json:"amountIn"`
+	Rates    []float64           `json:"rates"`
+	Block    uint64              `json:"block"`
+	Found    time.Time           `json:"found"`
+	GasUnits uint64              `json:"gasUnits"`
+	GasCost  string              `json:"gasCost"` // in wei
+}
+
+func (s *Server) opportunities(w http.ResponseWriter, r *http.Request, p *pipeline.Pipeline) {
+	opportunities := p.Strategy.Opportunities()
+	views := make([]opportunityView, 0, len(opportunities))
+	for i := len(opportunities) - 1; i >= 0; i-- {
+		o := opportunities[i]
+		views = append(views, opportunityView{
+			Path:     o.Path,
+			AmountIn: o.AmountIn,
+			Rates:    o.Rates,
+			Block:    o.Block,
+			Found:    o.Found,
+			GasUnits: o.GasUnits,
+			GasCost:  o.GasCost().String(),
+		})
+	}
+	writeJSON(w, http.StatusOK, views)
+}
+
+type transactionView struct {
+	Label      string    `json:"label"`
+	ID         string    `json:"id,omitempty"`
+	Hash       string    `json:"hash"`
+	Nonce      uint64    `json:"nonce"`
+	Status     string    `json:"status"`
+	Attempts   int       `json:"attempts"`
+	Cancelling bool      `json:"cancelling"`
+	Sent       time.Time `json:"sent"`
+	GasFeeCap  string    `json:"gasFeeCap"` // in wei
+	GasTipCap  string    `json:"gasTipCap"`
+}
+
+// transactions lists the transactions that are not final yet.
+func (s *Server) transactions(w http.ResponseWriter, r *http.Request, p *pipeline.Pipeline) {
+	txs := p.Txs.Pending()
+	views := make([]transactionView, 0, len(txs))
+	for _, tx := range txs {
+		current := tx.Current()
+		views = append(views, transactionView{
+			Label:      tx.Label,
+			ID:         tx.ID,
+			Hash:       current.Hash().Hex(),
+			Nonce:      current.Nonce(),
+			Status:     tx.Status().String(),
+			Attempts:   len(tx.Attempts()),
+			Cancelling: tx.Cancelling(),
+			Sent:       tx.Sent(),
+			GasFeeCap:  current.GasFeeCap().String(),
+			GasTipCap:  current.GasTipCap().String(),
+		})
+	}
+	writeJSON(w, http.StatusOK, views)
+}
+
+type balanceView struct {
+	Asset     string  `json:"asset"`
+	Native    bool    `json:"native,omitempty"`
+	Amount    float64 `json:"amount"`
+	Reserved  float64 `json:"reserved"`
+	Available float64 `json:"available"`
+	PriceUSD  float64 `json:"priceUSD,omitempty"`
+}
+
+func (s *Server) inventory(w http.ResponseWriter, r *http.Request, p *pipeline.Pipeline) {
+	prices := p.Strategy.Prices()
+	balances := p.Inventory.Balances()
+	views := make([]balanceView, 0, len(balances))
+	for _, b := range balances {
+		views = append(views, newBalanceView(b, prices))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"account": p.Inventory.Account.Hex(), "balances": views})
+}
+
+func newBalanceView(b inventory.Balance, prices map[string]float64) balanceView {
+	return balanceView{
+		Asset:     b.Asset,
+		Native:    b.Native,
+		Amount:    b.Amount,
+		Reserved:  b.Reserved,
+		Available: b.Available,
+		PriceUSD:  prices[b.Asset],
+	}
+}
+
+func (s *Server) pause(w http.ResponseWriter, r *http.Request, p *pipeline.Pipeline) {
+	p.Pause()
+	writeJSON(w, http.StatusOK, map[string]bool{"paused": true})
+}
+
+func (s *Server) resume(w http.ResponseWriter, r *http.Request, p *pipeline.Pipeline) {
+	p.Resume()
+	writeJSON(w, http.StatusOK, map[string]bool{"paused": false})
+}
+
+// thresholdsView is the pair admission filter, as in the config file.
+type thresholdsView struct {
+	strategy.PairRule
+	Pairs map[string]strategy.PairRule `json:"pairs"` // keyed by pair address
+}
+
+func (s *Server) thresholds(w http.ResponseWriter, r *http.Request, p *pipeline.Pipeline) {
+	global, overrides := p.Strategy.Filter.Rules()
+	writeJSON(w, http.StatusOK, thresholdsView{PairRule: global, Pairs: overrides})
+}
+
+// setThresholds replaces the global rule and every override; the graph uses
+// them from the next evaluation on.
+func (s *Server) setThresholds(w http.ResponseWriter, r *http.Request, p *pipeline.Pipeline) {
+	var view thresholdsView
+	if err := readJSON(w, r, &view); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if view.MinLiquidityUSD < 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("minLiquidityUSD must not be negative"))
+		return
+	}
+	p.Strategy.Filter.SetRules(view.PairRule, view.Pairs)
+	p.Log.Info("thresholds changed", "minLiquidityUSD", view.MinLiquidityUSD, "maxStaleBlocks", view.MaxStaleBlocks, "overrides", len(view.Pairs))
+	global, overrides := p.Strategy.Filter.Rules()
+	writeJSON(w, http.StatusOK, thresholdsView{PairRule: global, Pairs: overrides})
+}
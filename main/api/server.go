@@ -0,0 +1,131 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"bb/feed"
+	"bb/pipeline"
+)
+
+// Server serves a JSON API to inspect and control running pipelines, and a
+// dashboard built on it. Reads are open; requests that change anything need
+// the bearer token, and are refused if there is none.
+type Server struct {
+	Pipelines map[string]*pipeline.Pipeline // by chain name
+	Token     string
+	Feed      *feed.Feed // streamed at /api/events
+	Log       *slog.Logger
+
+	mux *http.ServeMux
+}
+
+// New creates the API server of pipelines, streaming the events of f.
+func New(pipelines []*pipeline.Pipeline, token string, f *feed.Feed) *Server {
+	s := &Server{
+		Pipelines: make(map[string]*pipeline.Pipeline, len(pipelines)),
+		Token:     token,
+		Feed:      f,
+		Log:       slog.Default(),
+		mux:       http.NewServeMux(),
+	}
+	for _, p := range pipelines {
+		s.Pipelines[p.Name] = p
+	}
+
+	s.mux.HandleFunc("GET /{$}", s.dashboard)
+	s.mux.HandleFunc("GET /api/events", s.events)
+	s.mux.HandleFunc("GET /api/chains", s.chains)
+	s.mux.HandleFunc("GET /api/chains/{chain}/pairs", s.chain(s.pairs))
+	s.mux.HandleFunc("POST /api/chains/{chain}/pairs", s.control(s.addPair))
+	s.mux.HandleFunc("DELETE /api/chains/{chain}/pairs/{address}", s.control(s.removePair))
+	s.mux.HandleFunc("GET /api/chains/{chain}/pairs/{address}/quote", s.chain(s.quote))
+	s.mux.HandleFunc("GET /api/chains/{chain}/graph", s.chain(s.graph))
+	s.mux.HandleFunc("GET /api/chains/{chain}/opportunities", s.chain(s.opportunities))
+	s.mux.HandleFunc("GET /api/chains/{chain}/transactions", s.chain(s.transactions))
+	s.mux.HandleFunc("GET /api/chains/{chain}/inventory", s.chain(s.inventory))
+	s.mux.HandleFunc("POST /api/chains/{chain}/pause", s.control(s.pause))
+	s.mux.HandleFunc("POST /api/chains/{chain}/resume", s.control(s.resume))
+	s.mux.HandleFunc("GET /api/chains/{chain}/thresholds", s.chain(s.thresholds))
+	s.mux.HandleFunc("PUT /api/chains/{chain}/thresholds", s.control(s.setThresholds))
+	return s
+}
+
+// Handle adds a handler to the server, e.g. for pages built on the API.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Serve serves the API on addr until ctx is cancelled.
+func (s *Server) Serve(ctx context.Context, addr string) error {
+	server := &http.Server{Addr: addr, Handler: s, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+	s.Log.Info("serving API and dashboard", "addr", addr)
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// chainHandler handles a request about one chain's pipeline.
+type chainHandler func(w http.ResponseWriter, r *http.Request, p *pipeline.Pipeline)
+
+// chain resolves the {chain} of the path to its pipeline.
+func (s *Server) chain(handle chainHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, ok := s.Pipelines[r.PathValue("chain")]
+		if !ok {
+			writeError(w, http.StatusNotFound, fmt.Errorf("unknown chain %q", r.PathValue("chain")))
+			return
+		}
+		handle(w, r, p)
+	}
+}
+
+// control is chain for requests that change a pipeline: they need the token
+// and are logged.
+func (s *Server) control(handle chainHandler) http.HandlerFunc {
+	return s.chain(func(w http.ResponseWriter, r *http.Request, p *pipeline.Pipeline) {
+		if s.Token == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+s.Token)) != 1 {
+			writeError(w, http.StatusUnauthorized, fmt.Errorf("missing or invalid bearer token"))
+			return
+		}
+		p.Log.Info("API request", "method", r.Method, "path", r.URL.Path, "remote", r.RemoteAddr)
+		handle(w, r, p)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// readJSON decodes a request body, refusing unknown fields so that typos do
+// not silently change nothing.
+func readJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid request body: %v", err)
+	}
+	return nil
+}
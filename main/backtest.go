@@ -0,0 +1,306 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	"bb/config"
+	"bb/contracts/uniswapv2"
+	"bb/pipeline"
+	"bb/types"
+)
+
+// backtestCommand replays the swaps of a past block range on the chain's V2
+// pairs through the strategy. It needs an archive node to read reserves at
+// past blocks.
+func backtestCommand(args []string) error {
+	opts := newOptions("backtest", "[flags] --from <block> [--to <block>]", "Evaluates the strategy on every swap of the chain's V2 pairs in a past block range,\nwith the reserves at each swap's block. Needs an archive node.")
+	from := opts.flags.Uint64("from", 0, "first block")
+	to := opts.flags.Uint64("to", 0, "last block, the head if 0")
+	chunk := opts.flags.Uint64("chunk", 2000, "blocks per log query")
+	record := opts.flags.String("record", "", "file to append the swap events to, for replay")
+	gwei := opts.flags.Float64("gas-price", 0, "gas price in gwei to cost opportunities at, the node's current price if 0")
+	if _, err := opts.parse(args); err != nil {
+		return err
+	}
+	if *from == 0 {
+		opts.flags.Usage()
+		return fmt.Errorf("--from is required")
+	}
+	cfg, err := opts.load(false)
+	if err != nil {
+		return err
+	}
+	chain, err := opts.chain(cfg)
+	if err != nil {
+		return err
+	}
+	logger := slog.Default().With("chain", chain.Name)
+
+	ctx := context.Background()
+	clients, err := pipeline.DialPool(ctx, chain.NodeURLs)
+	if err != nil {
+		return err
+	}
+	defer clients.Close()
+	if *to == 0 {
+		if *to, err = clients.BlockNumber(ctx); err != nil {
+			return fmt.Errorf("failed to read head block: %v", err)
+		}
+	}
+	if *to < *from {
+		return fmt.Errorf("block range %d-%d is empty", *from, *to)
+	}
+	gasPrice := gweiToWei(*gwei)
+	if *gwei == 0 {
+		if gasPrice, err = clients.SuggestGasPrice(ctx); err != nil {
+			return fmt.Errorf("failed to read gas price: %v", err)
+		}
+	}
+
+	built, err := pipeline.BuildPairs(chain, clients)
+	if err != nil {
+		return err
+	}
+	var pairs []types.Pair
+	for _, pair := range built {
+		if _, ok := pair.(*uniswapv2pair.Instance); ok {
+			pairs = append(pairs, pair)
+		} else {
+			logger.Warn("skipped, only V2 pairs can be backtested", "dex", pair.DEX(), "pair", pair.Address())
+		}
+	}
+
+	events, err := pastSwaps(ctx, pairs, *from, *to, max(*chunk, 1), logger)
+	if err != nil {
+		return err
+	}
+	if *record != "" {
+		eventLog, err := pipeline.CreateEventLog(*record)
+		if err != nil {
+			return err
+		}
+		defer eventLog.Close()
+		for _, e := range events {
+			if err := eventLog.Append(e); err != nil {
+				return err
+			}
+		}
+	}
+
+	result := evaluate(ctx, chain, pairs, events, gasPrice, logger)
+	result.From, result.To = *from, *to
+	return printRun(opts, result)
+}
+
+// replayCommand evaluates swap events recorded by monitor or backtest. It
+// works offline: the pairs are those of the events, and the filters and gas
+// model those of the chain's config.
+func replayCommand(args []string) error {
+	opts := newOptions("replay", "[flags] <event log>", "Evaluates the strategy on swap events recorded by monitor --record or\nbacktest --record, offline.")
+	gwei := opts.flags.Float64("gas-price", 0, "gas price in gwei to cost opportunities at")
+	args, err := opts.parse(args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		opts.flags.Usage()
+		return fmt.Errorf("expected an event log")
+	}
+	cfg, err := opts.load(false)
+	if err != nil {
+		return err
+	}
+	chain, err := replayChain(opts, cfg)
+	if err != nil {
+		return err
+	}
+
+	events, err := pipeline.ReadEvents(args[0])
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return fmt.Errorf("%s has no swap events", args[0])
+	}
+	result := evaluate(context.Background(), chain, recordedPairs(events), events, gweiToWei(*gwei), slog.Default().With("chain", chain.Name))
+	result.From, result.To = events[0].BlockNumber, events[len(events)-1].BlockNumber
+	return printRun(opts, result)
+}
+
+// replayChain returns the chain whose filters and gas model a replay uses:
+// the one named by --chain, or the only one configured. No node is needed.
+func replayChain(opts *options, cfg *config.Config) (config.Chain, error) {
+	chains, err := opts.chains(cfg)
+	if err != nil {
+		return config.Chain{}, err
+	}
+	if len(chains) != 1 {
+		return config.Chain{}, fmt.Errorf("several chains configured, select one with --chain")
+	}
+	return chains[0], nil
+}
+
+func gweiToWei(gwei float64) *big.Int {
+	wei, _ := new(big.Float).Mul(big.NewFloat(gwei), big.NewFloat(1e9)).Int(nil)
+	return wei
+}
+
+// pastSwaps returns a swap event for every block in which a pair swapped,
+// with the pair's reserves at the end of that block, ordered by block.
+func pastSwaps(ctx context.Context, pairs []types.Pair, from, to, chunk uint64, logger *slog.Logger) ([]types.SwapEvent, error) {
+	type swap struct {
+		pair  *uniswapv2pair.Instance
+		block uint64
+		index uint
+	}
+	var swaps []swap
+	for _, p := range pairs {
+		pair := p.(*uniswapv2pair.Instance)
+		seen := make(map[uint64]bool)
+		for start := from; start <= to; start += chunk {
+			end := min(start+chunk-1, to)
+			it, err := pair.PairInterface.FilterSwap(&bind.FilterOpts{Start: start, End: &end, Context: ctx}, nil, nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read swaps of %s: %v", pair.Address(), err)
+			}
+			for it.Next() {
+				if !seen[it.Event.Raw.BlockNumber] {
+					seen[it.Event.Raw.BlockNumber] = true
+					swaps = append(swaps, swap{pair, it.Event.Raw.BlockNumber, it.Event.Raw.Index})
+				}
+			}
+			err = it.Error()
+			it.Close()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read swaps of %s: %v", pair.Address(), err)
+			}
+		}
+		logger.Info("read swaps", "dex", pair.DEX(), "pair", pair.Address(), "blocks", len(seen))
+	}
+	slices.SortFunc(swaps, func(a, b swap) int {
+		return cmp.Or(cmp.Compare(a.block, b.block), cmp.Compare(a.index, b.index))
+	})
+
+	events := make([]types.SwapEvent, 0, len(swaps))
+	for _, s := range swaps {
+		reserves, err := s.pair.PairInterface.GetReserves(&bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(s.block)})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read reserves of %s at block %d: %v", s.pair.Address(), s.block, err)
+		}
+		events = append(events, s.pair.SwapEventAt(uniswapv2pair.Reserves{Reserve0: reserves.Reserve0, Reserve1: reserves.Reserve1}, s.block))
+	}
+	return events, nil
+}
+
+// replayResult is the outcome of evaluating a stream of swap events.
+type replayResult struct {
+	Chain         string        `json:"chain"`
+	From          uint64        `json:"from"`
+	To            uint64        `json:"to"`
+	Pairs         int           `json:"pairs"`
+	Events        int           `json:"events"`
+	Opportunities []opportunity `json:"opportunities"`
+}
+
+// evaluate runs the strategy on every event in order, with the latest event
+// of each pair before it, as a pipeline does when the events arrive live.
+// Each evaluation sees the event's block as the head.
+func evaluate(ctx context.Context, chain config.Chain, pairs []types.Pair, events []types.SwapEvent, gasPrice *big.Int, logger *slog.Logger) replayResult {
+	clock := &replayClock{gasPrice: gasPrice}
+	s := pipeline.NewStrategy(chain, clock, pairs, logger)
+
+	result := replayResult{Chain: chain.Name, Pairs: len(pairs), Events: len(events), Opportunities: []opportunity{}}
+	var latest []types.SwapEvent
+	index := make(map[string]int)
+	for _, event := range events {
+		key := eventKey(event)
+		if i, ok := index[key]; ok {
+			latest[i] = event
+		} else {
+			index[key] = len(latest)
+			latest = append(latest, event)
+		}
+
+		clock.head = event.BlockNumber
+		if o := s.Evaluate(ctx, latest); o != nil {
+			result.Opportunities = append(result.Opportunities, *newOpportunity(o))
+		}
+	}
+	return result
+}
+
+func printRun(opts *options, result replayResult) error {
+	return opts.print(result, func(w io.Writer) {
+		fmt.Fprintf(w, "block\treturn\tgas\tpath\t\n")
+		for _, o := range result.Opportunities {
+			fmt.Fprintf(w, "%d\t%+.4f%%\t%.6f\t%s\t\n", o.Block, 100*o.Return, o.GasCost, strings.Join(o.Path, " > "))
+		}
+		fmt.Fprintf(w, "%s blocks %d-%d: %d swap events on %d pairs, %d opportunities\n", result.Chain, result.From, result.To, result.Events, result.Pairs, len(result.Opportunities))
+	})
+}
+
+// eventKey identifies the pair an event is about.
+func eventKey(e types.SwapEvent) string {
+	return e.DEXName + "|" + e.Address + "|" + e.Asset1Name + "|" + e.Asset2Name
+}
+
+// replayClock is the chain as of the event being replayed.
+type replayClock struct {
+	head     uint64
+	gasPrice *big.Int
+}
+
+func (c *replayClock) BlockNumber(ctx context.Context) (uint64, error) {
+	return c.head, nil
+}
+
+func (c *replayClock) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return new(big.Int).Set(c.gasPrice), nil
+}
+
+// recordedPair is a pair known only from its recorded events. It can be
+// evaluated but not monitored, quoted or traded.
+type recordedPair struct {
+	dex, address, asset1, asset2 string
+}
+
+// recordedPairs returns a pair for every pair the events are about.
+func recordedPairs(events []types.SwapEvent) []types.Pair {
+	seen := make(map[string]bool)
+	var pairs []types.Pair
+	for _, e := range events {
+		if key := eventKey(e); !seen[key] {
+			seen[key] = true
+			pairs = append(pairs, &recordedPair{dex: e.DEXName, address: e.Address, asset1: e.Asset1Name, asset2: e.Asset2Name})
+		}
+	}
+	return pairs
+}
+
+func (p *recordedPair) Asset1() string  { return p.asset1 }
+func (p *recordedPair) Asset2() string  { return p.asset2 }
+func (p *recordedPair) DEX() string     { return p.dex }
+func (p *recordedPair) Address() string { return p.address }
+
+func (p *recordedPair) Monitor(ctx context.Context, swapEventChan chan<- types.SwapEvent) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (p *recordedPair) SwapCalls(executor, tokenIn common.Address, assetIn string, minOut *big.Int) ([]types.Call, error) {
+	return nil, fmt.Errorf("recorded pair %s cannot be traded", p.address)
+}
+
+func (p *recordedPair) Quote(assetIn string, amountIn *big.Int) (*big.Int, error) {
+	return nil, fmt.Errorf("recorded pair %s cannot be quoted", p.address)
+}
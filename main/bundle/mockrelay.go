@@ -0,0 +1,191 @@
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Backend receives the transactions of bundles the mock relay includes, e.g.
+// a simulated backend.
+type Backend interface {
+	SendTransaction(ctx context.Context, tx *ethtypes.Transaction) error
+}
+
+// ReceivedBundle is a bundle as recorded by the mock relay.
+type ReceivedBundle struct {
+	Method      string
+	Signer      common.Address
+	Txs         []*ethtypes.Transaction
+	BlockNumber uint64
+}
+
+// MockRelay is a local stand-in for a relay, served over HTTP. It checks
+// request signatures and records every bundle. Bundles sent with
+// eth_sendBundle are included by passing their transactions to Backend, if
+// set, unless one of them is scripted to revert; resubmitted transactions are
+// only passed on once. eth_callBundle does not
+// execute anything: each transaction reports its gas limit as gas used.
+type MockRelay struct {
+	Backend Backend
+
+	mu      sync.Mutex
+	bundles []ReceivedBundle
+	reverts map[common.Hash]string
+	sent    map[common.Hash]bool
+}
+
+// NewMockRelay creates a mock relay including bundles in backend, which may
+// be nil to only record them.
+func NewMockRelay(backend Backend) *MockRelay {
+	return &MockRelay{Backend: backend, reverts: make(map[common.Hash]string), sent: make(map[common.Hash]bool)}
+}
+
+// Revert scripts a transaction to revert with reason in simulations, which
+// also keeps bundles containing it from being included.
+func (m *MockRelay) Revert(txHash common.Hash, reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reverts[txHash] = reason
+}
+
+// Bundles returns the bundles received so far.
+func (m *MockRelay) Bundles() []ReceivedBundle {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]ReceivedBundle(nil), m.bundles...)
+}
+
+func (m *MockRelay) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	signer, err := Verify(body, r.Header.Get(SignatureHeader))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	var req struct {
+		ID     uint64         `json:"id"`
+		Method string         `json:"method"`
+		Params []bundleParams `json:"params"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil || len(req.Params) != 1 {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	received, err := decodeBundle(req.Method, signer, req.Params[0])
+	var result interface{}
+	if err == nil {
+		switch req.Method {
+		case "eth_sendBundle":
+			result, err = m.send(r.Context(), received)
+		case "eth_callBundle":
+			result = m.call(received)
+		default:
+			err = fmt.Errorf("method %s not supported", req.Method)
+		}
+	}
+
+	res := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+	if err != nil {
+		res["error"] = map[string]interface{}{"code": -32000, "message": err.Error()}
+	} else {
+		res["result"] = result
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func decodeBundle(method string, signer common.Address, params bundleParams) (ReceivedBundle, error) {
+	received := ReceivedBundle{Method: method, Signer: signer}
+	block, err := hexutil.DecodeUint64(params.BlockNumber)
+	if err != nil {
+		return received, fmt.Errorf("invalid blockNumber: %v", err)
+	}
+	received.BlockNumber = block
+	for _, raw := range params.Txs {
+		data, err := hexutil.Decode(raw)
+		if err != nil {
+			return received, fmt.Errorf("invalid tx: %v", err)
+		}
+		tx := new(ethtypes.Transaction)
+		if err := tx.UnmarshalBinary(data); err != nil {
+			return received, fmt.Errorf("invalid tx: %v", err)
+		}
+		received.Txs = append(received.Txs, tx)
+	}
+	if len(received.Txs) == 0 {
+		return received, fmt.Errorf("empty bundle")
+	}
+	return received, nil
+}
+
+func (m *MockRelay) send(ctx context.Context, received ReceivedBundle) (interface{}, error) {
+	m.mu.Lock()
+	m.bundles = append(m.bundles, received)
+	reverts := false
+	for _, tx := range received.Txs {
+		if _, ok := m.reverts[tx.Hash()]; ok {
+			reverts = true
+		}
+	}
+	var include []*ethtypes.Transaction
+	if m.Backend != nil && !reverts {
+		for _, tx := range received.Txs {
+			if !m.sent[tx.Hash()] {
+				m.sent[tx.Hash()] = true
+				include = append(include, tx)
+			}
+		}
+	}
+	m.mu.Unlock()
+
+	for _, tx := range include {
+		if err := m.Backend.SendTransaction(ctx, tx); err != nil {
+			return nil, fmt.Errorf("failed to include %s: %v", tx.Hash().Hex(), err)
+		}
+	}
+	return map[string]interface{}{"bundleHash": bundleHash(received.Txs)}, nil
+}
+
+func (m *MockRelay) call(received ReceivedBundle) *CallResult {
+	m.mu.Lock()
+	m.bundles = append(m.bundles, received)
+	defer m.mu.Unlock()
+
+	result := &CallResult{
+		BundleHash:       bundleHash(received.Txs),
+		CoinbaseDiff:     "0",
+		GasFees:          "0",
+		StateBlockNumber: received.BlockNumber - 1,
+	}
+	for _, tx := range received.Txs {
+		tx := TxResult{TxHash: tx.Hash(), GasUsed: tx.Gas(), Revert: m.reverts[tx.Hash()]}
+		result.TotalGasUsed += tx.GasUsed
+		result.Results = append(result.Results, tx)
+	}
+	return result
+}
+
+// bundleHash is the keccak256 of the concatenated transaction hashes, as
+// relays compute it.
+func bundleHash(txs []*ethtypes.Transaction) common.Hash {
+	var hashes []byte
+	for _, tx := range txs {
+		hashes = append(hashes, tx.Hash().Bytes()...)
+	}
+	return crypto.Keccak256Hash(hashes)
+}
@@ -0,0 +1,220 @@
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Bundle is a list of signed transactions to be included, in order and all
+// or nothing, in BlockNumber.
+type Bundle struct {
+	Txs         []*ethtypes.Transaction
+	BlockNumber uint64
+}
+
+// CallResult is a relay's simulation of a bundle. Wei amounts are decimal
+// strings, as relays return them.
+type CallResult struct {
+	BundleHash       common.Hash `json:"bundleHash"`
+	CoinbaseDiff     string      `json:"coinbaseDiff"`
+	GasFees          string      `json:"gasFees"`
+	TotalGasUsed     uint64      `json:"totalGasUsed"`
+	StateBlockNumber uint64      `json:"stateBlockNumber"`
+	Results          []TxResult  `json:"results"`
+}
+
+// Reverted returns the first transaction that failed in the simulation.
+func (c *CallResult) Reverted() (TxResult, bool) {
+	for _, tx := range c.Results {
+		if tx.Error != "" || tx.Revert != "" {
+			return tx, true
+		}
+	}
+	return TxResult{}, false
+}
+
+// TxResult is the simulated outcome of one transaction of a bundle.
+type TxResult struct {
+	TxHash  common.Hash `json:"txHash"`
+	GasUsed uint64      `json:"gasUsed"`
+	Error   string      `json:"error,omitempty"`
+	Revert  string      `json:"revert,omitempty"`
+}
+
+// Relay sends bundles to a Flashbots-compatible relay. Requests are signed
+// with AuthKey, which identifies the searcher to the relay and need not hold
+// funds.
+type Relay struct {
+	URL     string
+	AuthKey *ecdsa.PrivateKey
+	HTTP    *http.Client
+
+	id atomic.Uint64
+}
+
+// NewRelay creates a relay client. A nil authKey signs with a fresh random
+// key.
+func NewRelay(url string, authKey *ecdsa.PrivateKey) (*Relay, error) {
+	if authKey == nil {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate relay auth key: %v", err)
+		}
+		authKey = key
+	}
+	return &Relay{URL: url, AuthKey: authKey, HTTP: http.DefaultClient}, nil
+}
+
+type request struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type response struct {
+	ID     uint64          `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type bundleParams struct {
+	Txs              []string `json:"txs"`
+	BlockNumber      string   `json:"blockNumber"`
+	StateBlockNumber string   `json:"stateBlockNumber,omitempty"`
+}
+
+func (b *Bundle) params() (bundleParams, error) {
+	p := bundleParams{BlockNumber: hexutil.EncodeUint64(b.BlockNumber)}
+	for _, tx := range b.Txs {
+		raw, err := tx.MarshalBinary()
+		if err != nil {
+			return p, fmt.Errorf("failed to encode tx %s: %v", tx.Hash().Hex(), err)
+		}
+		p.Txs = append(p.Txs, hexutil.Encode(raw))
+	}
+	return p, nil
+}
+
+// SendBundle submits a bundle for its target block and returns its hash.
+func (r *Relay) SendBundle(ctx context.Context, b *Bundle) (common.Hash, error) {
+	params, err := b.params()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	var result struct {
+		BundleHash common.Hash `json:"bundleHash"`
+	}
+	if err := r.call(ctx, "eth_sendBundle", params, &result); err != nil {
+		return common.Hash{}, err
+	}
+	return result.BundleHash, nil
+}
+
+// CallBundle simulates a bundle on top of stateBlock ("latest" if empty).
+func (r *Relay) CallBundle(ctx context.Context, b *Bundle, stateBlock string) (*CallResult, error) {
+	params, err := b.params()
+	if err != nil {
+		return nil, err
+	}
+	params.StateBlockNumber = stateBlock
+	if params.StateBlockNumber == "" {
+		params.StateBlockNumber = "latest"
+	}
+
+	result := &CallResult{}
+	if err := r.call(ctx, "eth_callBundle", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *Relay) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	body, err := json.Marshal(request{JSONRPC: "2.0", ID: r.id.Add(1), Method: method, Params: []interface{}{params}})
+	if err != nil {
+		return err
+	}
+	signature, err := Sign(body, r.AuthKey)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, signature)
+
+	res, err := r.HTTP.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s failed: %v", method, err)
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("%s failed: %v", method, err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s failed: %s: %s", method, res.Status, bytes.TrimSpace(data))
+	}
+
+	var rpcRes response
+	if err := json.Unmarshal(data, &rpcRes); err != nil {
+		return fmt.Errorf("%s returned invalid JSON: %v", method, err)
+	}
+	if rpcRes.Error != nil {
+		return fmt.Errorf("%s failed: %s (%d)", method, rpcRes.Error.Message, rpcRes.Error.Code)
+	}
+	return json.Unmarshal(rpcRes.Result, result)
+}
+
+// SignatureHeader carries the searcher's signature of a relay request.
+const SignatureHeader = "X-Flashbots-Signature"
+
+// Sign returns the signature header value for a request body: the signer's
+// address and its EIP-191 signature of the hex keccak256 of the body.
+func Sign(body []byte, key *ecdsa.PrivateKey) (string, error) {
+	hash := crypto.Keccak256Hash(body).Hex()
+	signature, err := crypto.Sign(accounts.TextHash([]byte(hash)), key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign relay request: %v", err)
+	}
+	return crypto.PubkeyToAddress(key.PublicKey).Hex() + ":" + hexutil.Encode(signature), nil
+}
+
+// Verify checks a signature header against a request body and returns the
+// signer.
+func Verify(body []byte, header string) (common.Address, error) {
+	address, signature, _ := strings.Cut(header, ":")
+	sig, err := hexutil.Decode(signature)
+	if err != nil || len(sig) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("malformed signature")
+	}
+	hash := crypto.Keccak256Hash(body).Hex()
+	pub, err := crypto.SigToPub(accounts.TextHash([]byte(hash)), sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid signature: %v", err)
+	}
+	signer := crypto.PubkeyToAddress(*pub)
+	if !common.IsHexAddress(address) || common.HexToAddress(address) != signer {
+		return common.Address{}, fmt.Errorf("signature does not match %s", address)
+	}
+	return signer, nil
+}
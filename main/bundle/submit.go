@@ -0,0 +1,174 @@
+package bundle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// ChainReader is the chain access needed to target blocks and find receipts.
+type ChainReader interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*ethtypes.Receipt, error)
+}
+
+// Submitter sends a bundle for consecutive target blocks until it is
+// included or MaxBlocks blocks have passed.
+type Submitter struct {
+	Relay     *Relay
+	Chain     ChainReader
+	MaxBlocks uint64 // target blocks to try
+	Retries   int    // resends of a bundle for one block after relay errors
+	Simulate  bool   // eth_callBundle before the first submission
+	Poll      time.Duration
+	Log       *slog.Logger
+}
+
+// Inclusion is the outcome of a submission.
+type Inclusion struct {
+	BundleHash  common.Hash
+	Included    bool
+	BlockNumber uint64
+	Attempts    int
+	Receipts    []*ethtypes.Receipt
+}
+
+// NewSubmitter creates a submitter with the given number of target blocks;
+// zero tries a single block.
+func NewSubmitter(relay *Relay, chain ChainReader, maxBlocks uint64, simulate bool, logger *slog.Logger) *Submitter {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if maxBlocks == 0 {
+		maxBlocks = 1
+	}
+	return &Submitter{
+		Relay:     relay,
+		Chain:     chain,
+		MaxBlocks: maxBlocks,
+		Retries:   2,
+		Simulate:  simulate,
+		Poll:      time.Second,
+		Log:       logger,
+	}
+}
+
+// ErrReverted is returned when the relay simulation of a bundle fails.
+var ErrReverted = errors.New("bundle reverts in simulation")
+
+// Submit sends txs as a bundle targeting the blocks after the current head,
+// one block at a time, and reports whether and where it was included.
+func (s *Submitter) Submit(ctx context.Context, txs []*ethtypes.Transaction) (*Inclusion, error) {
+	if len(txs) == 0 {
+		return nil, fmt.Errorf("empty bundle")
+	}
+	head, err := s.Chain.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch head block: %v", err)
+	}
+
+	if s.Simulate {
+		result, err := s.Relay.CallBundle(ctx, &Bundle{Txs: txs, BlockNumber: head + 1}, "latest")
+		if err != nil {
+			return nil, err
+		}
+		if tx, reverted := result.Reverted(); reverted {
+			return nil, fmt.Errorf("%w: tx %s: %s%s", ErrReverted, tx.TxHash.Hex(), tx.Error, tx.Revert)
+		}
+		s.Log.InfoContext(ctx, "bundle simulated", "gasUsed", result.TotalGasUsed, "coinbaseDiff", result.CoinbaseDiff)
+	}
+
+	inclusion := &Inclusion{}
+	for target := head + 1; target <= head+s.MaxBlocks; target++ {
+		bundle := &Bundle{Txs: txs, BlockNumber: target}
+		inclusion.Attempts++
+		inclusion.BundleHash, err = s.send(ctx, bundle)
+		if err != nil {
+			return inclusion, err
+		}
+		s.Log.InfoContext(ctx, "bundle submitted", "bundle", inclusion.BundleHash.Hex(), "block", target)
+
+		if err := s.waitFor(ctx, target); err != nil {
+			return inclusion, err
+		}
+		receipts, err := s.receipts(ctx, txs)
+		if err != nil {
+			return inclusion, err
+		}
+		if receipts != nil {
+			inclusion.Included = true
+			inclusion.BlockNumber = receipts[0].BlockNumber.Uint64()
+			inclusion.Receipts = receipts
+			s.Log.InfoContext(ctx, "bundle included", "bundle", inclusion.BundleHash.Hex(), "block", inclusion.BlockNumber)
+			return inclusion, nil
+		}
+	}
+
+	s.Log.InfoContext(ctx, "bundle not included", "bundle", inclusion.BundleHash.Hex(), "blocks", inclusion.Attempts)
+	return inclusion, nil
+}
+
+// Send submits a single transaction as a bundle and reports whether it was
+// included, as the private sender of a transaction manager.
+func (s *Submitter) Send(ctx context.Context, tx *ethtypes.Transaction) (bool, error) {
+	inclusion, err := s.Submit(ctx, []*ethtypes.Transaction{tx})
+	if err != nil {
+		return false, err
+	}
+	return inclusion.Included, nil
+}
+
+func (s *Submitter) send(ctx context.Context, bundle *Bundle) (common.Hash, error) {
+	var err error
+	for attempt := 0; attempt <= s.Retries; attempt++ {
+		var hash common.Hash
+		if hash, err = s.Relay.SendBundle(ctx, bundle); err == nil {
+			return hash, nil
+		}
+		s.Log.WarnContext(ctx, "bundle submission failed", "block", bundle.BlockNumber, "attempt", attempt+1, "err", err)
+	}
+	return common.Hash{}, err
+}
+
+// waitFor blocks until the chain has reached block target.
+func (s *Submitter) waitFor(ctx context.Context, target uint64) error {
+	ticker := time.NewTicker(s.Poll)
+	defer ticker.Stop()
+	for {
+		head, err := s.Chain.BlockNumber(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch head block: %v", err)
+		}
+		if head >= target {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// receipts returns the receipts of all txs, or nil if the bundle has not
+// landed.
+func (s *Submitter) receipts(ctx context.Context, txs []*ethtypes.Transaction) ([]*ethtypes.Receipt, error) {
+	receipts := make([]*ethtypes.Receipt, 0, len(txs))
+	for _, tx := range txs {
+		receipt, err := s.Chain.TransactionReceipt(ctx, tx.Hash())
+		if errors.Is(err, ethereum.NotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch receipt of %s: %v", tx.Hash().Hex(), err)
+		}
+		receipts = append(receipts, receipt)
+	}
+	return receipts, nil
+}
@@ -0,0 +1,135 @@
+package bundle
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"bb/testutil"
+	"bb/txmanager"
+)
+
+// publicCounter counts the transactions sent to the node directly.
+type publicCounter struct {
+	txmanager.Backend
+	sent atomic.Int32
+}
+
+func (p *publicCounter) SendTransaction(ctx context.Context, tx *ethtypes.Transaction) error {
+	p.sent.Add(1)
+	return p.Backend.SendTransaction(ctx, tx)
+}
+
+// TestPrivateTransactions sends a transaction manager's transactions through
+// a submitter and a mock relay including bundles on a simulated chain: none
+// goes to the node, and one the relay's simulation reverts is dropped and
+// its nonce used by the next.
+func TestPrivateTransactions(t *testing.T) {
+	chain := testutil.NewChain(t)
+	relay := NewMockRelay(chain.Client)
+	server := httptest.NewServer(relay)
+	defer server.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	client, err := NewRelay(server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	submitter := NewSubmitter(client, chain.Client, 3, true, logger)
+	submitter.Poll = 10 * time.Millisecond
+
+	node := &publicCounter{Backend: chain.Client}
+	manager := txmanager.NewManager(node, chain.Auth, logger)
+	manager.Private = submitter
+	manager.Poll = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go manager.Run(ctx)
+	go func() {
+		ticker := time.NewTicker(20 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				chain.Backend.Commit()
+			}
+		}
+	}()
+
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	transfer := func(value int64, revert bool) func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+			signed, err := opts.Signer(opts.From, ethtypes.NewTx(&ethtypes.DynamicFeeTx{
+				Nonce:     opts.Nonce.Uint64(),
+				GasTipCap: opts.GasTipCap,
+				GasFeeCap: opts.GasFeeCap,
+				Gas:       21000,
+				To:        &to,
+				Value:     big.NewInt(value),
+			}))
+			if err == nil && revert {
+				relay.Revert(signed.Hash(), "scripted")
+			}
+			return signed, err
+		}
+	}
+	wait := func(tx *txmanager.Tx, want txmanager.Status) {
+		t.Helper()
+		waitCtx, stop := context.WithTimeout(ctx, 5*time.Second)
+		defer stop()
+		if status, err := tx.Wait(waitCtx); err != nil || status != want {
+			t.Fatalf("%s: %s %v, want %s", tx.Current().Hash().Hex(), status, err, want)
+		}
+	}
+
+	reverted, err := manager.Transact(ctx, "reverts", transfer(1, true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wait(reverted, txmanager.Dropped)
+
+	mined, err := manager.Transact(ctx, "transfer", transfer(2, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wait(mined, txmanager.Mined)
+	if mined.Current().Nonce() != reverted.Current().Nonce() {
+		t.Errorf("mined at nonce %d, dropped one had %d", mined.Current().Nonce(), reverted.Current().Nonce())
+	}
+
+	if n := node.sent.Load(); n != 0 {
+		t.Errorf("%d transactions sent to the node", n)
+	}
+	var simulated, sent int
+	for _, b := range relay.Bundles() {
+		if len(b.Txs) != 1 {
+			t.Fatalf("bundle of %d transactions", len(b.Txs))
+		}
+		switch {
+		case b.Method == "eth_callBundle":
+			simulated++
+		case b.Txs[0].Hash() == mined.Hash():
+			sent++
+		default:
+			t.Errorf("%s of %s", b.Method, b.Txs[0].Hash().Hex())
+		}
+	}
+	if simulated != 2 || sent == 0 {
+		t.Errorf("%d simulated, %d sent", simulated, sent)
+	}
+	if balance, err := chain.Client.BalanceAt(ctx, to, nil); err != nil || balance.Cmp(big.NewInt(2)) != 0 {
+		t.Errorf("recipient holds %s %v", balance, err)
+	}
+}
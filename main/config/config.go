@@ -0,0 +1,386 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+)
+
+// Config holds the settings that do not belong in the environment file.
+type Config struct {
+	Chains []Chain `json:"chains"`
+
+	// Ledger is the file every simulated and executed cycle is appended to.
+	Ledger string `json:"ledger"`
+
+	// MetricsAddr, if set, is the address serving Prometheus metrics at
+	// /metrics, e.g. ":9100".
+	MetricsAddr string `json:"metricsAddr,omitempty"`
+
+	// APIAddr, if set, is the address serving the control and query API at
+	// /api/, e.g. "127.0.0.1:8080". Requests that change anything need
+	// APIToken as a bearer token, which is required with APIAddr; ${VAR} is
+	// expanded from the environment.
+	APIAddr  string `json:"apiAddr,omitempty"`
+	APIToken string `json:"apiToken,omitempty"`
+
+	Log Log `json:"log"`
+
+	// Alerts, if set, notifies operators of notable events.
+	Alerts *Alerts `json:"alerts,omitempty"`
+
+	Shutdown Shutdown `json:"shutdown"`
+}
+
+// Shutdown policies for the trades in flight when bb is stopped.
+const (
+	ShutdownWait   = "wait"   // let them reach a final state
+	ShutdownCancel = "cancel" // replace them with cancellations first
+)
+
+// Shutdown configures what happens on SIGINT or SIGTERM. New evaluations stop
+// at once; the trades in flight are handled by Policy for up to
+// TimeoutSeconds, after which whatever is still pending is reported and left.
+type Shutdown struct {
+	Policy         string `json:"policy"`         // "wait" (the default) or "cancel"
+	TimeoutSeconds int    `json:"timeoutSeconds"` // default 120
+}
+
+// Alerts configures the alert sinks and when alerts fire. URLs, tokens and
+// chat IDs expand ${VAR} from the environment; sinks left without a URL or
+// token are dropped.
+type Alerts struct {
+	Webhooks []string   `json:"webhooks,omitempty"` // receive alerts as JSON objects
+	Slack    []string   `json:"slack,omitempty"`    // Slack incoming webhook URLs
+	Telegram []Telegram `json:"telegram,omitempty"`
+
+	MinOpportunityUSD float64 `json:"minOpportunityUSD"` // 0 for no opportunity alerts
+	OutageSeconds     int     `json:"outageSeconds"`     // subscription downtime before an alert
+	DedupSeconds      int     `json:"dedupSeconds"`      // window in which repeated alerts are dropped
+	MaxPerMinute      int     `json:"maxPerMinute"`      // critical alerts are never limited
+}
+
+// Telegram is a Telegram bot and the chat it posts to.
+type Telegram struct {
+	URL    string `json:"url,omitempty"` // Bot API server, api.telegram.org by default
+	Token  string `json:"token"`
+	ChatID string `json:"chatId"`
+}
+
+// Log configures the log output.
+type Log struct {
+	Format string `json:"format"` // "text" (the default) or "json"
+	Level  string `json:"level"`  // "debug", "info" (the default), "warn" or "error"
+}
+
+// Chain is one EVM chain to run the strategy on. Each chain gets its own
+// clients, pairs, filters and gas model.
+type Chain struct {
+	Name     string         `json:"name"`
+	ChainID  int64          `json:"chainId"`
+	NodeURLs []string       `json:"nodeUrls"` // ${VAR} is expanded from the environment
+	DEXes    map[string]DEX `json:"dexes"`    // keyed by DEX name
+	Pairs    []Pair         `json:"pairs"`
+	Filters  Filters        `json:"filters"`
+	Gas      Gas            `json:"gas"`
+	Risk     Risk           `json:"risk"`
+
+	// NativeAsset names the asset gas is paid in, to value gas in USD.
+	NativeAsset string `json:"nativeAsset,omitempty"`
+
+	// Tokens lists the ERC-20 tokens held for trading, keyed by asset. The
+	// tokens of V2 fork pairs are found from the pairs and need not be listed.
+	Tokens map[string]Token `json:"tokens,omitempty"`
+
+	// Mempool simulates pending swaps on the chain's V2 pairs to evaluate
+	// backruns before they are mined. It needs a node that streams full
+	// pending transactions.
+	Mempool bool `json:"mempool,omitempty"`
+
+	// Relay, if set, sends trades as private bundles instead of through the
+	// public mempool.
+	Relay *Relay `json:"relay,omitempty"`
+
+	// Executor is the address of an Executor contract owned by the trading
+	// account, deployed with bb deploy-executor. Every swap goes through it,
+	// so that a route either completes in one transaction or not at all.
+	Executor string `json:"executor,omitempty"`
+
+	// Rebalance, if set, swaps the inventory back toward target weights.
+	Rebalance *Rebalance `json:"rebalance,omitempty"`
+
+	// GraphExport, if set, writes snapshots of the graph to files.
+	GraphExport *GraphExport `json:"graphExport,omitempty"`
+}
+
+// GraphExport snapshots the graph every so many blocks.
+type GraphExport struct {
+	Dir         string   `json:"dir"`         // created if missing
+	EveryBlocks uint64   `json:"everyBlocks"` // first evaluation at or past each multiple
+	Formats     []string `json:"formats"`     // "dot" and/or "json"
+}
+
+// Rebalance keeps the inventory near target weights by USD value.
+type Rebalance struct {
+	Targets         map[string]float64 `json:"targets"`         // weight by asset, normalized to sum to one
+	Threshold       float64            `json:"threshold"`       // drift of any weight from its target that triggers a rebalance
+	MinTradeUSD     float64            `json:"minTradeUSD"`     // smaller swaps are not worth the gas
+	MaxHops         int                `json:"maxHops"`         // longest route considered
+	Slippage        float64            `json:"slippage"`        // fraction of a route's quote it may fall short by
+	IntervalSeconds int                `json:"intervalSeconds"` // between checks
+	DryRun          bool               `json:"dryRun"`          // log the plan instead of trading
+}
+
+// Relay is a Flashbots-compatible bundle relay.
+type Relay struct {
+	URL       string `json:"url"`
+	AuthKey   string `json:"authKey,omitempty"`   // hex key signing relay requests; ${VAR} is expanded, random if empty
+	MaxBlocks uint64 `json:"maxBlocks,omitempty"` // target blocks to try per bundle
+	Simulate  bool   `json:"simulate,omitempty"`  // eth_callBundle before submitting
+}
+
+// Token is an ERC-20 token.
+type Token struct {
+	Address  string `json:"address"`
+	Decimals int64  `json:"decimals"`
+}
+
+// Gas is the gas model used to cost a cycle on a chain, and the margin by
+// which a trade's quoted gain must beat that cost.
+type Gas struct {
+	BaseGas         uint64  `json:"baseGas"`
+	GasPerHop       uint64  `json:"gasPerHop"`
+	PriceMultiplier float64 `json:"priceMultiplier"`
+	MinProfitUSD    float64 `json:"minProfitUSD"`
+}
+
+// Risk limits live trading on a chain. MaxNotionalUSD and MaxDailyLossUSD
+// are required to trade; the other limits are not enforced at zero.
+type Risk struct {
+	MaxNotionalUSD        float64            `json:"maxNotionalUSD"`
+	MaxDailyLossUSD       float64            `json:"maxDailyLossUSD"`
+	MaxGasPerHour         float64            `json:"maxGasPerHour"` // in the native token
+	MaxConsecutiveReverts int                `json:"maxConsecutiveReverts"`
+	MaxExposureUSD        map[string]float64 `json:"maxExposureUSD"` // by asset
+	StateFile             string             `json:"stateFile"`      // keeps the kill switch across restarts
+}
+
+// DEX kinds. Every Uniswap V2 fork uses KindUniswapV2.
+const (
+	KindUniswapV2 = "uniswapv2"
+	KindUniswapV3 = "uniswapv3"
+	KindCurve     = "curve"
+	KindBalancer  = "balancer"
+)
+
+// DefaultV2FeePips is the fee of a Uniswap V2 fork that sets neither a fee
+// nor a fee method: 0.3%, the fee of Uniswap V2 itself.
+const DefaultV2FeePips = 3000
+
+// DEX describes an exchange. Factory, InitCodeHash, Router and the fee
+// settings only apply to Uniswap V2 forks; the other kinds read their fees from
+// the pools. Router is used to decode pending swaps.
+type DEX struct {
+	Kind         string `json:"kind"`
+	Factory      string `json:"factory,omitempty"`
+	InitCodeHash string `json:"initCodeHash,omitempty"`
+	Router       string `json:"router,omitempty"`
+	Fee
+
+	// FeeMethod names a view on the pair returning its fee in units of
+	// 1/FeeDenominator, for forks that store fees on-chain. DynamicFee
+	// re-reads it on every swap.
+	FeeMethod      string `json:"feeMethod,omitempty"`
+	FeeDenominator int64  `json:"feeDenominator,omitempty"`
+	DynamicFee     bool   `json:"dynamicFee,omitempty"`
+}
+
+// Fee is a swap fee given either in basis points or in pips (hundredths of a
+// basis point). Pips take precedence when both are set.
+type Fee struct {
+	FeeBps  float64 `json:"feeBps,omitempty"`
+	FeePips int64   `json:"feePips,omitempty"`
+}
+
+// Pips returns the fee in pips, or 0 if it is not set.
+func (f Fee) Pips() int64 {
+	if f.FeePips != 0 {
+		return f.FeePips
+	}
+	return int64(math.Round(f.FeeBps * 100))
+}
+
+// Pair is a pool to monitor. Assets and Decimals are in pool token order and
+// may list more than two coins for Curve and Balancer pools. V2 fork pairs may
+// give Tokens instead of Address to derive it from the factory, and a Fee
+// overriding the DEX fee. A pair's Fee also wins over the DEX's fee method:
+// the pair's fee is then never read on-chain.
+type Pair struct {
+	DEX      string   `json:"dex"`
+	Address  string   `json:"address,omitempty"`
+	Tokens   []string `json:"tokens,omitempty"`
+	Assets   []string `json:"assets"`
+	Decimals []int64  `json:"decimals"`
+	Fee
+}
+
+// Filters configures which pairs are admitted to the arbitrage graph.
+type Filters struct {
+	MinLiquidityUSD float64             `json:"minLiquidityUSD"`
+	MaxStaleBlocks  uint64              `json:"maxStaleBlocks"`
+	StableAssets    []string            `json:"stableAssets"`
+	Pairs           map[string]PairRule `json:"pairs"` // keyed by pair address
+}
+
+// PairRule overrides the global filters for a single pair.
+type PairRule struct {
+	MinLiquidityUSD float64 `json:"minLiquidityUSD"`
+	MaxStaleBlocks  uint64  `json:"maxStaleBlocks"`
+}
+
+// defaultStableAssets are valued at one USD when a chain lists none.
+var defaultStableAssets = []string{"USDT", "USDC", "DAI"}
+
+// Load reads a JSON config file, expands environment variables in node URLs
+// and fills in per-chain defaults.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %v", err)
+	}
+
+	cfg := &Config{}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %v", path, err)
+	}
+
+	if cfg.Ledger == "" {
+		cfg.Ledger = "ledger.jsonl"
+	}
+	cfg.APIToken = os.ExpandEnv(cfg.APIToken)
+	if cfg.APIAddr != "" && cfg.APIToken == "" {
+		return nil, fmt.Errorf("apiAddr is set without an apiToken to authenticate control requests")
+	}
+	if a := cfg.Alerts; a != nil {
+		a.Webhooks = expandAll(a.Webhooks)
+		a.Slack = expandAll(a.Slack)
+		telegram := a.Telegram[:0]
+		for _, t := range a.Telegram {
+			t.URL, t.Token, t.ChatID = os.ExpandEnv(t.URL), os.ExpandEnv(t.Token), os.ExpandEnv(t.ChatID)
+			if t.Token != "" {
+				telegram = append(telegram, t)
+			}
+		}
+		a.Telegram = telegram
+		if a.OutageSeconds == 0 {
+			a.OutageSeconds = 120
+		}
+		if a.DedupSeconds == 0 {
+			a.DedupSeconds = 300
+		}
+		if a.MaxPerMinute == 0 {
+			a.MaxPerMinute = 10
+		}
+	}
+	if cfg.Shutdown.Policy == "" {
+		cfg.Shutdown.Policy = ShutdownWait
+	}
+	if cfg.Shutdown.Policy != ShutdownWait && cfg.Shutdown.Policy != ShutdownCancel {
+		return nil, fmt.Errorf("unknown shutdown policy %q, expected wait or cancel", cfg.Shutdown.Policy)
+	}
+	if cfg.Shutdown.TimeoutSeconds == 0 {
+		cfg.Shutdown.TimeoutSeconds = 120
+	}
+	if cfg.Log.Format == "" {
+		cfg.Log.Format = "text"
+	}
+	if cfg.Log.Level == "" {
+		cfg.Log.Level = "info"
+	}
+	for i := range cfg.Chains {
+		chain := &cfg.Chains[i]
+		if chain.Name == "" {
+			return nil, fmt.Errorf("chain %d in %s has no name", i, path)
+		}
+		chain.NodeURLs = expandAll(chain.NodeURLs)
+		if chain.Relay != nil {
+			chain.Relay.URL = os.ExpandEnv(chain.Relay.URL)
+			chain.Relay.AuthKey = os.ExpandEnv(chain.Relay.AuthKey)
+		}
+		if len(chain.Filters.StableAssets) == 0 {
+			chain.Filters.StableAssets = defaultStableAssets
+		}
+		if chain.NativeAsset == "" {
+			chain.NativeAsset = "ETH"
+		}
+		if chain.Risk.StateFile == "" {
+			chain.Risk.StateFile = fmt.Sprintf("risk-%s.json", chain.Name)
+		}
+		if r := chain.Rebalance; r != nil {
+			if r.Threshold == 0 {
+				r.Threshold = 0.05
+			}
+			if r.MaxHops == 0 {
+				r.MaxHops = 3
+			}
+			if r.Slippage == 0 {
+				r.Slippage = 0.005
+			}
+			if r.Slippage < 0 || r.Slippage >= 1 {
+				return nil, fmt.Errorf("chain %s rebalances with slippage %g, expected a fraction below one", chain.Name, r.Slippage)
+			}
+			if !r.DryRun && chain.Executor == "" {
+				return nil, fmt.Errorf("chain %s rebalances without an executor to swap through", chain.Name)
+			}
+			if r.IntervalSeconds == 0 {
+				r.IntervalSeconds = 300
+			}
+		}
+		if e := chain.GraphExport; e != nil {
+			if e.Dir == "" {
+				e.Dir = "graphs"
+			}
+			if e.EveryBlocks == 0 {
+				e.EveryBlocks = 100
+			}
+			if len(e.Formats) == 0 {
+				e.Formats = []string{"dot", "json"}
+			}
+			for _, format := range e.Formats {
+				if format != "dot" && format != "json" {
+					return nil, fmt.Errorf("chain %s exports graphs as %q, expected dot or json", chain.Name, format)
+				}
+			}
+		}
+		for name, dex := range chain.DEXes {
+			// V2 forks without a configured or on-chain fee charge the
+			// original Uniswap V2 fee rather than none at all.
+			if dex.Kind == KindUniswapV2 && dex.Pips() == 0 && dex.FeeMethod == "" {
+				dex.FeePips = DefaultV2FeePips
+				chain.DEXes[name] = dex
+			}
+		}
+		if chain.Gas.BaseGas == 0 {
+			chain.Gas.BaseGas = 21000
+		}
+		if chain.Gas.GasPerHop == 0 {
+			chain.Gas.GasPerHop = 100000
+		}
+		if chain.Gas.MinProfitUSD < 0 {
+			return nil, fmt.Errorf("chain %s has a negative minProfitUSD", chain.Name)
+		}
+	}
+	return cfg, nil
+}
+
+// expandAll expands ${VAR} in every string, dropping those left empty.
+func expandAll(values []string) []string {
+	expanded := values[:0]
+	for _, value := range values {
+		if value = os.ExpandEnv(value); value != "" {
+			expanded = append(expanded, value)
+		}
+	}
+	return expanded
+}
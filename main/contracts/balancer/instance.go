@@ -0,0 +1,460 @@
+package balancerpool
+
+// using same package as vault.go and weightedpool.go generated with abigen
+// from Vault.abi and WeightedPool.abi
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	"bb/executor"
+	"bb/types"
+)
+
+const (
+	swapKindGivenIn = 0
+
+	// VaultAddress is the Balancer V2 Vault, deployed at the same address on
+	// every supported chain.
+	VaultAddress = "0xBA12222222228d8Ba445958a75a0704d566BF2C8"
+)
+
+// Pool is a Balancer V2 weighted pool. Balances live in the Vault, so the pool
+// follows the Vault's Swap and PoolBalanceChanged events for its pool id.
+// Every token pair in the pool is exposed as an Edge through Pairs.
+type Pool struct {
+	AddressString string
+	Client        bind.ContractBackend
+	PoolInterface *Weightedpool
+	Vault         *Vault
+	DEXName       string
+	AssetNames    []string
+	AssetDecimals []int64
+	PoolId        [32]byte
+	Tokens        []common.Address
+
+	scales     []*big.Int // upscales token amounts to 18 decimals
+	monitorMu  sync.Mutex
+	monitoring bool          // an edge is monitoring the pool
+	released   chan struct{} // closed when that edge stops
+
+	mu          sync.RWMutex
+	balances    []*big.Int
+	weights     []*big.Int // normalized, 18 decimals
+	swapFee     *big.Int   // 18 decimals
+	blockNumber uint64
+}
+
+// Edge is the types.Pair view of tokens I and J of a pool.
+type Edge struct {
+	pool *Pool
+	I, J int
+}
+
+func (e *Edge) Asset1() string {
+	return e.pool.AssetNames[e.I]
+}
+
+func (e *Edge) Asset2() string {
+	return e.pool.AssetNames[e.J]
+}
+
+func (e *Edge) DEX() string {
+	return e.pool.DEXName
+}
+
+func (e *Edge) Address() string {
+	return e.pool.AddressString
+}
+
+// NewPool binds a weighted pool whose tokens, in Vault order, are named
+// assetNames with the given decimals, and loads its balances, weights and fee.
+func NewPool(address string, client bind.ContractBackend, assetNames []string, assetDecimals []int64) (*Pool, error) {
+	if len(assetNames) != len(assetDecimals) || len(assetNames) < 2 {
+		return nil, fmt.Errorf("balancer pool %s needs at least two tokens with decimals", address)
+	}
+
+	pool, err := NewWeightedpool(common.HexToAddress(address), client)
+	if err != nil {
+		return nil, err
+	}
+	vault, err := NewVault(common.HexToAddress(VaultAddress), client)
+	if err != nil {
+		return nil, err
+	}
+
+	poolId, err := pool.GetPoolId(nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a balancer pool: failed to read pool id: %v", address, err)
+	}
+
+	p := &Pool{
+		AddressString: address,
+		Client:        client,
+		PoolInterface: pool,
+		Vault:         vault,
+		DEXName:       "Balancer",
+		AssetNames:    assetNames,
+		AssetDecimals: assetDecimals,
+		PoolId:        poolId,
+	}
+	for _, decimals := range assetDecimals {
+		p.scales = append(p.scales, new(big.Int).Exp(big.NewInt(10), big.NewInt(18-decimals), nil))
+	}
+	if err := p.Load(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to load balancer pool %s: %v", address, err)
+	}
+	return p, nil
+}
+
+// Pairs returns an edge for every unordered token pair in the pool.
+func (p *Pool) Pairs() []types.Pair {
+	var pairs []types.Pair
+	for i := range p.AssetNames {
+		for j := i + 1; j < len(p.AssetNames); j++ {
+			pairs = append(pairs, &Edge{pool: p, I: i, J: j})
+		}
+	}
+	return pairs
+}
+
+// Load refreshes balances from the Vault and weights and fee from the pool.
+func (p *Pool) Load(ctx context.Context) error {
+	opts := &bind.CallOpts{Context: ctx}
+
+	poolTokens, err := p.Vault.GetPoolTokens(opts, p.PoolId)
+	if err != nil {
+		return fmt.Errorf("failed to read pool tokens: %v", err)
+	}
+	if len(poolTokens.Tokens) != len(p.AssetNames) {
+		return fmt.Errorf("balancer pool %s has %d tokens, configured with %d", p.AddressString, len(poolTokens.Tokens), len(p.AssetNames))
+	}
+	weights, err := p.PoolInterface.GetNormalizedWeights(opts)
+	if err != nil {
+		return fmt.Errorf("failed to read weights: %v", err)
+	}
+	swapFee, err := p.PoolInterface.GetSwapFeePercentage(opts)
+	if err != nil {
+		return fmt.Errorf("failed to read swap fee: %v", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Tokens = poolTokens.Tokens
+	p.balances = poolTokens.Balances
+	p.weights = weights
+	p.swapFee = swapFee
+	p.blockNumber = poolTokens.LastChangeBlock.Uint64()
+	return nil
+}
+
+// OutGivenIn quotes token i to token j offline with the weighted-product
+// formula and the pool's swap fee.
+func (p *Pool) OutGivenIn(i, j int, amountIn *big.Int) (*big.Int, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return outGivenIn(p.balances[i], p.balances[j], p.weights[i], p.weights[j], p.swapFee, p.scales[i], p.scales[j], amountIn)
+}
+
+func (e *Edge) indexes(assetIn string) (int, int, error) {
+	switch assetIn {
+	case e.Asset1():
+		return e.I, e.J, nil
+	case e.Asset2():
+		return e.J, e.I, nil
+	}
+	return 0, 0, fmt.Errorf("%s is not traded on %s %s/%s", assetIn, e.DEX(), e.Asset1(), e.Asset2())
+}
+
+func (e *Edge) Quote(assetIn string, amountIn *big.Int) (*big.Int, error) {
+	i, j, err := e.indexes(assetIn)
+	if err != nil {
+		return nil, err
+	}
+	return e.pool.OutGivenIn(i, j, amountIn)
+}
+
+// swapEvent derives the edge's marginal rates after fees from the spot price
+// (balanceOut / weightOut) / (balanceIn / weightIn).
+func (e *Edge) swapEvent() types.SwapEvent {
+	p := e.pool
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	whole := func(k int) *big.Float {
+		value := new(big.Float).SetInt(p.balances[k])
+		return value.Quo(value, new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(p.AssetDecimals[k]), nil)))
+	}
+	reserve1, reserve2 := whole(e.I), whole(e.J)
+
+	weighted1 := new(big.Float).Quo(reserve1, new(big.Float).SetInt(p.weights[e.I]))
+	weighted2 := new(big.Float).Quo(reserve2, new(big.Float).SetInt(p.weights[e.J]))
+	feeFactor := new(big.Float).Quo(new(big.Float).SetInt(complement(p.swapFee)), new(big.Float).SetInt(one))
+
+	forward := new(big.Float).Quo(weighted2, weighted1)
+	backward := new(big.Float).Quo(weighted1, weighted2)
+
+	return types.SwapEvent{
+		DEXName:     p.DEXName,
+		Asset1Name:  e.Asset1(),
+		Asset2Name:  e.Asset2(),
+		Address:     p.AddressString,
+		AmountOut:   types.AmountOut{Amount1: forward.Mul(forward, feeFactor), Amount2: backward.Mul(backward, feeFactor)},
+		Reserve1:    reserve1,
+		Reserve2:    reserve2,
+		BlockNumber: p.blockNumber,
+	}
+}
+
+// PairTokens returns the tokens of I and J, as the Vault listed them.
+func (e *Edge) PairTokens(ctx context.Context) (types.Token, types.Token, error) {
+	p := e.pool
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return types.Token{Asset: e.Asset1(), Address: p.Tokens[e.I], Decimals: p.AssetDecimals[e.I]},
+		types.Token{Asset: e.Asset2(), Address: p.Tokens[e.J], Decimals: p.AssetDecimals[e.J]}, nil
+}
+
+// Snapshot reloads the pool and describes the edge as a swap event.
+func (e *Edge) Snapshot(ctx context.Context) (types.SwapEvent, error) {
+	if err := e.pool.Load(ctx); err != nil {
+		return types.SwapEvent{}, err
+	}
+	return e.swapEvent(), nil
+}
+
+// Monitor watches the pool on behalf of all of its edges: one edge at a time
+// subscribes and reports every edge, the others wait and take over when it
+// stops. It returns nil once ctx is cancelled, or the error that stopped the
+// subscription.
+func (e *Edge) Monitor(ctx context.Context, swapEventChan chan<- types.SwapEvent) error {
+	for {
+		released, ok := e.pool.claimMonitor()
+		if ok {
+			defer e.pool.releaseMonitor()
+			return e.pool.monitor(ctx, swapEventChan)
+		}
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// claimMonitor makes the caller the pool's monitor if there is none, and
+// otherwise returns a channel closed when the current one stops.
+func (p *Pool) claimMonitor() (<-chan struct{}, bool) {
+	p.monitorMu.Lock()
+	defer p.monitorMu.Unlock()
+	if p.monitoring {
+		return p.released, false
+	}
+	p.monitoring, p.released = true, make(chan struct{})
+	return nil, true
+}
+
+func (p *Pool) releaseMonitor() {
+	p.monitorMu.Lock()
+	defer p.monitorMu.Unlock()
+	p.monitoring = false
+	close(p.released)
+}
+
+func (p *Pool) monitor(ctx context.Context, swapEventChan chan<- types.SwapEvent) error {
+	opts := &bind.WatchOpts{Context: ctx}
+	poolIds := [][32]byte{p.PoolId}
+
+	swapChan := make(chan *VaultSwap)
+	swapSub, err := p.Vault.WatchSwap(opts, swapChan, poolIds, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %v", p.AddressString, err)
+	}
+	defer swapSub.Unsubscribe()
+	balanceChan := make(chan *VaultPoolBalanceChanged)
+	balanceSub, err := p.Vault.WatchPoolBalanceChanged(opts, balanceChan, poolIds, nil)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %v", p.AddressString, err)
+	}
+	defer balanceSub.Unsubscribe()
+	feeChan := make(chan *WeightedpoolSwapFeePercentageChanged)
+	feeSub, err := p.PoolInterface.WatchSwapFeePercentageChanged(opts, feeChan)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %v", p.AddressString, err)
+	}
+	defer feeSub.Unsubscribe()
+
+	edges := p.Pairs()
+	for _, edge := range edges {
+		log.Printf("Listening for swap events: %s/%s on %s (%s)", edge.Asset1(), edge.Asset2(), p.DEXName, p.AddressString)
+	}
+
+	for {
+		select {
+		case err := <-swapSub.Err():
+			return fmt.Errorf("subscription error: %v", err)
+		case err := <-balanceSub.Err():
+			return fmt.Errorf("subscription error: %v", err)
+		case err := <-feeSub.Err():
+			return fmt.Errorf("subscription error: %v", err)
+		case <-ctx.Done():
+			return nil
+		case <-swapChan:
+		case <-balanceChan:
+		case <-feeChan:
+		}
+
+		if err := p.Load(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		// Send update to channel
+		for _, edge := range edges {
+			select {
+			case swapEventChan <- edge.(*Edge).swapEvent():
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+}
+
+func funds(account common.Address) IVaultFundManagement {
+	return IVaultFundManagement{
+		Sender:    account,
+		Recipient: account,
+	}
+}
+
+// deadline never passes: a rebroadcast route must not revert with BAL#508,
+// and the executor already reverts a route that pays out too little.
+func deadline() *big.Int {
+	return abi.MaxUint256
+}
+
+// SwapCalls has the executor approve the Vault for its balance of assetIn
+// and sell all of it through the pool for at least minOut.
+func (e *Edge) SwapCalls(exec, tokenIn common.Address, assetIn string, minOut *big.Int) ([]types.Call, error) {
+	i, j, err := e.indexes(assetIn)
+	if err != nil {
+		return nil, err
+	}
+	p := e.pool
+	if tokenIn != p.Tokens[i] {
+		return nil, fmt.Errorf("%s of %s %s/%s is %s, not %s", assetIn, e.DEX(), e.Asset1(), e.Asset2(), p.Tokens[i].Hex(), tokenIn.Hex())
+	}
+	if minOut == nil {
+		minOut = new(big.Int)
+	}
+
+	singleSwap := IVaultSingleSwap{
+		PoolId:   p.PoolId,
+		Kind:     swapKindGivenIn,
+		AssetIn:  p.Tokens[i],
+		AssetOut: p.Tokens[j],
+		Amount:   executor.Balance,
+		UserData: []byte{},
+	}
+	return vaultCalls(tokenIn, "swap", singleSwap, funds(exec), minOut, deadline())
+}
+
+// vaultCalls has the executor approve the Vault for its balance of tokenIn
+// and call method with args, which spend that balance.
+func vaultCalls(tokenIn common.Address, method string, args ...interface{}) ([]types.Call, error) {
+	vault := common.HexToAddress(VaultAddress)
+	approve, err := executor.Approve(tokenIn, vault)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := VaultMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	data, err := parsed.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	call, err := executor.Spend(vault, data, tokenIn)
+	if err != nil {
+		return nil, err
+	}
+	return []types.Call{approve, call}, nil
+}
+
+// Leg is one hop of a batch swap: selling AssetIn through Edge.
+type Leg struct {
+	Edge    *Edge
+	AssetIn string
+}
+
+// BatchSwapCalls has the executor route its whole balance of the first leg's
+// input through consecutive legs in a single Vault batchSwap, each leg
+// consuming the previous leg's output, for at least minOut of the last leg's
+// output. A cycle nets out to one asset, whose minimum is left to the route.
+// All legs must share a Vault.
+func BatchSwapCalls(exec common.Address, legs []Leg, minOut *big.Int) ([]types.Call, error) {
+	if len(legs) == 0 {
+		return nil, fmt.Errorf("batch swap needs at least one leg")
+	}
+
+	var assets []common.Address
+	assetIndex := make(map[common.Address]int)
+	indexOf := func(token common.Address) *big.Int {
+		k, ok := assetIndex[token]
+		if !ok {
+			k = len(assets)
+			assetIndex[token] = k
+			assets = append(assets, token)
+		}
+		return big.NewInt(int64(k))
+	}
+
+	var steps []IVaultBatchSwapStep
+	var tokenIn common.Address
+	for n, leg := range legs {
+		i, j, err := leg.Edge.indexes(leg.AssetIn)
+		if err != nil {
+			return nil, err
+		}
+
+		// An amount of zero tells the Vault to use the previous step's output
+		amount := big.NewInt(0)
+		if n == 0 {
+			amount = executor.Balance
+			tokenIn = leg.Edge.pool.Tokens[i]
+		}
+		steps = append(steps, IVaultBatchSwapStep{
+			PoolId:        leg.Edge.pool.PoolId,
+			AssetInIndex:  indexOf(leg.Edge.pool.Tokens[i]),
+			AssetOutIndex: indexOf(leg.Edge.pool.Tokens[j]),
+			Amount:        amount,
+			UserData:      []byte{},
+		})
+	}
+
+	// Positive limits cap what the executor sends, which is all it holds
+	// anyway; negative limits floor what it receives
+	limits := make([]*big.Int, len(assets))
+	for k := range limits {
+		limits[k] = big.NewInt(0)
+	}
+	first, last := steps[0].AssetInIndex.Int64(), steps[len(steps)-1].AssetOutIndex.Int64()
+	limits[first] = maxInt256
+	if first != last && minOut != nil {
+		limits[last] = new(big.Int).Neg(minOut)
+	}
+	return vaultCalls(tokenIn, "batchSwap", uint8(swapKindGivenIn), steps, assets, funds(exec), limits, deadline())
+}
+
+// maxInt256 is the largest limit the Vault takes.
+var maxInt256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(1))
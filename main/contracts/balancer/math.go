@@ -0,0 +1,115 @@
+package balancerpool
+
+// Offline port of the weighted-pool swap math (WeightedMath, FixedPoint).
+// Powers with exponent 1, 2 or 4 are exact like the contracts; other
+// exponents are evaluated in float64 and rounded up by the pool's maximum
+// relative pow error, so quotes may differ from the pool by a few wei.
+
+import (
+	"errors"
+	"math"
+	"math/big"
+)
+
+var (
+	one            = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	two            = new(big.Int).Mul(one, big.NewInt(2))
+	four           = new(big.Int).Mul(one, big.NewInt(4))
+	maxInRatio     = new(big.Int).Div(new(big.Int).Mul(one, big.NewInt(3)), big.NewInt(10))
+	maxPowRelErr   = big.NewInt(10000)
+	errMaxInRatio  = errors.New("amount in exceeds the pool's max in ratio")
+	errZeroBalance = errors.New("pool has a zero balance")
+)
+
+func mulDown(a, b *big.Int) *big.Int {
+	product := new(big.Int).Mul(a, b)
+	return product.Quo(product, one)
+}
+
+func mulUp(a, b *big.Int) *big.Int {
+	product := new(big.Int).Mul(a, b)
+	if product.Sign() == 0 {
+		return product
+	}
+	product.Sub(product, big.NewInt(1))
+	product.Quo(product, one)
+	return product.Add(product, big.NewInt(1))
+}
+
+func divDown(a, b *big.Int) *big.Int {
+	numerator := new(big.Int).Mul(a, one)
+	return numerator.Quo(numerator, b)
+}
+
+func divUp(a, b *big.Int) *big.Int {
+	if a.Sign() == 0 {
+		return new(big.Int)
+	}
+	numerator := new(big.Int).Mul(a, one)
+	numerator.Sub(numerator, big.NewInt(1))
+	numerator.Quo(numerator, b)
+	return numerator.Add(numerator, big.NewInt(1))
+}
+
+func complement(x *big.Int) *big.Int {
+	if x.Cmp(one) >= 0 {
+		return new(big.Int)
+	}
+	return new(big.Int).Sub(one, x)
+}
+
+func powUp(x, y *big.Int) *big.Int {
+	switch {
+	case y.Cmp(one) == 0:
+		return new(big.Int).Set(x)
+	case y.Cmp(two) == 0:
+		return mulUp(x, x)
+	case y.Cmp(four) == 0:
+		square := mulUp(x, x)
+		return mulUp(square, square)
+	}
+
+	base, _ := new(big.Float).Quo(new(big.Float).SetInt(x), new(big.Float).SetInt(one)).Float64()
+	exponent, _ := new(big.Float).Quo(new(big.Float).SetInt(y), new(big.Float).SetInt(one)).Float64()
+	raw, _ := new(big.Float).Mul(big.NewFloat(math.Pow(base, exponent)), new(big.Float).SetInt(one)).Int(nil)
+
+	maxError := new(big.Int).Add(mulUp(raw, maxPowRelErr), big.NewInt(1))
+	return raw.Add(raw, maxError)
+}
+
+// calcOutGivenIn mirrors WeightedMath._calcOutGivenIn on upscaled amounts.
+func calcOutGivenIn(balanceIn, weightIn, balanceOut, weightOut, amountIn *big.Int) (*big.Int, error) {
+	if amountIn.Cmp(mulDown(balanceIn, maxInRatio)) > 0 {
+		return nil, errMaxInRatio
+	}
+
+	denominator := new(big.Int).Add(balanceIn, amountIn)
+	base := divUp(balanceIn, denominator)
+	exponent := divDown(weightIn, weightOut)
+	power := powUp(base, exponent)
+
+	return mulDown(balanceOut, complement(power)), nil
+}
+
+// outGivenIn runs a GIVEN_IN swap the way the pool's onSwap does: the swap fee
+// is taken from the input in token units, then amounts are scaled to 18
+// decimals for the weighted math and the result is scaled back down.
+func outGivenIn(balanceIn, balanceOut, weightIn, weightOut, swapFee, scaleIn, scaleOut, amountIn *big.Int) (*big.Int, error) {
+	if balanceIn.Sign() == 0 || balanceOut.Sign() == 0 {
+		return nil, errZeroBalance
+	}
+
+	amountIn = new(big.Int).Sub(amountIn, mulUp(amountIn, swapFee))
+
+	out, err := calcOutGivenIn(
+		new(big.Int).Mul(balanceIn, scaleIn),
+		weightIn,
+		new(big.Int).Mul(balanceOut, scaleOut),
+		weightOut,
+		new(big.Int).Mul(amountIn, scaleIn),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return out.Quo(out, scaleOut), nil
+}
@@ -0,0 +1,95 @@
+package balancerpool
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+// power raises x to the exponents the tests use, exactly or by square roots,
+// in 256-bit floats.
+func power(x *big.Float, exponent float64) *big.Float {
+	result := new(big.Float).SetPrec(256).SetInt64(1)
+	whole := int(exponent)
+	for k := 0; k < whole; k++ {
+		result.Mul(result, x)
+	}
+	switch exponent - float64(whole) {
+	case 0:
+	case 0.5:
+		result.Mul(result, new(big.Float).SetPrec(256).Sqrt(x))
+	case 0.25:
+		root := new(big.Float).SetPrec(256).Sqrt(x)
+		result.Mul(result, root.Sqrt(root))
+	default:
+		panic("unsupported exponent")
+	}
+	return result
+}
+
+// units converts whole tokens to a raw amount with the given decimals.
+func units(whole float64, decimals int64) *big.Int {
+	amount, _ := new(big.Float).Mul(big.NewFloat(whole), new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(decimals), nil))).Int(nil)
+	return amount
+}
+
+func TestOutGivenIn(t *testing.T) {
+	cases := []struct {
+		name                    string
+		weightIn, weightOut     float64
+		balanceIn, balanceOut   float64
+		decimalsIn, decimalsOut int64
+		amountIn                float64
+	}{
+		{"50/50", 0.5, 0.5, 1000, 2e6, 18, 18, 10},
+		{"50/50, 18 to 6 decimals", 0.5, 0.5, 1000, 2e6, 18, 6, 10},
+		{"50/50, 6 to 18 decimals", 0.5, 0.5, 2e6, 1000, 6, 18, 2e4},
+		{"80/20", 0.8, 0.2, 1000, 5e5, 18, 18, 100},
+		{"20/80", 0.2, 0.8, 5e5, 1000, 18, 18, 1e4},
+		{"60/40", 0.6, 0.4, 1000, 1e6, 18, 6, 50},
+		{"98/2", 0.98, 0.02, 1e4, 100, 18, 18, 1},
+		{"at nearly the max in ratio", 0.5, 0.5, 1000, 1000, 18, 18, 300},
+	}
+	swapFee := units(0.003, 18)
+	for _, c := range cases {
+		scaleIn := new(big.Int).Exp(big.NewInt(10), big.NewInt(18-c.decimalsIn), nil)
+		scaleOut := new(big.Int).Exp(big.NewInt(10), big.NewInt(18-c.decimalsOut), nil)
+		balanceIn, balanceOut := units(c.balanceIn, c.decimalsIn), units(c.balanceOut, c.decimalsOut)
+		got, err := outGivenIn(balanceIn, balanceOut, units(c.weightIn, 18), units(c.weightOut, 18), swapFee, scaleIn, scaleOut, units(c.amountIn, c.decimalsIn))
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+
+		// balanceOut * (1 - (balanceIn / (balanceIn + amountIn)) ^ (weightIn / weightOut))
+		in := new(big.Float).SetPrec(256).SetFloat64(c.amountIn * 0.997)
+		base := new(big.Float).SetPrec(256).SetFloat64(c.balanceIn)
+		base.Quo(base, in.Add(in, base))
+		ratio := new(big.Float).Sub(new(big.Float).SetInt64(1), power(base, math.Round(4*c.weightIn/c.weightOut)/4))
+		want, _ := ratio.Mul(ratio, new(big.Float).SetFloat64(c.balanceOut)).Float64()
+
+		out, _ := new(big.Float).Quo(new(big.Float).SetInt(got), new(big.Float).SetInt(units(1, c.decimalsOut))).Float64()
+		// The pool rounds every step in its own favour, the power up by its
+		// maximum relative error of 1e-14, and the result down to a raw unit
+		tolerance := 2e-14*c.balanceOut + math.Pow10(-int(c.decimalsOut))
+		if out > want*(1+1e-15) || out < want-tolerance {
+			t.Errorf("%s: got %g, want %g", c.name, out, want)
+		}
+	}
+}
+
+func TestOutGivenInLimits(t *testing.T) {
+	balance, weight, scale := units(1000, 18), units(0.5, 18), big.NewInt(1)
+	if _, err := outGivenIn(balance, balance, weight, weight, new(big.Int), scale, scale, units(301, 18)); err != errMaxInRatio {
+		t.Errorf("over the max in ratio: %v", err)
+	}
+	// The ratio applies after the fee
+	if _, err := outGivenIn(balance, balance, weight, weight, units(0.01, 18), scale, scale, units(301, 18)); err != nil {
+		t.Errorf("under the max in ratio after the fee: %v", err)
+	}
+	if _, err := outGivenIn(new(big.Int), balance, weight, weight, new(big.Int), scale, scale, units(1, 18)); err != errZeroBalance {
+		t.Errorf("zero balance: %v", err)
+	}
+	if out, err := outGivenIn(balance, balance, weight, weight, new(big.Int), scale, scale, new(big.Int)); err != nil || out.Sign() != 0 {
+		t.Errorf("paid %v %v for nothing", out, err)
+	}
+}
@@ -0,0 +1,620 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package balancerpool
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// IVaultBatchSwapStep is an auto generated low-level Go binding around an user-defined struct.
+type IVaultBatchSwapStep struct {
+	PoolId        [32]byte
+	AssetInIndex  *big.Int
+	AssetOutIndex *big.Int
+	Amount        *big.Int
+	UserData      []byte
+}
+
+// IVaultFundManagement is an auto generated low-level Go binding around an user-defined struct.
+type IVaultFundManagement struct {
+	Sender              common.Address
+	FromInternalBalance bool
+	Recipient           common.Address
+	ToInternalBalance   bool
+}
+
+// IVaultSingleSwap is an auto generated low-level Go binding around an user-defined struct.
+type IVaultSingleSwap struct {
+	PoolId   [32]byte
+	Kind     uint8
+	AssetIn  common.Address
+	AssetOut common.Address
+	Amount   *big.Int
+	UserData []byte
+}
+
+// VaultMetaData contains all meta data concerning the Vault contract.
+var VaultMetaData = &bind.MetaData{
+	ABI: "[{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"poolId\",\"type\":\"bytes32\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"liquidityProvider\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"contractIERC20[]\",\"name\":\"tokens\",\"type\":\"address[]\"},{\"indexed\":false,\"internalType\":\"int256[]\",\"name\":\"deltas\",\"type\":\"int256[]\"},{\"indexed\":false,\"internalType\":\"uint256[]\",\"name\":\"protocolFeeAmounts\",\"type\":\"uint256[]\"}],\"name\":\"PoolBalanceChanged\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"poolId\",\"type\":\"bytes32\"},{\"indexed\":true,\"internalType\":\"contractIERC20\",\"name\":\"tokenIn\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"contractIERC20\",\"name\":\"tokenOut\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"amountIn\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"amountOut\",\"type\":\"uint256\"}],\"name\":\"Swap\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"enumIVault.SwapKind\",\"name\":\"kind\",\"type\":\"uint8\"},{\"internalType\":\"structIVault.BatchSwapStep[]\",\"name\":\"swaps\",\"type\":\"tuple[]\",\"components\":[{\"internalType\":\"bytes32\",\"name\":\"poolId\",\"type\":\"bytes32\"},{\"internalType\":\"uint256\",\"name\":\"assetInIndex\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"assetOutIndex\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"userData\",\"type\":\"bytes\"}]},{\"internalType\":\"contractIAsset[]\",\"name\":\"assets\",\"type\":\"address[]\"},{\"internalType\":\"structIVault.FundManagement\",\"name\":\"funds\",\"type\":\"tuple\",\"components\":[{\"internalType\":\"address\",\"name\":\"sender\",\"type\":\"address\"},{\"internalType\":\"bool\",\"name\":\"fromInternalBalance\",\"type\":\"bool\"},{\"internalType\":\"addresspayable\",\"name\":\"recipient\",\"type\":\"address\"},{\"internalType\":\"bool\",\"name\":\"toInternalBalance\",\"type\":\"bool\"}]},{\"internalType\":\"int256[]\",\"name\":\"limits\",\"type\":\"int256[]\"},{\"internalType\":\"uint256\",\"name\":\"deadline\",\"type\":\"uint256\"}],\"name\":\"batchSwap\",\"outputs\":[{\"internalType\":\"int256[]\",\"name\":\"assetDeltas\",\"type\":\"int256[]\"}],\"stateMutability\":\"payable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"poolId\",\"type\":\"bytes32\"}],\"name\":\"getPoolTokens\",\"outputs\":[{\"internalType\":\"contractIERC20[]\",\"name\":\"tokens\",\"type\":\"address[]\"},{\"internalType\":\"uint256[]\",\"name\":\"balances\",\"type\":\"uint256[]\"},{\"internalType\":\"uint256\",\"name\":\"lastChangeBlock\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"structIVault.SingleSwap\",\"name\":\"singleSwap\",\"type\":\"tuple\",\"components\":[{\"internalType\":\"bytes32\",\"name\":\"poolId\",\"type\":\"bytes32\"},{\"internalType\":\"enumIVault.SwapKind\",\"name\":\"kind\",\"type\":\"uint8\"},{\"internalType\":\"contractIAsset\",\"name\":\"assetIn\",\"type\":\"address\"},{\"internalType\":\"contractIAsset\",\"name\":\"assetOut\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"userData\",\"type\":\"bytes\"}]},{\"internalType\":\"structIVault.FundManagement\",\"name\":\"funds\",\"type\":\"tuple\",\"components\":[{\"internalType\":\"address\",\"name\":\"sender\",\"type\":\"address\"},{\"internalType\":\"bool\",\"name\":\"fromInternalBalance\",\"type\":\"bool\"},{\"internalType\":\"addresspayable\",\"name\":\"recipient\",\"type\":\"address\"},{\"internalType\":\"bool\",\"name\":\"toInternalBalance\",\"type\":\"bool\"}]},{\"internalType\":\"uint256\",\"name\":\"limit\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"deadline\",\"type\":\"uint256\"}],\"name\":\"swap\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"amountCalculated\",\"type\":\"uint256\"}],\"stateMutability\":\"payable\",\"type\":\"function\"}]",
+}
+
+// VaultABI is the input ABI used to generate the binding from.
+// Deprecated: Use VaultMetaData.ABI instead.
+var VaultABI = VaultMetaData.ABI
+
+// Vault is an auto generated Go binding around an Ethereum contract.
+type Vault struct {
+	VaultCaller     // Read-only binding to the contract
+	VaultTransactor // Write-only binding to the contract
+	VaultFilterer   // Log filterer for contract events
+}
+
+// VaultCaller is an auto generated read-only Go binding around an Ethereum contract.
+type VaultCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// VaultTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type VaultTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// VaultFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type VaultFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// VaultSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type VaultSession struct {
+	Contract     *Vault            // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// VaultCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type VaultCallerSession struct {
+	Contract *VaultCaller  // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts // Call options to use throughout this session
+}
+
+// VaultTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type VaultTransactorSession struct {
+	Contract     *VaultTransactor  // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// VaultRaw is an auto generated low-level Go binding around an Ethereum contract.
+type VaultRaw struct {
+	Contract *Vault // Generic contract binding to access the raw methods on
+}
+
+// VaultCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type VaultCallerRaw struct {
+	Contract *VaultCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// VaultTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type VaultTransactorRaw struct {
+	Contract *VaultTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewVault creates a new instance of Vault, bound to a specific deployed contract.
+func NewVault(address common.Address, backend bind.ContractBackend) (*Vault, error) {
+	contract, err := bindVault(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Vault{VaultCaller: VaultCaller{contract: contract}, VaultTransactor: VaultTransactor{contract: contract}, VaultFilterer: VaultFilterer{contract: contract}}, nil
+}
+
+// NewVaultCaller creates a new read-only instance of Vault, bound to a specific deployed contract.
+func NewVaultCaller(address common.Address, caller bind.ContractCaller) (*VaultCaller, error) {
+	contract, err := bindVault(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &VaultCaller{contract: contract}, nil
+}
+
+// NewVaultTransactor creates a new write-only instance of Vault, bound to a specific deployed contract.
+func NewVaultTransactor(address common.Address, transactor bind.ContractTransactor) (*VaultTransactor, error) {
+	contract, err := bindVault(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &VaultTransactor{contract: contract}, nil
+}
+
+// NewVaultFilterer creates a new log filterer instance of Vault, bound to a specific deployed contract.
+func NewVaultFilterer(address common.Address, filterer bind.ContractFilterer) (*VaultFilterer, error) {
+	contract, err := bindVault(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &VaultFilterer{contract: contract}, nil
+}
+
+// bindVault binds a generic wrapper to an already deployed contract.
+func bindVault(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := VaultMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Vault *VaultRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Vault.Contract.VaultCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Vault *VaultRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Vault.Contract.VaultTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Vault *VaultRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Vault.Contract.VaultTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Vault *VaultCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Vault.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Vault *VaultTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Vault.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Vault *VaultTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Vault.Contract.contract.Transact(opts, method, params...)
+}
+
+// GetPoolTokens is a free data retrieval call binding the contract method 0xf94d4668.
+//
+// Solidity: function getPoolTokens(bytes32 poolId) view returns(address[] tokens, uint256[] balances, uint256 lastChangeBlock)
+func (_Vault *VaultCaller) GetPoolTokens(opts *bind.CallOpts, poolId [32]byte) (struct {
+	Tokens          []common.Address
+	Balances        []*big.Int
+	LastChangeBlock *big.Int
+}, error) {
+	var out []interface{}
+	err := _Vault.contract.Call(opts, &out, "getPoolTokens", poolId)
+
+	outstruct := new(struct {
+		Tokens          []common.Address
+		Balances        []*big.Int
+		LastChangeBlock *big.Int
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Tokens = *abi.ConvertType(out[0], new([]common.Address)).(*[]common.Address)
+	outstruct.Balances = *abi.ConvertType(out[1], new([]*big.Int)).(*[]*big.Int)
+	outstruct.LastChangeBlock = *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+
+	return *outstruct, err
+
+}
+
+// GetPoolTokens is a free data retrieval call binding the contract method 0xf94d4668.
+//
+// Solidity: function getPoolTokens(bytes32 poolId) view returns(address[] tokens, uint256[] balances, uint256 lastChangeBlock)
+func (_Vault *VaultSession) GetPoolTokens(poolId [32]byte) (struct {
+	Tokens          []common.Address
+	Balances        []*big.Int
+	LastChangeBlock *big.Int
+}, error) {
+	return _Vault.Contract.GetPoolTokens(&_Vault.CallOpts, poolId)
+}
+
+// GetPoolTokens is a free data retrieval call binding the contract method 0xf94d4668.
+//
+// Solidity: function getPoolTokens(bytes32 poolId) view returns(address[] tokens, uint256[] balances, uint256 lastChangeBlock)
+func (_Vault *VaultCallerSession) GetPoolTokens(poolId [32]byte) (struct {
+	Tokens          []common.Address
+	Balances        []*big.Int
+	LastChangeBlock *big.Int
+}, error) {
+	return _Vault.Contract.GetPoolTokens(&_Vault.CallOpts, poolId)
+}
+
+// BatchSwap is a paid mutator transaction binding the contract method 0x945bcec9.
+//
+// Solidity: function batchSwap(uint8 kind, (bytes32,uint256,uint256,uint256,bytes)[] swaps, address[] assets, (address,bool,address,bool) funds, int256[] limits, uint256 deadline) payable returns(int256[] assetDeltas)
+func (_Vault *VaultTransactor) BatchSwap(opts *bind.TransactOpts, kind uint8, swaps []IVaultBatchSwapStep, assets []common.Address, funds IVaultFundManagement, limits []*big.Int, deadline *big.Int) (*types.Transaction, error) {
+	return _Vault.contract.Transact(opts, "batchSwap", kind, swaps, assets, funds, limits, deadline)
+}
+
+// BatchSwap is a paid mutator transaction binding the contract method 0x945bcec9.
+//
+// Solidity: function batchSwap(uint8 kind, (bytes32,uint256,uint256,uint256,bytes)[] swaps, address[] assets, (address,bool,address,bool) funds, int256[] limits, uint256 deadline) payable returns(int256[] assetDeltas)
+func (_Vault *VaultSession) BatchSwap(kind uint8, swaps []IVaultBatchSwapStep, assets []common.Address, funds IVaultFundManagement, limits []*big.Int, deadline *big.Int) (*types.Transaction, error) {
+	return _Vault.Contract.BatchSwap(&_Vault.TransactOpts, kind, swaps, assets, funds, limits, deadline)
+}
+
+// BatchSwap is a paid mutator transaction binding the contract method 0x945bcec9.
+//
+// Solidity: function batchSwap(uint8 kind, (bytes32,uint256,uint256,uint256,bytes)[] swaps, address[] assets, (address,bool,address,bool) funds, int256[] limits, uint256 deadline) payable returns(int256[] assetDeltas)
+func (_Vault *VaultTransactorSession) BatchSwap(kind uint8, swaps []IVaultBatchSwapStep, assets []common.Address, funds IVaultFundManagement, limits []*big.Int, deadline *big.Int) (*types.Transaction, error) {
+	return _Vault.Contract.BatchSwap(&_Vault.TransactOpts, kind, swaps, assets, funds, limits, deadline)
+}
+
+// Swap is a paid mutator transaction binding the contract method 0x52bbbe29.
+//
+// Solidity: function swap((bytes32,uint8,address,address,uint256,bytes) singleSwap, (address,bool,address,bool) funds, uint256 limit, uint256 deadline) payable returns(uint256 amountCalculated)
+func (_Vault *VaultTransactor) Swap(opts *bind.TransactOpts, singleSwap IVaultSingleSwap, funds IVaultFundManagement, limit *big.Int, deadline *big.Int) (*types.Transaction, error) {
+	return _Vault.contract.Transact(opts, "swap", singleSwap, funds, limit, deadline)
+}
+
+// Swap is a paid mutator transaction binding the contract method 0x52bbbe29.
+//
+// Solidity: function swap((bytes32,uint8,address,address,uint256,bytes) singleSwap, (address,bool,address,bool) funds, uint256 limit, uint256 deadline) payable returns(uint256 amountCalculated)
+func (_Vault *VaultSession) Swap(singleSwap IVaultSingleSwap, funds IVaultFundManagement, limit *big.Int, deadline *big.Int) (*types.Transaction, error) {
+	return _Vault.Contract.Swap(&_Vault.TransactOpts, singleSwap, funds, limit, deadline)
+}
+
+// Swap is a paid mutator transaction binding the contract method 0x52bbbe29.
+//
+// Solidity: function swap((bytes32,uint8,address,address,uint256,bytes) singleSwap, (address,bool,address,bool) funds, uint256 limit, uint256 deadline) payable returns(uint256 amountCalculated)
+func (_Vault *VaultTransactorSession) Swap(singleSwap IVaultSingleSwap, funds IVaultFundManagement, limit *big.Int, deadline *big.Int) (*types.Transaction, error) {
+	return _Vault.Contract.Swap(&_Vault.TransactOpts, singleSwap, funds, limit, deadline)
+}
+
+// VaultPoolBalanceChangedIterator is returned from FilterPoolBalanceChanged and is used to iterate over the raw logs and unpacked data for PoolBalanceChanged events raised by the Vault contract.
+type VaultPoolBalanceChangedIterator struct {
+	Event *VaultPoolBalanceChanged // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *VaultPoolBalanceChangedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(VaultPoolBalanceChanged)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(VaultPoolBalanceChanged)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *VaultPoolBalanceChangedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *VaultPoolBalanceChangedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// VaultPoolBalanceChanged represents a PoolBalanceChanged event raised by the Vault contract.
+type VaultPoolBalanceChanged struct {
+	PoolId             [32]byte
+	LiquidityProvider  common.Address
+	Tokens             []common.Address
+	Deltas             []*big.Int
+	ProtocolFeeAmounts []*big.Int
+	Raw                types.Log // Blockchain specific contextual infos
+}
+
+// FilterPoolBalanceChanged is a free log retrieval operation binding the contract event 0xe5ce249087ce04f05a957192435400fd97868dba0e6a4b4c049abf8af80dae78.
+//
+// Solidity: event PoolBalanceChanged(bytes32 indexed poolId, address indexed liquidityProvider, address[] tokens, int256[] deltas, uint256[] protocolFeeAmounts)
+func (_Vault *VaultFilterer) FilterPoolBalanceChanged(opts *bind.FilterOpts, poolId [][32]byte, liquidityProvider []common.Address) (*VaultPoolBalanceChangedIterator, error) {
+
+	var poolIdRule []interface{}
+	for _, poolIdItem := range poolId {
+		poolIdRule = append(poolIdRule, poolIdItem)
+	}
+	var liquidityProviderRule []interface{}
+	for _, liquidityProviderItem := range liquidityProvider {
+		liquidityProviderRule = append(liquidityProviderRule, liquidityProviderItem)
+	}
+
+	logs, sub, err := _Vault.contract.FilterLogs(opts, "PoolBalanceChanged", poolIdRule, liquidityProviderRule)
+	if err != nil {
+		return nil, err
+	}
+	return &VaultPoolBalanceChangedIterator{contract: _Vault.contract, event: "PoolBalanceChanged", logs: logs, sub: sub}, nil
+}
+
+// WatchPoolBalanceChanged is a free log subscription operation binding the contract event 0xe5ce249087ce04f05a957192435400fd97868dba0e6a4b4c049abf8af80dae78.
+//
+// Solidity: event PoolBalanceChanged(bytes32 indexed poolId, address indexed liquidityProvider, address[] tokens, int256[] deltas, uint256[] protocolFeeAmounts)
+func (_Vault *VaultFilterer) WatchPoolBalanceChanged(opts *bind.WatchOpts, sink chan<- *VaultPoolBalanceChanged, poolId [][32]byte, liquidityProvider []common.Address) (event.Subscription, error) {
+
+	var poolIdRule []interface{}
+	for _, poolIdItem := range poolId {
+		poolIdRule = append(poolIdRule, poolIdItem)
+	}
+	var liquidityProviderRule []interface{}
+	for _, liquidityProviderItem := range liquidityProvider {
+		liquidityProviderRule = append(liquidityProviderRule, liquidityProviderItem)
+	}
+
+	logs, sub, err := _Vault.contract.WatchLogs(opts, "PoolBalanceChanged", poolIdRule, liquidityProviderRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(VaultPoolBalanceChanged)
+				if err := _Vault.contract.UnpackLog(event, "PoolBalanceChanged", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParsePoolBalanceChanged is a log parse operation binding the contract event 0xe5ce249087ce04f05a957192435400fd97868dba0e6a4b4c049abf8af80dae78.
+//
+// Solidity: event PoolBalanceChanged(bytes32 indexed poolId, address indexed liquidityProvider, address[] tokens, int256[] deltas, uint256[] protocolFeeAmounts)
+func (_Vault *VaultFilterer) ParsePoolBalanceChanged(log types.Log) (*VaultPoolBalanceChanged, error) {
+	event := new(VaultPoolBalanceChanged)
+	if err := _Vault.contract.UnpackLog(event, "PoolBalanceChanged", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// VaultSwapIterator is returned from FilterSwap and is used to iterate over the raw logs and unpacked data for Swap events raised by the Vault contract.
+type VaultSwapIterator struct {
+	Event *VaultSwap // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *VaultSwapIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(VaultSwap)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(VaultSwap)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *VaultSwapIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *VaultSwapIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// VaultSwap represents a Swap event raised by the Vault contract.
+type VaultSwap struct {
+	PoolId    [32]byte
+	TokenIn   common.Address
+	TokenOut  common.Address
+	AmountIn  *big.Int
+	AmountOut *big.Int
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterSwap is a free log retrieval operation binding the contract event 0x2170c741c41531aec20e7c107c24eecfdd15e69c9bb0a8dd37b1840b9e0b207b.
+//
+// Solidity: event Swap(bytes32 indexed poolId, address indexed tokenIn, address indexed tokenOut, uint256 amountIn, uint256 amountOut)
+func (_Vault *VaultFilterer) FilterSwap(opts *bind.FilterOpts, poolId [][32]byte, tokenIn []common.Address, tokenOut []common.Address) (*VaultSwapIterator, error) {
+
+	var poolIdRule []interface{}
+	for _, poolIdItem := range poolId {
+		poolIdRule = append(poolIdRule, poolIdItem)
+	}
+	var tokenInRule []interface{}
+	for _, tokenInItem := range tokenIn {
+		tokenInRule = append(tokenInRule, tokenInItem)
+	}
+	var tokenOutRule []interface{}
+	for _, tokenOutItem := range tokenOut {
+		tokenOutRule = append(tokenOutRule, tokenOutItem)
+	}
+
+	logs, sub, err := _Vault.contract.FilterLogs(opts, "Swap", poolIdRule, tokenInRule, tokenOutRule)
+	if err != nil {
+		return nil, err
+	}
+	return &VaultSwapIterator{contract: _Vault.contract, event: "Swap", logs: logs, sub: sub}, nil
+}
+
+// WatchSwap is a free log subscription operation binding the contract event 0x2170c741c41531aec20e7c107c24eecfdd15e69c9bb0a8dd37b1840b9e0b207b.
+//
+// Solidity: event Swap(bytes32 indexed poolId, address indexed tokenIn, address indexed tokenOut, uint256 amountIn, uint256 amountOut)
+func (_Vault *VaultFilterer) WatchSwap(opts *bind.WatchOpts, sink chan<- *VaultSwap, poolId [][32]byte, tokenIn []common.Address, tokenOut []common.Address) (event.Subscription, error) {
+
+	var poolIdRule []interface{}
+	for _, poolIdItem := range poolId {
+		poolIdRule = append(poolIdRule, poolIdItem)
+	}
+	var tokenInRule []interface{}
+	for _, tokenInItem := range tokenIn {
+		tokenInRule = append(tokenInRule, tokenInItem)
+	}
+	var tokenOutRule []interface{}
+	for _, tokenOutItem := range tokenOut {
+		tokenOutRule = append(tokenOutRule, tokenOutItem)
+	}
+
+	logs, sub, err := _Vault.contract.WatchLogs(opts, "Swap", poolIdRule, tokenInRule, tokenOutRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(VaultSwap)
+				if err := _Vault.contract.UnpackLog(event, "Swap", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseSwap is a log parse operation binding the contract event 0x2170c741c41531aec20e7c107c24eecfdd15e69c9bb0a8dd37b1840b9e0b207b.
+//
+// Solidity: event Swap(bytes32 indexed poolId, address indexed tokenIn, address indexed tokenOut, uint256 amountIn, uint256 amountOut)
+func (_Vault *VaultFilterer) ParseSwap(log types.Log) (*VaultSwap, error) {
+	event := new(VaultSwap)
+	if err := _Vault.contract.UnpackLog(event, "Swap", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
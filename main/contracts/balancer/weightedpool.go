@@ -0,0 +1,439 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package balancerpool
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// WeightedpoolMetaData contains all meta data concerning the Weightedpool contract.
+var WeightedpoolMetaData = &bind.MetaData{
+	ABI: "[{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"swapFeePercentage\",\"type\":\"uint256\"}],\"name\":\"SwapFeePercentageChanged\",\"type\":\"event\"},{\"inputs\":[],\"name\":\"getNormalizedWeights\",\"outputs\":[{\"internalType\":\"uint256[]\",\"name\":\"\",\"type\":\"uint256[]\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getPoolId\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getSwapFeePercentage\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getVault\",\"outputs\":[{\"internalType\":\"contractIVault\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]",
+}
+
+// WeightedpoolABI is the input ABI used to generate the binding from.
+// Deprecated: Use WeightedpoolMetaData.ABI instead.
+var WeightedpoolABI = WeightedpoolMetaData.ABI
+
+// Weightedpool is an auto generated Go binding around an Ethereum contract.
+type Weightedpool struct {
+	WeightedpoolCaller     // Read-only binding to the contract
+	WeightedpoolTransactor // Write-only binding to the contract
+	WeightedpoolFilterer   // Log filterer for contract events
+}
+
+// WeightedpoolCaller is an auto generated read-only Go binding around an Ethereum contract.
+type WeightedpoolCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// WeightedpoolTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type WeightedpoolTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// WeightedpoolFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type WeightedpoolFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// WeightedpoolSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type WeightedpoolSession struct {
+	Contract     *Weightedpool     // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// WeightedpoolCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type WeightedpoolCallerSession struct {
+	Contract *WeightedpoolCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts       // Call options to use throughout this session
+}
+
+// WeightedpoolTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type WeightedpoolTransactorSession struct {
+	Contract     *WeightedpoolTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts       // Transaction auth options to use throughout this session
+}
+
+// WeightedpoolRaw is an auto generated low-level Go binding around an Ethereum contract.
+type WeightedpoolRaw struct {
+	Contract *Weightedpool // Generic contract binding to access the raw methods on
+}
+
+// WeightedpoolCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type WeightedpoolCallerRaw struct {
+	Contract *WeightedpoolCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// WeightedpoolTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type WeightedpoolTransactorRaw struct {
+	Contract *WeightedpoolTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewWeightedpool creates a new instance of Weightedpool, bound to a specific deployed contract.
+func NewWeightedpool(address common.Address, backend bind.ContractBackend) (*Weightedpool, error) {
+	contract, err := bindWeightedpool(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Weightedpool{WeightedpoolCaller: WeightedpoolCaller{contract: contract}, WeightedpoolTransactor: WeightedpoolTransactor{contract: contract}, WeightedpoolFilterer: WeightedpoolFilterer{contract: contract}}, nil
+}
+
+// NewWeightedpoolCaller creates a new read-only instance of Weightedpool, bound to a specific deployed contract.
+func NewWeightedpoolCaller(address common.Address, caller bind.ContractCaller) (*WeightedpoolCaller, error) {
+	contract, err := bindWeightedpool(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &WeightedpoolCaller{contract: contract}, nil
+}
+
+// NewWeightedpoolTransactor creates a new write-only instance of Weightedpool, bound to a specific deployed contract.
+func NewWeightedpoolTransactor(address common.Address, transactor bind.ContractTransactor) (*WeightedpoolTransactor, error) {
+	contract, err := bindWeightedpool(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &WeightedpoolTransactor{contract: contract}, nil
+}
+
+// NewWeightedpoolFilterer creates a new log filterer instance of Weightedpool, bound to a specific deployed contract.
+func NewWeightedpoolFilterer(address common.Address, filterer bind.ContractFilterer) (*WeightedpoolFilterer, error) {
+	contract, err := bindWeightedpool(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &WeightedpoolFilterer{contract: contract}, nil
+}
+
+// bindWeightedpool binds a generic wrapper to an already deployed contract.
+func bindWeightedpool(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := WeightedpoolMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Weightedpool *WeightedpoolRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Weightedpool.Contract.WeightedpoolCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Weightedpool *WeightedpoolRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Weightedpool.Contract.WeightedpoolTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Weightedpool *WeightedpoolRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Weightedpool.Contract.WeightedpoolTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Weightedpool *WeightedpoolCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Weightedpool.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Weightedpool *WeightedpoolTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Weightedpool.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Weightedpool *WeightedpoolTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Weightedpool.Contract.contract.Transact(opts, method, params...)
+}
+
+// GetNormalizedWeights is a free data retrieval call binding the contract method 0xf89f27ed.
+//
+// Solidity: function getNormalizedWeights() view returns(uint256[])
+func (_Weightedpool *WeightedpoolCaller) GetNormalizedWeights(opts *bind.CallOpts) ([]*big.Int, error) {
+	var out []interface{}
+	err := _Weightedpool.contract.Call(opts, &out, "getNormalizedWeights")
+
+	if err != nil {
+		return *new([]*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([]*big.Int)).(*[]*big.Int)
+
+	return out0, err
+
+}
+
+// GetNormalizedWeights is a free data retrieval call binding the contract method 0xf89f27ed.
+//
+// Solidity: function getNormalizedWeights() view returns(uint256[])
+func (_Weightedpool *WeightedpoolSession) GetNormalizedWeights() ([]*big.Int, error) {
+	return _Weightedpool.Contract.GetNormalizedWeights(&_Weightedpool.CallOpts)
+}
+
+// GetNormalizedWeights is a free data retrieval call binding the contract method 0xf89f27ed.
+//
+// Solidity: function getNormalizedWeights() view returns(uint256[])
+func (_Weightedpool *WeightedpoolCallerSession) GetNormalizedWeights() ([]*big.Int, error) {
+	return _Weightedpool.Contract.GetNormalizedWeights(&_Weightedpool.CallOpts)
+}
+
+// GetPoolId is a free data retrieval call binding the contract method 0x38fff2d0.
+//
+// Solidity: function getPoolId() view returns(bytes32)
+func (_Weightedpool *WeightedpoolCaller) GetPoolId(opts *bind.CallOpts) ([32]byte, error) {
+	var out []interface{}
+	err := _Weightedpool.contract.Call(opts, &out, "getPoolId")
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// GetPoolId is a free data retrieval call binding the contract method 0x38fff2d0.
+//
+// Solidity: function getPoolId() view returns(bytes32)
+func (_Weightedpool *WeightedpoolSession) GetPoolId() ([32]byte, error) {
+	return _Weightedpool.Contract.GetPoolId(&_Weightedpool.CallOpts)
+}
+
+// GetPoolId is a free data retrieval call binding the contract method 0x38fff2d0.
+//
+// Solidity: function getPoolId() view returns(bytes32)
+func (_Weightedpool *WeightedpoolCallerSession) GetPoolId() ([32]byte, error) {
+	return _Weightedpool.Contract.GetPoolId(&_Weightedpool.CallOpts)
+}
+
+// GetSwapFeePercentage is a free data retrieval call binding the contract method 0x55c67628.
+//
+// Solidity: function getSwapFeePercentage() view returns(uint256)
+func (_Weightedpool *WeightedpoolCaller) GetSwapFeePercentage(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _Weightedpool.contract.Call(opts, &out, "getSwapFeePercentage")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetSwapFeePercentage is a free data retrieval call binding the contract method 0x55c67628.
+//
+// Solidity: function getSwapFeePercentage() view returns(uint256)
+func (_Weightedpool *WeightedpoolSession) GetSwapFeePercentage() (*big.Int, error) {
+	return _Weightedpool.Contract.GetSwapFeePercentage(&_Weightedpool.CallOpts)
+}
+
+// GetSwapFeePercentage is a free data retrieval call binding the contract method 0x55c67628.
+//
+// Solidity: function getSwapFeePercentage() view returns(uint256)
+func (_Weightedpool *WeightedpoolCallerSession) GetSwapFeePercentage() (*big.Int, error) {
+	return _Weightedpool.Contract.GetSwapFeePercentage(&_Weightedpool.CallOpts)
+}
+
+// GetVault is a free data retrieval call binding the contract method 0x8d928af8.
+//
+// Solidity: function getVault() view returns(address)
+func (_Weightedpool *WeightedpoolCaller) GetVault(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _Weightedpool.contract.Call(opts, &out, "getVault")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// GetVault is a free data retrieval call binding the contract method 0x8d928af8.
+//
+// Solidity: function getVault() view returns(address)
+func (_Weightedpool *WeightedpoolSession) GetVault() (common.Address, error) {
+	return _Weightedpool.Contract.GetVault(&_Weightedpool.CallOpts)
+}
+
+// GetVault is a free data retrieval call binding the contract method 0x8d928af8.
+//
+// Solidity: function getVault() view returns(address)
+func (_Weightedpool *WeightedpoolCallerSession) GetVault() (common.Address, error) {
+	return _Weightedpool.Contract.GetVault(&_Weightedpool.CallOpts)
+}
+
+// WeightedpoolSwapFeePercentageChangedIterator is returned from FilterSwapFeePercentageChanged and is used to iterate over the raw logs and unpacked data for SwapFeePercentageChanged events raised by the Weightedpool contract.
+type WeightedpoolSwapFeePercentageChangedIterator struct {
+	Event *WeightedpoolSwapFeePercentageChanged // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *WeightedpoolSwapFeePercentageChangedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(WeightedpoolSwapFeePercentageChanged)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(WeightedpoolSwapFeePercentageChanged)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *WeightedpoolSwapFeePercentageChangedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *WeightedpoolSwapFeePercentageChangedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// WeightedpoolSwapFeePercentageChanged represents a SwapFeePercentageChanged event raised by the Weightedpool contract.
+type WeightedpoolSwapFeePercentageChanged struct {
+	SwapFeePercentage *big.Int
+	Raw               types.Log // Blockchain specific contextual infos
+}
+
+// FilterSwapFeePercentageChanged is a free log retrieval operation binding the contract event 0xa9ba3ffe0b6c366b81232caab38605a0699ad5398d6cce76f91ee809e322dafc.
+//
+// Solidity: event SwapFeePercentageChanged(uint256 swapFeePercentage)
+func (_Weightedpool *WeightedpoolFilterer) FilterSwapFeePercentageChanged(opts *bind.FilterOpts) (*WeightedpoolSwapFeePercentageChangedIterator, error) {
+
+	logs, sub, err := _Weightedpool.contract.FilterLogs(opts, "SwapFeePercentageChanged")
+	if err != nil {
+		return nil, err
+	}
+	return &WeightedpoolSwapFeePercentageChangedIterator{contract: _Weightedpool.contract, event: "SwapFeePercentageChanged", logs: logs, sub: sub}, nil
+}
+
+// WatchSwapFeePercentageChanged is a free log subscription operation binding the contract event 0xa9ba3ffe0b6c366b81232caab38605a0699ad5398d6cce76f91ee809e322dafc.
+//
+// Solidity: event SwapFeePercentageChanged(uint256 swapFeePercentage)
+func (_Weightedpool *WeightedpoolFilterer) WatchSwapFeePercentageChanged(opts *bind.WatchOpts, sink chan<- *WeightedpoolSwapFeePercentageChanged) (event.Subscription, error) {
+
+	logs, sub, err := _Weightedpool.contract.WatchLogs(opts, "SwapFeePercentageChanged")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(WeightedpoolSwapFeePercentageChanged)
+				if err := _Weightedpool.contract.UnpackLog(event, "SwapFeePercentageChanged", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseSwapFeePercentageChanged is a log parse operation binding the contract event 0xa9ba3ffe0b6c366b81232caab38605a0699ad5398d6cce76f91ee809e322dafc.
+//
+// Solidity: event SwapFeePercentageChanged(uint256 swapFeePercentage)
+func (_Weightedpool *WeightedpoolFilterer) ParseSwapFeePercentageChanged(log types.Log) (*WeightedpoolSwapFeePercentageChanged, error) {
+	event := new(WeightedpoolSwapFeePercentageChanged)
+	if err := _Weightedpool.contract.UnpackLog(event, "SwapFeePercentageChanged", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
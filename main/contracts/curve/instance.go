@@ -0,0 +1,324 @@
+package curvepool
+
+// using same package as stableswap.go generated with abigen from StableSwap.abi
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"bb/executor"
+	"bb/types"
+)
+
+// Pool is a StableSwap pool with N coins. It is not a types.Pair itself;
+// every coin pair in the pool is exposed as an Edge through Pairs.
+type Pool struct {
+	AddressString string
+	Client        bind.ContractBackend
+	PoolInterface *Stableswap
+	DEXName       string
+	AssetNames    []string
+	AssetDecimals []int64
+
+	rates      []*big.Int
+	monitorMu  sync.Mutex
+	monitoring bool          // an edge is monitoring the pool
+	released   chan struct{} // closed when that edge stops
+
+	mu          sync.RWMutex
+	balances    []*big.Int
+	amp         *big.Int
+	fee         *big.Int // in units of 1e-10
+	blockNumber uint64
+	coins       []common.Address // read once by PairTokens
+}
+
+// Edge is the types.Pair view of coins I and J of a pool.
+type Edge struct {
+	pool *Pool
+	I, J int
+}
+
+func (e *Edge) Asset1() string {
+	return e.pool.AssetNames[e.I]
+}
+
+func (e *Edge) Asset2() string {
+	return e.pool.AssetNames[e.J]
+}
+
+func (e *Edge) DEX() string {
+	return e.pool.DEXName
+}
+
+func (e *Edge) Address() string {
+	return e.pool.AddressString
+}
+
+// NewPool binds a StableSwap pool whose coins, in index order, are named
+// assetNames with the given decimals, and loads its balances, A and fee.
+func NewPool(address string, client bind.ContractBackend, assetNames []string, assetDecimals []int64) (*Pool, error) {
+	if len(assetNames) != len(assetDecimals) || len(assetNames) < 2 {
+		return nil, fmt.Errorf("curve pool %s needs at least two coins with decimals", address)
+	}
+
+	pool, err := NewStableswap(common.HexToAddress(address), client)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Pool{
+		AddressString: address,
+		Client:        client,
+		PoolInterface: pool,
+		DEXName:       "Curve",
+		AssetNames:    assetNames,
+		AssetDecimals: assetDecimals,
+	}
+	for _, decimals := range assetDecimals {
+		p.rates = append(p.rates, rate(decimals))
+	}
+	if err := p.Load(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to load curve pool %s: %v", address, err)
+	}
+	return p, nil
+}
+
+// Pairs returns an edge for every unordered coin pair in the pool.
+func (p *Pool) Pairs() []types.Pair {
+	var pairs []types.Pair
+	for i := range p.AssetNames {
+		for j := i + 1; j < len(p.AssetNames); j++ {
+			pairs = append(pairs, &Edge{pool: p, I: i, J: j})
+		}
+	}
+	return pairs
+}
+
+// Load refreshes balances, amplification coefficient and fee from the pool.
+func (p *Pool) Load(ctx context.Context) error {
+	opts := &bind.CallOpts{Context: ctx}
+
+	balances := make([]*big.Int, len(p.AssetNames))
+	for i := range balances {
+		balance, err := p.PoolInterface.Balances(opts, big.NewInt(int64(i)))
+		if err != nil {
+			return fmt.Errorf("failed to read balance %d: %v", i, err)
+		}
+		balances[i] = balance
+	}
+	amp, err := p.PoolInterface.A(opts)
+	if err != nil {
+		return fmt.Errorf("failed to read A: %v", err)
+	}
+	fee, err := p.PoolInterface.Fee(opts)
+	if err != nil {
+		return fmt.Errorf("failed to read fee: %v", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.balances = balances
+	p.amp = amp
+	p.fee = fee
+	return nil
+}
+
+// GetDy quotes coin i to coin j offline, matching the pool's get_dy.
+func (p *Pool) GetDy(i, j int, dx *big.Int) *big.Int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return getDy(i, j, dx, p.balances, p.rates, p.amp, p.fee)
+}
+
+func (e *Edge) Quote(assetIn string, amountIn *big.Int) (*big.Int, error) {
+	switch assetIn {
+	case e.Asset1():
+		return e.pool.GetDy(e.I, e.J, amountIn), nil
+	case e.Asset2():
+		return e.pool.GetDy(e.J, e.I, amountIn), nil
+	}
+	return nil, fmt.Errorf("%s is not traded on %s %s/%s", assetIn, e.DEX(), e.Asset1(), e.Asset2())
+}
+
+// swapEvent quotes one whole token in each direction to get the marginal
+// rates of the edge.
+func (e *Edge) swapEvent() types.SwapEvent {
+	p := e.pool
+	oneI := new(big.Int).Exp(big.NewInt(10), big.NewInt(p.AssetDecimals[e.I]), nil)
+	oneJ := new(big.Int).Exp(big.NewInt(10), big.NewInt(p.AssetDecimals[e.J]), nil)
+
+	forward := new(big.Float).Quo(new(big.Float).SetInt(p.GetDy(e.I, e.J, oneI)), new(big.Float).SetInt(oneJ))
+	backward := new(big.Float).Quo(new(big.Float).SetInt(p.GetDy(e.J, e.I, oneJ)), new(big.Float).SetInt(oneI))
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return types.SwapEvent{
+		DEXName:     p.DEXName,
+		Asset1Name:  e.Asset1(),
+		Asset2Name:  e.Asset2(),
+		Address:     p.AddressString,
+		AmountOut:   types.AmountOut{Amount1: forward, Amount2: backward},
+		Reserve1:    new(big.Float).Quo(new(big.Float).SetInt(p.balances[e.I]), new(big.Float).SetInt(oneI)),
+		Reserve2:    new(big.Float).Quo(new(big.Float).SetInt(p.balances[e.J]), new(big.Float).SetInt(oneJ)),
+		BlockNumber: p.blockNumber,
+	}
+}
+
+// PairTokens returns the tokens of coins I and J, reading the pool's coin
+// addresses once.
+func (e *Edge) PairTokens(ctx context.Context) (types.Token, types.Token, error) {
+	p := e.pool
+	p.mu.RLock()
+	coins := p.coins
+	p.mu.RUnlock()
+	if coins == nil {
+		opts := &bind.CallOpts{Context: ctx}
+		for i := range p.AssetNames {
+			coin, err := p.PoolInterface.Coins(opts, big.NewInt(int64(i)))
+			if err != nil {
+				return types.Token{}, types.Token{}, fmt.Errorf("failed to read coin %d of %s: %v", i, p.AddressString, err)
+			}
+			coins = append(coins, coin)
+		}
+		p.mu.Lock()
+		p.coins = coins
+		p.mu.Unlock()
+	}
+	return types.Token{Asset: e.Asset1(), Address: coins[e.I], Decimals: p.AssetDecimals[e.I]},
+		types.Token{Asset: e.Asset2(), Address: coins[e.J], Decimals: p.AssetDecimals[e.J]}, nil
+}
+
+// Snapshot reloads the pool and describes the edge as a swap event.
+func (e *Edge) Snapshot(ctx context.Context) (types.SwapEvent, error) {
+	if err := e.pool.Load(ctx); err != nil {
+		return types.SwapEvent{}, err
+	}
+	return e.swapEvent(), nil
+}
+
+// Monitor watches the pool on behalf of all of its edges: one edge at a time
+// subscribes and reports every edge, the others wait and take over when it
+// stops. It returns nil once ctx is cancelled, or the error that stopped the
+// subscription.
+func (e *Edge) Monitor(ctx context.Context, swapEventChan chan<- types.SwapEvent) error {
+	for {
+		released, ok := e.pool.claimMonitor()
+		if ok {
+			defer e.pool.releaseMonitor()
+			return e.pool.monitor(ctx, swapEventChan)
+		}
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// claimMonitor makes the caller the pool's monitor if there is none, and
+// otherwise returns a channel closed when the current one stops.
+func (p *Pool) claimMonitor() (<-chan struct{}, bool) {
+	p.monitorMu.Lock()
+	defer p.monitorMu.Unlock()
+	if p.monitoring {
+		return p.released, false
+	}
+	p.monitoring, p.released = true, make(chan struct{})
+	return nil, true
+}
+
+func (p *Pool) releaseMonitor() {
+	p.monitorMu.Lock()
+	defer p.monitorMu.Unlock()
+	p.monitoring = false
+	close(p.released)
+}
+
+func (p *Pool) monitor(ctx context.Context, swapEventChan chan<- types.SwapEvent) error {
+	// Any log from the pool (exchanges, liquidity changes, A ramps) can move
+	// the balances, so follow them all rather than binding each event.
+	logChan := make(chan ethtypes.Log)
+	query := ethereum.FilterQuery{Addresses: []common.Address{common.HexToAddress(p.AddressString)}}
+	sub, err := p.Client.SubscribeFilterLogs(ctx, query, logChan)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %v", p.AddressString, err)
+	}
+	defer sub.Unsubscribe()
+
+	edges := p.Pairs()
+	for _, edge := range edges {
+		log.Printf("Listening for swap events: %s/%s on %s (%s)", edge.Asset1(), edge.Asset2(), p.DEXName, p.AddressString)
+	}
+
+	for {
+		select {
+		case err := <-sub.Err():
+			return fmt.Errorf("subscription error: %v", err)
+		case <-ctx.Done():
+			return nil
+		case l := <-logChan:
+			if err := p.Load(ctx); err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return err
+			}
+			p.mu.Lock()
+			p.blockNumber = l.BlockNumber
+			p.mu.Unlock()
+
+			// Send update to channel
+			for _, edge := range edges {
+				select {
+				case swapEventChan <- edge.(*Edge).swapEvent():
+				case <-ctx.Done():
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// SwapCalls has the executor approve the pool for its balance of assetIn and
+// exchange all of it for at least minOut.
+func (e *Edge) SwapCalls(exec, tokenIn common.Address, assetIn string, minOut *big.Int) ([]types.Call, error) {
+	i, j := e.I, e.J
+	switch assetIn {
+	case e.Asset1():
+	case e.Asset2():
+		i, j = e.J, e.I
+	default:
+		return nil, fmt.Errorf("%s is not traded on %s %s/%s", assetIn, e.DEX(), e.Asset1(), e.Asset2())
+	}
+	if minOut == nil {
+		minOut = new(big.Int)
+	}
+
+	pool := common.HexToAddress(e.pool.AddressString)
+	approve, err := executor.Approve(tokenIn, pool)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := StableswapMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	data, err := parsed.Pack("exchange", big.NewInt(int64(i)), big.NewInt(int64(j)), executor.Balance, minOut)
+	if err != nil {
+		return nil, err
+	}
+	exchange, err := executor.Spend(pool, data, tokenIn)
+	if err != nil {
+		return nil, err
+	}
+	return []types.Call{approve, exchange}, nil
+}
@@ -0,0 +1,126 @@
+package curvepool
+
+// Offline port of the StableSwap invariant as implemented in the Vyper pools
+// (3pool and its clones). All amounts are integers so quotes match get_dy.
+
+import "math/big"
+
+var (
+	precision      = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	feeDenominator = new(big.Int).Exp(big.NewInt(10), big.NewInt(10), nil)
+)
+
+// rate returns the multiplier that normalises a balance with the given
+// decimals to 18 decimals, scaled by PRECISION as the pools store it.
+func rate(decimals int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(36-decimals), nil)
+}
+
+func xp(balances, rates []*big.Int) []*big.Int {
+	result := make([]*big.Int, len(balances))
+	for i := range balances {
+		result[i] = mulDiv(balances[i], rates[i], precision)
+	}
+	return result
+}
+
+func mulDiv(a, b, denominator *big.Int) *big.Int {
+	product := new(big.Int).Mul(a, b)
+	return product.Quo(product, denominator)
+}
+
+func withinOne(a, b *big.Int) bool {
+	diff := new(big.Int).Sub(a, b)
+	return diff.CmpAbs(big.NewInt(1)) <= 0
+}
+
+// getD solves the invariant for D by Newton's method.
+func getD(xp []*big.Int, amp *big.Int) *big.Int {
+	n := big.NewInt(int64(len(xp)))
+	s := new(big.Int)
+	for _, x := range xp {
+		s.Add(s, x)
+	}
+	if s.Sign() == 0 {
+		return s
+	}
+
+	d := new(big.Int).Set(s)
+	ann := new(big.Int).Mul(amp, n)
+	for k := 0; k < 255; k++ {
+		dP := new(big.Int).Set(d)
+		for _, x := range xp {
+			dP = mulDiv(dP, d, new(big.Int).Mul(x, n))
+		}
+		dPrev := d
+
+		// D = (Ann * S + D_P * N) * D / ((Ann - 1) * D + (N + 1) * D_P)
+		numerator := new(big.Int).Add(new(big.Int).Mul(ann, s), new(big.Int).Mul(dP, n))
+		numerator.Mul(numerator, d)
+		denominator := new(big.Int).Mul(new(big.Int).Sub(ann, big.NewInt(1)), d)
+		denominator.Add(denominator, new(big.Int).Mul(new(big.Int).Add(n, big.NewInt(1)), dP))
+		d = numerator.Quo(numerator, denominator)
+
+		if withinOne(d, dPrev) {
+			break
+		}
+	}
+	return d
+}
+
+// getY returns the new balance of coin j when coin i's normalised balance is
+// set to x, holding D constant.
+func getY(i, j int, x *big.Int, xp []*big.Int, amp *big.Int) *big.Int {
+	n := big.NewInt(int64(len(xp)))
+	d := getD(xp, amp)
+	c := new(big.Int).Set(d)
+	s := new(big.Int)
+	ann := new(big.Int).Mul(amp, n)
+
+	for k := range xp {
+		var xk *big.Int
+		switch {
+		case k == i:
+			xk = x
+		case k != j:
+			xk = xp[k]
+		default:
+			continue
+		}
+		s.Add(s, xk)
+		c = mulDiv(c, d, new(big.Int).Mul(xk, n))
+	}
+	c = mulDiv(c, d, new(big.Int).Mul(ann, n))
+	b := new(big.Int).Add(s, new(big.Int).Quo(d, ann))
+
+	y := new(big.Int).Set(d)
+	for k := 0; k < 255; k++ {
+		yPrev := y
+		// y = (y*y + c) / (2*y + b - D)
+		numerator := new(big.Int).Add(new(big.Int).Mul(y, y), c)
+		denominator := new(big.Int).Add(new(big.Int).Lsh(y, 1), b)
+		denominator.Sub(denominator, d)
+		y = numerator.Quo(numerator, denominator)
+
+		if withinOne(y, yPrev) {
+			break
+		}
+	}
+	return y
+}
+
+// getDy mirrors the pool's get_dy: the amount of coin j received for dx of
+// coin i, after the swap fee.
+func getDy(i, j int, dx *big.Int, balances, rates []*big.Int, amp, fee *big.Int) *big.Int {
+	normalised := xp(balances, rates)
+	x := new(big.Int).Add(normalised[i], mulDiv(dx, rates[i], precision))
+	y := getY(i, j, x, normalised, amp)
+
+	dy := new(big.Int).Sub(normalised[j], y)
+	dy.Sub(dy, big.NewInt(1))
+	dy = mulDiv(dy, precision, rates[j])
+	if dy.Sign() < 0 {
+		return new(big.Int)
+	}
+	return dy.Sub(dy, mulDiv(fee, dy, feeDenominator))
+}
@@ -0,0 +1,127 @@
+package curvepool
+
+import (
+	"math/big"
+	"testing"
+)
+
+const refPrec = 256
+
+// referenceDy solves the StableSwap invariant by bisection in 256-bit floats:
+// D for the pool's balances, then coin j's balance that keeps D once dx of
+// coin i is in. Balances and amounts are in whole coins.
+func referenceDy(i, j int, dx float64, balances []float64, amp, fee float64) float64 {
+	n := float64(len(balances))
+	ann := newFloat(amp * n)
+	f := func(x []*big.Float, d *big.Float) *big.Float {
+		// Ann*S + D - Ann*D - D^(n+1) / (n^n * prod(x))
+		s, dP := newFloat(0), new(big.Float).SetPrec(refPrec).Set(d)
+		for _, xk := range x {
+			s.Add(s, xk)
+			dP.Mul(dP, d)
+			dP.Quo(dP, new(big.Float).Mul(xk, newFloat(n)))
+		}
+		result := new(big.Float).Mul(ann, s)
+		result.Add(result, d)
+		result.Sub(result, new(big.Float).Mul(ann, d))
+		return result.Sub(result, dP)
+	}
+	// bisect finds the root of g between low and high, where g(low) and
+	// g(high) have the signs lowSign and its opposite.
+	bisect := func(low, high *big.Float, lowSign int, g func(*big.Float) *big.Float) *big.Float {
+		for k := 0; k < refPrec; k++ {
+			mid := new(big.Float).Add(low, high)
+			mid.Quo(mid, newFloat(2))
+			if g(mid).Sign() == lowSign {
+				low = mid
+			} else {
+				high = mid
+			}
+		}
+		return low
+	}
+
+	x, sum := make([]*big.Float, len(balances)), 0.0
+	for k, b := range balances {
+		x[k] = newFloat(b)
+		sum += b
+	}
+	d := bisect(newFloat(0), newFloat(sum), 1, func(d *big.Float) *big.Float { return f(x, d) })
+
+	x[i] = newFloat(balances[i] + dx)
+	y := bisect(newFloat(0), newFloat(sum+dx), -1, func(y *big.Float) *big.Float {
+		x[j] = y
+		return f(x, d)
+	})
+	dy, _ := new(big.Float).Sub(newFloat(balances[j]), y).Float64()
+	return dy * (1 - fee/1e10)
+}
+
+func newFloat(x float64) *big.Float {
+	return new(big.Float).SetPrec(refPrec).SetFloat64(x)
+}
+
+// units converts whole coins to a raw amount with the given decimals.
+func units(whole float64, decimals int64) *big.Int {
+	amount, _ := new(big.Float).Mul(big.NewFloat(whole), new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(decimals), nil))).Int(nil)
+	return amount
+}
+
+func TestGetDy(t *testing.T) {
+	cases := []struct {
+		name     string
+		balances []float64
+		decimals []int64
+		amp      int64
+		i, j     int
+		dx       float64
+	}{
+		{"balanced, 6 to 18 decimals", []float64{1e6, 1e6}, []int64{6, 18}, 100, 0, 1, 1000},
+		{"balanced, 18 to 6 decimals", []float64{1e6, 1e6}, []int64{6, 18}, 100, 1, 0, 1000},
+		{"three coins, imbalanced", []float64{5e7, 3e7, 8e7}, []int64{18, 6, 6}, 2000, 2, 1, 1e6},
+		{"A of 1, a tenth of the pool", []float64{1e6, 1e6}, []int64{18, 18}, 1, 0, 1, 1e5},
+		{"largest A, ten to one", []float64{1e7, 1e6}, []int64{18, 18}, 1000000, 0, 1, 1e5},
+		{"into the scarce coin", []float64{1e6, 1e3}, []int64{18, 18}, 100, 0, 1, 1e4},
+		{"out of the scarce coin", []float64{1e6, 1e3}, []int64{18, 18}, 100, 1, 0, 100},
+	}
+	fee := big.NewInt(4000000)
+	for _, c := range cases {
+		var balances, rates []*big.Int
+		for k, b := range c.balances {
+			balances = append(balances, units(b, c.decimals[k]))
+			rates = append(rates, rate(c.decimals[k]))
+		}
+		got := getDy(c.i, c.j, units(c.dx, c.decimals[c.i]), balances, rates, big.NewInt(c.amp), fee)
+		dy, _ := new(big.Float).Quo(new(big.Float).SetInt(got), new(big.Float).SetInt(units(1, c.decimals[c.j]))).Float64()
+
+		want := referenceDy(c.i, c.j, c.dx, c.balances, float64(c.amp), 4e6)
+		if diff := (dy - want) / want; diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("%s: got %g, want %g", c.name, dy, want)
+		}
+		if dy >= c.balances[c.j] {
+			t.Errorf("%s: got %g of a balance of %g", c.name, dy, c.balances[c.j])
+		}
+	}
+}
+
+// TestGetDyAmplification checks the invariant's limits: at A of 1 a swap pays
+// out more than a constant product pool would, at the largest A it pays out
+// nearly one for one, even ten to one out of balance.
+func TestGetDyAmplification(t *testing.T) {
+	balances := []*big.Int{units(1e6, 18), units(1e6, 18)}
+	rates := []*big.Int{rate(18), rate(18)}
+	dy := getDy(0, 1, units(1e5, 18), balances, rates, big.NewInt(1), new(big.Int))
+	if constantProduct := units(1e6-1e12/(1e6+1e5), 18); dy.Cmp(constantProduct) <= 0 || dy.Cmp(units(1e5, 18)) >= 0 {
+		t.Errorf("A of 1 paid %s for 1e23, constant product pays %s", dy, constantProduct)
+	}
+
+	balances = []*big.Int{units(1e7, 18), units(1e6, 18)}
+	dy = getDy(0, 1, units(1e3, 18), balances, rates, big.NewInt(1000000), new(big.Int))
+	if low := units(999.9, 18); dy.Cmp(low) < 0 || dy.Cmp(units(1e3, 18)) >= 0 {
+		t.Errorf("largest A paid %s for 1e21", dy)
+	}
+
+	if dy := getDy(0, 1, new(big.Int), balances, rates, big.NewInt(100), big.NewInt(4000000)); dy.Sign() != 0 {
+		t.Errorf("paid %s for nothing", dy)
+	}
+}
@@ -0,0 +1,505 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package curvepool
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// StableswapMetaData contains all meta data concerning the Stableswap contract.
+var StableswapMetaData = &bind.MetaData{
+	ABI: "[{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"buyer\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"sold_id\",\"type\":\"int128\"},{\"indexed\":false,\"name\":\"tokens_sold\",\"type\":\"uint256\"},{\"indexed\":false,\"name\":\"bought_id\",\"type\":\"int128\"},{\"indexed\":false,\"name\":\"tokens_bought\",\"type\":\"uint256\"}],\"name\":\"TokenExchange\",\"type\":\"event\"},{\"inputs\":[],\"name\":\"A\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"name\":\"arg0\",\"type\":\"uint256\"}],\"name\":\"balances\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"name\":\"arg0\",\"type\":\"uint256\"}],\"name\":\"coins\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"name\":\"i\",\"type\":\"int128\"},{\"name\":\"j\",\"type\":\"int128\"},{\"name\":\"dx\",\"type\":\"uint256\"},{\"name\":\"min_dy\",\"type\":\"uint256\"}],\"name\":\"exchange\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"fee\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"name\":\"i\",\"type\":\"int128\"},{\"name\":\"j\",\"type\":\"int128\"},{\"name\":\"dx\",\"type\":\"uint256\"}],\"name\":\"get_dy\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]",
+}
+
+// StableswapABI is the input ABI used to generate the binding from.
+// Deprecated: Use StableswapMetaData.ABI instead.
+var StableswapABI = StableswapMetaData.ABI
+
+// Stableswap is an auto generated Go binding around an Ethereum contract.
+type Stableswap struct {
+	StableswapCaller     // Read-only binding to the contract
+	StableswapTransactor // Write-only binding to the contract
+	StableswapFilterer   // Log filterer for contract events
+}
+
+// StableswapCaller is an auto generated read-only Go binding around an Ethereum contract.
+type StableswapCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// StableswapTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type StableswapTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// StableswapFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type StableswapFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// StableswapSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type StableswapSession struct {
+	Contract     *Stableswap       // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// StableswapCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type StableswapCallerSession struct {
+	Contract *StableswapCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts     // Call options to use throughout this session
+}
+
+// StableswapTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type StableswapTransactorSession struct {
+	Contract     *StableswapTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts     // Transaction auth options to use throughout this session
+}
+
+// StableswapRaw is an auto generated low-level Go binding around an Ethereum contract.
+type StableswapRaw struct {
+	Contract *Stableswap // Generic contract binding to access the raw methods on
+}
+
+// StableswapCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type StableswapCallerRaw struct {
+	Contract *StableswapCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// StableswapTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type StableswapTransactorRaw struct {
+	Contract *StableswapTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewStableswap creates a new instance of Stableswap, bound to a specific deployed contract.
+func NewStableswap(address common.Address, backend bind.ContractBackend) (*Stableswap, error) {
+	contract, err := bindStableswap(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Stableswap{StableswapCaller: StableswapCaller{contract: contract}, StableswapTransactor: StableswapTransactor{contract: contract}, StableswapFilterer: StableswapFilterer{contract: contract}}, nil
+}
+
+// NewStableswapCaller creates a new read-only instance of Stableswap, bound to a specific deployed contract.
+func NewStableswapCaller(address common.Address, caller bind.ContractCaller) (*StableswapCaller, error) {
+	contract, err := bindStableswap(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &StableswapCaller{contract: contract}, nil
+}
+
+// NewStableswapTransactor creates a new write-only instance of Stableswap, bound to a specific deployed contract.
+func NewStableswapTransactor(address common.Address, transactor bind.ContractTransactor) (*StableswapTransactor, error) {
+	contract, err := bindStableswap(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &StableswapTransactor{contract: contract}, nil
+}
+
+// NewStableswapFilterer creates a new log filterer instance of Stableswap, bound to a specific deployed contract.
+func NewStableswapFilterer(address common.Address, filterer bind.ContractFilterer) (*StableswapFilterer, error) {
+	contract, err := bindStableswap(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &StableswapFilterer{contract: contract}, nil
+}
+
+// bindStableswap binds a generic wrapper to an already deployed contract.
+func bindStableswap(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := StableswapMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Stableswap *StableswapRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Stableswap.Contract.StableswapCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Stableswap *StableswapRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Stableswap.Contract.StableswapTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Stableswap *StableswapRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Stableswap.Contract.StableswapTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Stableswap *StableswapCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Stableswap.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Stableswap *StableswapTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Stableswap.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Stableswap *StableswapTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Stableswap.Contract.contract.Transact(opts, method, params...)
+}
+
+// A is a free data retrieval call binding the contract method 0xf446c1d0.
+//
+// Solidity: function A() view returns(uint256)
+func (_Stableswap *StableswapCaller) A(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _Stableswap.contract.Call(opts, &out, "A")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// A is a free data retrieval call binding the contract method 0xf446c1d0.
+//
+// Solidity: function A() view returns(uint256)
+func (_Stableswap *StableswapSession) A() (*big.Int, error) {
+	return _Stableswap.Contract.A(&_Stableswap.CallOpts)
+}
+
+// A is a free data retrieval call binding the contract method 0xf446c1d0.
+//
+// Solidity: function A() view returns(uint256)
+func (_Stableswap *StableswapCallerSession) A() (*big.Int, error) {
+	return _Stableswap.Contract.A(&_Stableswap.CallOpts)
+}
+
+// Balances is a free data retrieval call binding the contract method 0x4903b0d1.
+//
+// Solidity: function balances(uint256 arg0) view returns(uint256)
+func (_Stableswap *StableswapCaller) Balances(opts *bind.CallOpts, arg0 *big.Int) (*big.Int, error) {
+	var out []interface{}
+	err := _Stableswap.contract.Call(opts, &out, "balances", arg0)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// Balances is a free data retrieval call binding the contract method 0x4903b0d1.
+//
+// Solidity: function balances(uint256 arg0) view returns(uint256)
+func (_Stableswap *StableswapSession) Balances(arg0 *big.Int) (*big.Int, error) {
+	return _Stableswap.Contract.Balances(&_Stableswap.CallOpts, arg0)
+}
+
+// Balances is a free data retrieval call binding the contract method 0x4903b0d1.
+//
+// Solidity: function balances(uint256 arg0) view returns(uint256)
+func (_Stableswap *StableswapCallerSession) Balances(arg0 *big.Int) (*big.Int, error) {
+	return _Stableswap.Contract.Balances(&_Stableswap.CallOpts, arg0)
+}
+
+// Coins is a free data retrieval call binding the contract method 0xc6610657.
+//
+// Solidity: function coins(uint256 arg0) view returns(address)
+func (_Stableswap *StableswapCaller) Coins(opts *bind.CallOpts, arg0 *big.Int) (common.Address, error) {
+	var out []interface{}
+	err := _Stableswap.contract.Call(opts, &out, "coins", arg0)
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Coins is a free data retrieval call binding the contract method 0xc6610657.
+//
+// Solidity: function coins(uint256 arg0) view returns(address)
+func (_Stableswap *StableswapSession) Coins(arg0 *big.Int) (common.Address, error) {
+	return _Stableswap.Contract.Coins(&_Stableswap.CallOpts, arg0)
+}
+
+// Coins is a free data retrieval call binding the contract method 0xc6610657.
+//
+// Solidity: function coins(uint256 arg0) view returns(address)
+func (_Stableswap *StableswapCallerSession) Coins(arg0 *big.Int) (common.Address, error) {
+	return _Stableswap.Contract.Coins(&_Stableswap.CallOpts, arg0)
+}
+
+// Fee is a free data retrieval call binding the contract method 0xddca3f43.
+//
+// Solidity: function fee() view returns(uint256)
+func (_Stableswap *StableswapCaller) Fee(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _Stableswap.contract.Call(opts, &out, "fee")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// Fee is a free data retrieval call binding the contract method 0xddca3f43.
+//
+// Solidity: function fee() view returns(uint256)
+func (_Stableswap *StableswapSession) Fee() (*big.Int, error) {
+	return _Stableswap.Contract.Fee(&_Stableswap.CallOpts)
+}
+
+// Fee is a free data retrieval call binding the contract method 0xddca3f43.
+//
+// Solidity: function fee() view returns(uint256)
+func (_Stableswap *StableswapCallerSession) Fee() (*big.Int, error) {
+	return _Stableswap.Contract.Fee(&_Stableswap.CallOpts)
+}
+
+// GetDy is a free data retrieval call binding the contract method 0x5e0d443f.
+//
+// Solidity: function get_dy(int128 i, int128 j, uint256 dx) view returns(uint256)
+func (_Stableswap *StableswapCaller) GetDy(opts *bind.CallOpts, i *big.Int, j *big.Int, dx *big.Int) (*big.Int, error) {
+	var out []interface{}
+	err := _Stableswap.contract.Call(opts, &out, "get_dy", i, j, dx)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetDy is a free data retrieval call binding the contract method 0x5e0d443f.
+//
+// Solidity: function get_dy(int128 i, int128 j, uint256 dx) view returns(uint256)
+func (_Stableswap *StableswapSession) GetDy(i *big.Int, j *big.Int, dx *big.Int) (*big.Int, error) {
+	return _Stableswap.Contract.GetDy(&_Stableswap.CallOpts, i, j, dx)
+}
+
+// GetDy is a free data retrieval call binding the contract method 0x5e0d443f.
+//
+// Solidity: function get_dy(int128 i, int128 j, uint256 dx) view returns(uint256)
+func (_Stableswap *StableswapCallerSession) GetDy(i *big.Int, j *big.Int, dx *big.Int) (*big.Int, error) {
+	return _Stableswap.Contract.GetDy(&_Stableswap.CallOpts, i, j, dx)
+}
+
+// Exchange is a paid mutator transaction binding the contract method 0x3df02124.
+//
+// Solidity: function exchange(int128 i, int128 j, uint256 dx, uint256 min_dy) returns()
+func (_Stableswap *StableswapTransactor) Exchange(opts *bind.TransactOpts, i *big.Int, j *big.Int, dx *big.Int, min_dy *big.Int) (*types.Transaction, error) {
+	return _Stableswap.contract.Transact(opts, "exchange", i, j, dx, min_dy)
+}
+
+// Exchange is a paid mutator transaction binding the contract method 0x3df02124.
+//
+// Solidity: function exchange(int128 i, int128 j, uint256 dx, uint256 min_dy) returns()
+func (_Stableswap *StableswapSession) Exchange(i *big.Int, j *big.Int, dx *big.Int, min_dy *big.Int) (*types.Transaction, error) {
+	return _Stableswap.Contract.Exchange(&_Stableswap.TransactOpts, i, j, dx, min_dy)
+}
+
+// Exchange is a paid mutator transaction binding the contract method 0x3df02124.
+//
+// Solidity: function exchange(int128 i, int128 j, uint256 dx, uint256 min_dy) returns()
+func (_Stableswap *StableswapTransactorSession) Exchange(i *big.Int, j *big.Int, dx *big.Int, min_dy *big.Int) (*types.Transaction, error) {
+	return _Stableswap.Contract.Exchange(&_Stableswap.TransactOpts, i, j, dx, min_dy)
+}
+
+// StableswapTokenExchangeIterator is returned from FilterTokenExchange and is used to iterate over the raw logs and unpacked data for TokenExchange events raised by the Stableswap contract.
+type StableswapTokenExchangeIterator struct {
+	Event *StableswapTokenExchange // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *StableswapTokenExchangeIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(StableswapTokenExchange)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(StableswapTokenExchange)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *StableswapTokenExchangeIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *StableswapTokenExchangeIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// StableswapTokenExchange represents a TokenExchange event raised by the Stableswap contract.
+type StableswapTokenExchange struct {
+	Buyer        common.Address
+	SoldId       *big.Int
+	TokensSold   *big.Int
+	BoughtId     *big.Int
+	TokensBought *big.Int
+	Raw          types.Log // Blockchain specific contextual infos
+}
+
+// FilterTokenExchange is a free log retrieval operation binding the contract event 0x8b3e96f2b889fa771c53c981b40daf005f63f637f1869f707052d15a3dd97140.
+//
+// Solidity: event TokenExchange(address indexed buyer, int128 sold_id, uint256 tokens_sold, int128 bought_id, uint256 tokens_bought)
+func (_Stableswap *StableswapFilterer) FilterTokenExchange(opts *bind.FilterOpts, buyer []common.Address) (*StableswapTokenExchangeIterator, error) {
+
+	var buyerRule []interface{}
+	for _, buyerItem := range buyer {
+		buyerRule = append(buyerRule, buyerItem)
+	}
+
+	logs, sub, err := _Stableswap.contract.FilterLogs(opts, "TokenExchange", buyerRule)
+	if err != nil {
+		return nil, err
+	}
+	return &StableswapTokenExchangeIterator{contract: _Stableswap.contract, event: "TokenExchange", logs: logs, sub: sub}, nil
+}
+
+// WatchTokenExchange is a free log subscription operation binding the contract event 0x8b3e96f2b889fa771c53c981b40daf005f63f637f1869f707052d15a3dd97140.
+//
+// Solidity: event TokenExchange(address indexed buyer, int128 sold_id, uint256 tokens_sold, int128 bought_id, uint256 tokens_bought)
+func (_Stableswap *StableswapFilterer) WatchTokenExchange(opts *bind.WatchOpts, sink chan<- *StableswapTokenExchange, buyer []common.Address) (event.Subscription, error) {
+
+	var buyerRule []interface{}
+	for _, buyerItem := range buyer {
+		buyerRule = append(buyerRule, buyerItem)
+	}
+
+	logs, sub, err := _Stableswap.contract.WatchLogs(opts, "TokenExchange", buyerRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(StableswapTokenExchange)
+				if err := _Stableswap.contract.UnpackLog(event, "TokenExchange", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseTokenExchange is a log parse operation binding the contract event 0x8b3e96f2b889fa771c53c981b40daf005f63f637f1869f707052d15a3dd97140.
+//
+// Solidity: event TokenExchange(address indexed buyer, int128 sold_id, uint256 tokens_sold, int128 bought_id, uint256 tokens_bought)
+func (_Stableswap *StableswapFilterer) ParseTokenExchange(log types.Log) (*StableswapTokenExchange, error) {
+	event := new(StableswapTokenExchange)
+	if err := _Stableswap.contract.UnpackLog(event, "TokenExchange", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
@@ -0,0 +1,98 @@
+package uniswapv2pair
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Fork describes a Uniswap V2 fork. Forks deploy the same pair contract and
+// differ only in name, factory, pair init code hash and fee, so every fork is
+// served by this package.
+type Fork struct {
+	Name         string
+	Factory      common.Address
+	InitCodeHash common.Hash // optional, needed to derive pair addresses
+	FeePips      int64       // swap fee in hundredths of a basis point
+
+	// Forks that store the fee on the pair name the view method returning it
+	// and the denominator it is expressed in (e.g. 1000 for per-mille).
+	// DynamicFee re-reads it on every swap.
+	FeeMethod      string
+	FeeDenominator int64
+	DynamicFee     bool
+}
+
+// PairAddress derives the CREATE2 address of the fork's pair for two tokens.
+func (f Fork) PairAddress(tokenA, tokenB common.Address) (common.Address, error) {
+	if f.InitCodeHash == (common.Hash{}) {
+		return common.Address{}, fmt.Errorf("%s has no init code hash configured", f.Name)
+	}
+	if bytes.Compare(tokenA.Bytes(), tokenB.Bytes()) > 0 {
+		tokenA, tokenB = tokenB, tokenA
+	}
+	salt := crypto.Keccak256Hash(tokenA.Bytes(), tokenB.Bytes())
+	return crypto.CreateAddress2(f.Factory, salt, f.InitCodeHash.Bytes()), nil
+}
+
+// Validate checks that the pair was created by the fork's factory.
+func (d *Instance) Validate(ctx context.Context) error {
+	if d.Fork.Factory == (common.Address{}) {
+		return nil
+	}
+	factory, err := d.PairInterface.Factory(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return fmt.Errorf("%s %s/%s (%s) is not a V2 pair: %v", d.DEXName, d.Asset1Name, d.Asset2Name, d.AddressString, err)
+	}
+	if factory != d.Fork.Factory {
+		return fmt.Errorf("%s %s/%s (%s) belongs to factory %s, expected %s", d.DEXName, d.Asset1Name, d.Asset2Name, d.AddressString, factory.Hex(), d.Fork.Factory.Hex())
+	}
+	return nil
+}
+
+// fee returns the current swap fee in pips.
+func (d *Instance) fee() int64 {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.FeePips
+}
+
+// RefreshFee reads the fee from the pair through the fork's fee method, for
+// forks that store it on-chain.
+func (d *Instance) RefreshFee(ctx context.Context) error {
+	if d.Fork.FeeMethod == "" {
+		return nil
+	}
+
+	definition := fmt.Sprintf(`[{"name":%q,"type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]}]`, d.Fork.FeeMethod)
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		return fmt.Errorf("invalid fee method %q: %v", d.Fork.FeeMethod, err)
+	}
+	contract := bind.NewBoundContract(common.HexToAddress(d.AddressString), parsed, d.Client, d.Client, d.Client)
+
+	var out []interface{}
+	if err := contract.Call(&bind.CallOpts{Context: ctx}, &out, d.Fork.FeeMethod); err != nil {
+		return fmt.Errorf("failed to read %s of %s: %v", d.Fork.FeeMethod, d.AddressString, err)
+	}
+	value := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	denominator := d.Fork.FeeDenominator
+	if denominator == 0 {
+		denominator = 1000000
+	}
+	pips := new(big.Int).Mul(value, big.NewInt(1000000))
+	pips.Quo(pips, big.NewInt(denominator))
+
+	d.mu.Lock()
+	d.FeePips = pips.Int64()
+	d.mu.Unlock()
+	return nil
+}
@@ -1,33 +1,38 @@
 package uniswapv2pair
 // using same package as uniswapv2pair.go generated with abigen from UniswapV2Pair.abi
 
-// TODO - document complete DEX implementation
-
 import (
   "log"
   "fmt"
   "sync"
   "context"
   "math/big"
-  "crypto/ecdsa"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 
+  "bb/executor"
   "bb/types"
 )
 
+// Instance is a pair on any Uniswap V2 fork; the fork supplies its name and fee.
 type Instance struct {
 	AddressString      string
 	Client             bind.ContractBackend
 	PairInterface      *Uniswapv2pair
-	auth               *bind.TransactOpts
-	FeePerThousand     int64
+	FeePips            int64 // swap fee in hundredths of a basis point
 	Asset1Name         string
 	Asset2Name         string
 	DEXName            string
+	Fork               Fork
   Asset1Decimals     int64
   Asset2Decimals     int64
+
+	mu                 sync.RWMutex
+	reserve0           *big.Int
+	reserve1           *big.Int
+	token0             common.Address
+	token1             common.Address
 }
 
 func (i *Instance) Asset1() string {
@@ -46,31 +51,33 @@ func (i *Instance) Address() string {
 	return i.AddressString
 }
 
-var wg sync.WaitGroup
-
-func NewInstance(address string, client bind.ContractBackend, privateKey *ecdsa.PrivateKey, chainId *big.Int, asset1name string, asset2name string, asset1decimals int64, asset2decimals int64) *Instance {
+// NewInstance binds a pair of the fork whose token0 is asset1 and token1 is
+// asset2, reading its fee if the fork stores fees on-chain.
+func NewInstance(address string, client bind.ContractBackend, fork Fork, asset1name string, asset2name string, asset1decimals int64, asset2decimals int64) (*Instance, error) {
   pair, err := NewUniswapv2pair(common.HexToAddress(address), client)
   if err != nil {
-    log.Fatal(err)
+    return nil, err
   }
 
-  auth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainId)
-	if err != nil {
-    log.Fatal(err)
-	}
-
-  return &Instance{
+  d := &Instance{
     AddressString:        address,
     Client:         client,
     PairInterface:  pair,
-    auth:           auth,
-    FeePerThousand: 3,
+    FeePips:        fork.FeePips,
     Asset1Name:     asset1name,
     Asset2Name:     asset2name,
-    DEXName:        "UniswapV2",
+    DEXName:        fork.Name,
+    Fork:           fork,
     Asset1Decimals: asset1decimals,
     Asset2Decimals: asset2decimals,
   }
+
+  if fork.FeeMethod != "" {
+    if err := d.RefreshFee(context.Background()); err != nil {
+      return nil, err
+    }
+  }
+  return d, nil
 }
 
 func multiplyBy10PowX(value *big.Float, x int64) *big.Float {
@@ -96,23 +103,29 @@ func multiplyBy10PowX(value *big.Float, x int64) *big.Float {
 }
 
 func (d *Instance) GetAmountOut() (*big.Float, *big.Float, error) {
-	amountIn := new(big.Float).Mul(big.NewFloat(1), big.NewFloat(1)) // 1e18 should be 1 asset
-
 	// Fetch reserves
 	reserves, err := d.PairInterface.GetReserves(nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	forward, backward := d.amountOut(reserves.Reserve0, reserves.Reserve1)
+	return forward, backward, nil
+}
+
+func (d *Instance) amountOut(reserve0, reserve1 *big.Int) (*big.Float, *big.Float) {
+	feeComplement := big.NewFloat(float64(1000000 - d.fee()))
+	amountIn := new(big.Float).Mul(big.NewFloat(1), big.NewFloat(1)) // 1e18 should be 1 asset
+
 	// Calculate amountOut for both directions
-	amountOut1:= calculateAmountOut(amountIn, new(big.Float).SetInt(reserves.Reserve0), new(big.Float).SetInt(reserves.Reserve1))
+	amountOut1:= calculateAmountOut(amountIn, new(big.Float).SetInt(reserve0), new(big.Float).SetInt(reserve1))
   amountOut2 := new(big.Float).Quo(big.NewFloat(1), amountOut1)
 
   // Correct scaling for ETH/USDT
-  amountOut1Scaled := multiplyBy10PowX(amountOut1, d.Asset1Decimals - d.Asset2Decimals - 3)
-  amountOut2Scaled := multiplyBy10PowX(amountOut2, d.Asset2Decimals - d.Asset1Decimals - 3)
+  amountOut1Scaled := multiplyBy10PowX(amountOut1, d.Asset1Decimals - d.Asset2Decimals - 6)
+  amountOut2Scaled := multiplyBy10PowX(amountOut2, d.Asset2Decimals - d.Asset1Decimals - 6)
 
-	return new(big.Float).Mul(amountOut1Scaled, big.NewFloat(float64(1000 - d.FeePerThousand))), new(big.Float).Mul(amountOut2Scaled, big.NewFloat(float64(1000 - d.FeePerThousand))), nil
+	return new(big.Float).Mul(amountOut1Scaled, feeComplement), new(big.Float).Mul(amountOut2Scaled, feeComplement)
 }
 
 func calculateAmountOut(amountIn, reserveIn, reserveOut *big.Float) *big.Float {
@@ -122,55 +135,91 @@ func calculateAmountOut(amountIn, reserveIn, reserveOut *big.Float) *big.Float {
 	return amountOut
 }
 
-func (d *Instance) Monitor(ctx context.Context, swapEventChan chan<- types.SwapEvent) {
-	defer wg.Done()
+// Quote applies the pair's getAmountOut formula to the reserves seen on the
+// last swap, fetching them first if none have been seen yet.
+func (d *Instance) Quote(assetIn string, amountIn *big.Int) (*big.Int, error) {
+	reserves, err := d.Reserves(context.Background())
+	if err != nil {
+		return nil, err
+	}
 
+	var reserveIn, reserveOut *big.Int
+	switch assetIn {
+	case d.Asset1Name:
+		reserveIn, reserveOut = reserves.Reserve0, reserves.Reserve1
+	case d.Asset2Name:
+		reserveIn, reserveOut = reserves.Reserve1, reserves.Reserve0
+	default:
+		return nil, fmt.Errorf("%s is not traded on %s %s/%s", assetIn, d.DEXName, d.Asset1Name, d.Asset2Name)
+	}
+
+	amountOut := getAmountOut(amountIn, reserveIn, reserveOut, d.fee())
+	if amountOut == nil {
+		return nil, fmt.Errorf("%s %s/%s has no liquidity", d.DEXName, d.Asset1Name, d.Asset2Name)
+	}
+	return amountOut, nil
+}
+
+// Monitor sends a swap event for every swap on the pair until ctx is
+// cancelled, returning nil then, or returns the error that stopped it.
+func (d *Instance) Monitor(ctx context.Context, swapEventChan chan<- types.SwapEvent) error {
   swapChan := make(chan *Uniswapv2pairSwap)
 
   sub, err := d.PairInterface.WatchSwap(&bind.WatchOpts{Context: ctx}, swapChan, nil, nil)
   if err != nil {
-    log.Fatal(err)
+    return fmt.Errorf("failed to watch swaps on %s: %v", d.AddressString, err)
   }
+  defer sub.Unsubscribe()
 
 	log.Printf("Listening for swap events: %s/%s on %s (%s)", d.Asset1Name, d.Asset2Name, d.DEXName, d.AddressString)
 
 	for {
 		select {
 		case err := <-sub.Err():
-			log.Fatalf("Subscription error: %v", err)
+			return fmt.Errorf("subscription error: %v", err)
 		case <-ctx.Done():
-			return
+			return nil
 		case swap := <-swapChan:
-			_ = swap
-
-			// Get amounts out
-			forward, backward, err := d.GetAmountOut()
+			// Read reserves as of the block the swap landed in
+			reserves, err := d.PairInterface.GetReserves(&bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(swap.Raw.BlockNumber)})
 			if err != nil {
-				log.Fatalf("GetAmountOut error: %v", err)
-				return
+				if ctx.Err() != nil {
+					return nil
+				}
+				return fmt.Errorf("failed to read reserves: %v", err)
 			}
 
-			amountOut := types.AmountOut{forward, backward}
+			d.mu.Lock()
+			d.reserve0, d.reserve1 = reserves.Reserve0, reserves.Reserve1
+			d.mu.Unlock()
+
+			if d.Fork.DynamicFee {
+				if err := d.RefreshFee(ctx); err != nil {
+					log.Printf("failed to refresh fee of %s %s/%s, keeping %d pips: %v", d.DEXName, d.Asset1Name, d.Asset2Name, d.fee(), err)
+				}
+			}
 
 			// Send update to channel
-			swapEvent := types.SwapEvent{
-				DEXName:    d.DEXName,
-				Asset1Name: d.Asset1Name,
-				Asset2Name: d.Asset2Name,
-				Address:    d.AddressString,
-				AmountOut:  amountOut,
+			swapEvent := d.SwapEventAt(Reserves{Reserve0: reserves.Reserve0, Reserve1: reserves.Reserve1}, swap.Raw.BlockNumber)
+			select {
+			case swapEventChan <- swapEvent:
+			case <-ctx.Done():
+				return nil
 			}
-			swapEventChan <- swapEvent
 		}
 	}
 }
 
-func (d *Instance) ExecuteSwap(amountIn1, amountIn2 *big.Int) error {
-  _, err := d.PairInterface.Swap(d.auth, amountIn1, amountIn2, common.Address{}, nil)
+// SwapCalls sells the executor's balance of assetIn through the pair with
+// the executor's swapV2, which pays the pair its input and takes the output in
+// the same call, at the pair's fee.
+func (d *Instance) SwapCalls(exec, tokenIn common.Address, assetIn string, minOut *big.Int) ([]types.Call, error) {
+	if assetIn != d.Asset1Name && assetIn != d.Asset2Name {
+		return nil, fmt.Errorf("%s is not an asset of %s %s/%s", assetIn, d.DEXName, d.Asset1Name, d.Asset2Name)
+	}
+	call, err := executor.SwapV2(exec, common.HexToAddress(d.AddressString), tokenIn, d.fee(), minOut)
 	if err != nil {
-		return fmt.Errorf("failed to execute sell: %v", err)
+		return nil, err
 	}
-
-  // Assume success
-  return nil
+	return []types.Call{call}, nil
 }
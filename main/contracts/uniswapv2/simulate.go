@@ -0,0 +1,214 @@
+package uniswapv2pair
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	"bb/types"
+)
+
+// Reserves is a reserve state of a pair. Swaps can be simulated against it
+// without touching the reserves the pair has seen on-chain.
+type Reserves struct {
+	Reserve0 *big.Int
+	Reserve1 *big.Int
+}
+
+// Reserves returns the reserves seen on the last swap, fetching them first if
+// none have been seen yet.
+func (d *Instance) Reserves(ctx context.Context) (Reserves, error) {
+	d.mu.RLock()
+	reserve0, reserve1 := d.reserve0, d.reserve1
+	d.mu.RUnlock()
+
+	if reserve0 == nil || reserve1 == nil {
+		reserves, err := d.PairInterface.GetReserves(&bind.CallOpts{Context: ctx})
+		if err != nil {
+			return Reserves{}, err
+		}
+		reserve0, reserve1 = reserves.Reserve0, reserves.Reserve1
+	}
+	return Reserves{Reserve0: reserve0, Reserve1: reserve1}, nil
+}
+
+// Tokens returns the addresses of token0 and token1, reading them from the
+// pair once.
+func (d *Instance) Tokens(ctx context.Context) (common.Address, common.Address, error) {
+	d.mu.RLock()
+	token0, token1 := d.token0, d.token1
+	d.mu.RUnlock()
+	if token0 != (common.Address{}) {
+		return token0, token1, nil
+	}
+
+	opts := &bind.CallOpts{Context: ctx}
+	token0, err := d.PairInterface.Token0(opts)
+	if err != nil {
+		return common.Address{}, common.Address{}, fmt.Errorf("failed to read token0 of %s: %v", d.AddressString, err)
+	}
+	token1, err = d.PairInterface.Token1(opts)
+	if err != nil {
+		return common.Address{}, common.Address{}, fmt.Errorf("failed to read token1 of %s: %v", d.AddressString, err)
+	}
+
+	d.mu.Lock()
+	d.token0, d.token1 = token0, token1
+	d.mu.Unlock()
+	return token0, token1, nil
+}
+
+// PairTokens returns the tokens of asset1 and asset2, token0 and token1.
+func (d *Instance) PairTokens(ctx context.Context) (types.Token, types.Token, error) {
+	token0, token1, err := d.Tokens(ctx)
+	if err != nil {
+		return types.Token{}, types.Token{}, err
+	}
+	return types.Token{Asset: d.Asset1Name, Address: token0, Decimals: d.Asset1Decimals},
+		types.Token{Asset: d.Asset2Name, Address: token1, Decimals: d.Asset2Decimals}, nil
+}
+
+// SimulateExactIn swaps amountIn of tokenIn against r and returns the amount
+// out and the reserves after the swap.
+func (d *Instance) SimulateExactIn(r Reserves, tokenIn common.Address, amountIn *big.Int) (*big.Int, Reserves, error) {
+	zeroForOne, err := d.direction(tokenIn)
+	if err != nil {
+		return nil, r, err
+	}
+	reserveIn, reserveOut := r.Reserve0, r.Reserve1
+	if !zeroForOne {
+		reserveIn, reserveOut = reserveOut, reserveIn
+	}
+
+	amountOut := getAmountOut(amountIn, reserveIn, reserveOut, d.fee())
+	if amountOut == nil {
+		return nil, r, fmt.Errorf("%s %s/%s has no liquidity", d.DEXName, d.Asset1Name, d.Asset2Name)
+	}
+	return amountOut, d.apply(r, zeroForOne, amountIn, amountOut), nil
+}
+
+// SimulateExactOut buys amountOut of the token other than tokenIn against r
+// and returns the amount of tokenIn required and the reserves after the swap.
+func (d *Instance) SimulateExactOut(r Reserves, tokenIn common.Address, amountOut *big.Int) (*big.Int, Reserves, error) {
+	zeroForOne, err := d.direction(tokenIn)
+	if err != nil {
+		return nil, r, err
+	}
+	reserveIn, reserveOut := r.Reserve0, r.Reserve1
+	if !zeroForOne {
+		reserveIn, reserveOut = reserveOut, reserveIn
+	}
+
+	amountIn := getAmountIn(amountOut, reserveIn, reserveOut, d.fee())
+	if amountIn == nil {
+		return nil, r, fmt.Errorf("%s %s/%s cannot pay out %s", d.DEXName, d.Asset1Name, d.Asset2Name, amountOut)
+	}
+	return amountIn, d.apply(r, zeroForOne, amountIn, amountOut), nil
+}
+
+// SimulateSwap applies a direct call of the pair's swap method. The input is
+// transferred to the pair beforehand and is not part of the call, so it is
+// taken to be the least amount that satisfies the invariant. Flash swaps
+// paying out both tokens cannot be simulated.
+func (d *Instance) SimulateSwap(r Reserves, amount0Out, amount1Out *big.Int) (Reserves, error) {
+	switch {
+	case amount0Out.Sign() > 0 && amount1Out.Sign() > 0:
+		return r, fmt.Errorf("swap on %s pays out both tokens", d.AddressString)
+	case amount1Out.Sign() > 0:
+		amountIn := getAmountIn(amount1Out, r.Reserve0, r.Reserve1, d.fee())
+		if amountIn == nil {
+			return r, fmt.Errorf("%s %s/%s cannot pay out %s", d.DEXName, d.Asset1Name, d.Asset2Name, amount1Out)
+		}
+		return d.apply(r, true, amountIn, amount1Out), nil
+	case amount0Out.Sign() > 0:
+		amountIn := getAmountIn(amount0Out, r.Reserve1, r.Reserve0, d.fee())
+		if amountIn == nil {
+			return r, fmt.Errorf("%s %s/%s cannot pay out %s", d.DEXName, d.Asset1Name, d.Asset2Name, amount0Out)
+		}
+		return d.apply(r, false, amountIn, amount0Out), nil
+	}
+	return r, fmt.Errorf("swap on %s pays out nothing", d.AddressString)
+}
+
+// SwapEventAt describes the pair at reserves r as a swap event.
+func (d *Instance) SwapEventAt(r Reserves, blockNumber uint64) types.SwapEvent {
+	forward, backward := d.amountOut(r.Reserve0, r.Reserve1)
+	return types.SwapEvent{
+		DEXName:     d.DEXName,
+		Asset1Name:  d.Asset1Name,
+		Asset2Name:  d.Asset2Name,
+		Address:     d.AddressString,
+		AmountOut:   types.AmountOut{Amount1: forward, Amount2: backward},
+		Reserve1:    multiplyBy10PowX(new(big.Float).SetInt(r.Reserve0), -d.Asset1Decimals),
+		Reserve2:    multiplyBy10PowX(new(big.Float).SetInt(r.Reserve1), -d.Asset2Decimals),
+		BlockNumber: blockNumber,
+	}
+}
+
+func (d *Instance) direction(tokenIn common.Address) (bool, error) {
+	d.mu.RLock()
+	token0, token1 := d.token0, d.token1
+	d.mu.RUnlock()
+
+	switch tokenIn {
+	case token0:
+		return true, nil
+	case token1:
+		return false, nil
+	}
+	return false, fmt.Errorf("%s is not traded on %s (%s)", tokenIn.Hex(), d.DEXName, d.AddressString)
+}
+
+func (d *Instance) apply(r Reserves, zeroForOne bool, amountIn, amountOut *big.Int) Reserves {
+	if zeroForOne {
+		return Reserves{
+			Reserve0: new(big.Int).Add(r.Reserve0, amountIn),
+			Reserve1: new(big.Int).Sub(r.Reserve1, amountOut),
+		}
+	}
+	return Reserves{
+		Reserve0: new(big.Int).Sub(r.Reserve0, amountOut),
+		Reserve1: new(big.Int).Add(r.Reserve1, amountIn),
+	}
+}
+
+// getAmountOut is the pair's getAmountOut with a fee in pips, or nil without
+// liquidity.
+func getAmountOut(amountIn, reserveIn, reserveOut *big.Int, feePips int64) *big.Int {
+	amountInWithFee := new(big.Int).Mul(amountIn, big.NewInt(1000000-feePips))
+	numerator := new(big.Int).Mul(amountInWithFee, reserveOut)
+	denominator := new(big.Int).Add(new(big.Int).Mul(reserveIn, big.NewInt(1000000)), amountInWithFee)
+	if denominator.Sign() == 0 {
+		return nil
+	}
+	return numerator.Quo(numerator, denominator)
+}
+
+// getAmountIn is the router's getAmountIn with a fee in pips, or nil if the
+// pair cannot pay out amountOut.
+func getAmountIn(amountOut, reserveIn, reserveOut *big.Int, feePips int64) *big.Int {
+	if amountOut.Cmp(reserveOut) >= 0 || reserveIn.Sign() == 0 {
+		return nil
+	}
+	numerator := new(big.Int).Mul(reserveIn, amountOut)
+	numerator.Mul(numerator, big.NewInt(1000000))
+	denominator := new(big.Int).Sub(reserveOut, amountOut)
+	denominator.Mul(denominator, big.NewInt(1000000-feePips))
+	amountIn := numerator.Quo(numerator, denominator)
+	return amountIn.Add(amountIn, big.NewInt(1))
+}
+
+// Snapshot reads the pair's current reserves, keeping them as the last seen.
+func (d *Instance) Snapshot(ctx context.Context) (types.SwapEvent, error) {
+	reserves, err := d.PairInterface.GetReserves(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return types.SwapEvent{}, fmt.Errorf("failed to read reserves of %s: %v", d.AddressString, err)
+	}
+	d.mu.Lock()
+	d.reserve0, d.reserve1 = reserves.Reserve0, reserves.Reserve1
+	d.mu.Unlock()
+	return d.SwapEventAt(Reserves{Reserve0: reserves.Reserve0, Reserve1: reserves.Reserve1}, 0), nil
+}
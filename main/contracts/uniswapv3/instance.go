@@ -0,0 +1,428 @@
+package uniswapv3pool
+
+// using same package as uniswapv3pool.go generated with abigen from UniswapV3Pool.abi
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	"bb/executor"
+	"bb/types"
+)
+
+// TickWordWindow is the number of tick bitmap words loaded on each side of
+// the current tick. Quotes that would cross out of the loaded range fail
+// instead of guessing at liquidity.
+var TickWordWindow = 2
+
+type Instance struct {
+	AddressString  string
+	Client         bind.ContractBackend
+	PoolInterface  *Uniswapv3pool
+	FeePips        int64 // swap fee in hundredths of a basis point
+	TickSpacing    int
+	Asset1Name     string
+	Asset2Name     string
+	DEXName        string
+	Asset1Decimals int64
+	Asset2Decimals int64
+
+	mu           sync.RWMutex
+	sqrtPriceX96 *big.Int
+	tick         int
+	liquidity    *big.Int
+	liquidityNet map[int]*big.Int // keyed by initialized tick
+	initialized  []int            // sorted compressed indexes of initialized ticks
+	loadedLow    int              // lowest compressed tick index loaded
+	loadedHigh   int              // highest compressed tick index loaded
+	blockNumber  uint64
+	tokens       []types.Token // read once by PairTokens
+}
+
+func (i *Instance) Asset1() string {
+	return i.Asset1Name
+}
+
+func (i *Instance) Asset2() string {
+	return i.Asset2Name
+}
+
+func (i *Instance) DEX() string {
+	return i.DEXName
+}
+
+func (i *Instance) Address() string {
+	return i.AddressString
+}
+
+// NewInstance binds a V3 pool whose token0 is asset1 and token1 is asset2, and
+// loads slot0, liquidity and the initialized ticks around the current price.
+func NewInstance(address string, client bind.ContractBackend, asset1name string, asset2name string, asset1decimals int64, asset2decimals int64) (*Instance, error) {
+	pool, err := NewUniswapv3pool(common.HexToAddress(address), client)
+	if err != nil {
+		return nil, err
+	}
+
+	fee, err := pool.Fee(nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a V3 pool: failed to read fee: %v", address, err)
+	}
+	tickSpacing, err := pool.TickSpacing(nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a V3 pool: failed to read tick spacing: %v", address, err)
+	}
+
+	d := &Instance{
+		AddressString:  address,
+		Client:         client,
+		PoolInterface:  pool,
+		FeePips:        fee.Int64(),
+		TickSpacing:    int(tickSpacing.Int64()),
+		Asset1Name:     asset1name,
+		Asset2Name:     asset2name,
+		DEXName:        "UniswapV3",
+		Asset1Decimals: asset1decimals,
+		Asset2Decimals: asset2decimals,
+	}
+	if err := d.Load(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to load V3 pool %s: %v", address, err)
+	}
+	return d, nil
+}
+
+// Load replaces the local pool state with slot0, liquidity and the
+// initialized ticks within TickWordWindow words of the current tick.
+func (d *Instance) Load(ctx context.Context) error {
+	opts := &bind.CallOpts{Context: ctx}
+
+	slot0, err := d.PoolInterface.Slot0(opts)
+	if err != nil {
+		return fmt.Errorf("failed to read slot0: %v", err)
+	}
+	liquidity, err := d.PoolInterface.Liquidity(opts)
+	if err != nil {
+		return fmt.Errorf("failed to read liquidity: %v", err)
+	}
+
+	tick := int(slot0.Tick.Int64())
+	word := compress(tick, d.TickSpacing) >> 8
+	liquidityNet := make(map[int]*big.Int)
+	var initialized []int
+
+	for w := word - TickWordWindow; w <= word+TickWordWindow; w++ {
+		bitmap, err := d.PoolInterface.TickBitmap(opts, int16(w))
+		if err != nil {
+			return fmt.Errorf("failed to read tick bitmap word %d: %v", w, err)
+		}
+		for bit := 0; bit < 256; bit++ {
+			if bitmap.Bit(bit) == 0 {
+				continue
+			}
+			compressed := w*256 + bit
+			t := compressed * d.TickSpacing
+			info, err := d.PoolInterface.Ticks(opts, big.NewInt(int64(t)))
+			if err != nil {
+				return fmt.Errorf("failed to read tick %d: %v", t, err)
+			}
+			liquidityNet[t] = info.LiquidityNet
+			initialized = append(initialized, compressed)
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sqrtPriceX96 = slot0.SqrtPriceX96
+	d.tick = tick
+	d.liquidity = liquidity
+	d.liquidityNet = liquidityNet
+	d.initialized = initialized
+	d.loadedLow = (word - TickWordWindow) * 256
+	d.loadedHigh = (word+TickWordWindow)*256 + 255
+	return nil
+}
+
+// updateTick adds delta to the net liquidity of a tick, marking it initialized
+// if it was not seen before.
+func (d *Instance) updateTick(tick int, delta *big.Int) {
+	net, ok := d.liquidityNet[tick]
+	if !ok {
+		net = new(big.Int)
+	}
+	net = new(big.Int).Add(net, delta)
+
+	compressed := compress(tick, d.TickSpacing)
+	i := sort.SearchInts(d.initialized, compressed)
+	present := i < len(d.initialized) && d.initialized[i] == compressed
+
+	// A tick with zero net liquidity may still be initialized by other
+	// positions, so keep it unless it was never seen
+	d.liquidityNet[tick] = net
+	if !present {
+		d.initialized = append(d.initialized, 0)
+		copy(d.initialized[i+1:], d.initialized[i:])
+		d.initialized[i] = compressed
+	}
+}
+
+// applyPosition updates local state for a Mint (positive amount) or Burn
+// (negative amount) over [tickLower, tickUpper).
+func (d *Instance) applyPosition(tickLower, tickUpper int, amount *big.Int, blockNumber uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.updateTick(tickLower, amount)
+	d.updateTick(tickUpper, new(big.Int).Neg(amount))
+	if tickLower <= d.tick && d.tick < tickUpper {
+		d.liquidity = new(big.Int).Add(d.liquidity, amount)
+	}
+	d.blockNumber = blockNumber
+}
+
+func (d *Instance) applySwap(swap *Uniswapv3poolSwap) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.sqrtPriceX96 = swap.SqrtPriceX96
+	d.tick = int(swap.Tick.Int64())
+	d.liquidity = swap.Liquidity
+	d.blockNumber = swap.Raw.BlockNumber
+}
+
+// Quote simulates an exact-input swap through the locally tracked ticks,
+// crossing initialized ticks the same way the pool does.
+func (d *Instance) Quote(assetIn string, amountIn *big.Int) (*big.Int, error) {
+	var zeroForOne bool
+	switch assetIn {
+	case d.Asset1Name:
+		zeroForOne = true
+	case d.Asset2Name:
+		zeroForOne = false
+	default:
+		return nil, fmt.Errorf("%s is not traded on %s %s/%s", assetIn, d.DEXName, d.Asset1Name, d.Asset2Name)
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	sqrtPriceLimit := new(big.Int).Sub(MaxSqrtRatio, big.NewInt(1))
+	if zeroForOne {
+		sqrtPriceLimit = new(big.Int).Add(MinSqrtRatio, big.NewInt(1))
+	}
+
+	remaining := new(big.Int).Set(amountIn)
+	amountOut := new(big.Int)
+	sqrtPrice := new(big.Int).Set(d.sqrtPriceX96)
+	liquidity := new(big.Int).Set(d.liquidity)
+	tick := d.tick
+
+	for remaining.Sign() > 0 && sqrtPrice.Cmp(sqrtPriceLimit) != 0 {
+		tickNext, initialized := nextInitializedTickWithinOneWord(d.initialized, tick, d.TickSpacing, zeroForOne)
+		if compressed := compress(tickNext, d.TickSpacing); compressed < d.loadedLow || compressed > d.loadedHigh {
+			return nil, fmt.Errorf("quote on %s %s/%s leaves the loaded tick range", d.DEXName, d.Asset1Name, d.Asset2Name)
+		}
+		if tickNext < MinTick {
+			tickNext = MinTick
+		} else if tickNext > MaxTick {
+			tickNext = MaxTick
+		}
+
+		sqrtPriceNext := GetSqrtRatioAtTick(tickNext)
+		target := sqrtPriceNext
+		if (zeroForOne && sqrtPriceNext.Cmp(sqrtPriceLimit) < 0) || (!zeroForOne && sqrtPriceNext.Cmp(sqrtPriceLimit) > 0) {
+			target = sqrtPriceLimit
+		}
+
+		var stepIn, stepOut, stepFee *big.Int
+		sqrtPrice, stepIn, stepOut, stepFee = computeSwapStep(sqrtPrice, target, liquidity, remaining, d.FeePips)
+		remaining.Sub(remaining, stepIn)
+		remaining.Sub(remaining, stepFee)
+		amountOut.Add(amountOut, stepOut)
+
+		if sqrtPrice.Cmp(sqrtPriceNext) == 0 {
+			if initialized {
+				net := d.liquidityNet[tickNext]
+				if zeroForOne {
+					liquidity.Sub(liquidity, net)
+				} else {
+					liquidity.Add(liquidity, net)
+				}
+			}
+			if zeroForOne {
+				tick = tickNext - 1
+			} else {
+				tick = tickNext
+			}
+		}
+	}
+
+	return amountOut, nil
+}
+
+// rates returns the marginal exchange rates after fees, in whole tokens, and
+// the virtual reserves backing the current price.
+func (d *Instance) rates() (forward, backward, reserve1, reserve2 *big.Float) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	sqrtPrice := new(big.Float).Quo(new(big.Float).SetInt(d.sqrtPriceX96), new(big.Float).SetInt(q96))
+	price := new(big.Float).Mul(sqrtPrice, sqrtPrice) // token1 per token0, raw units
+	price = multiplyBy10PowX(price, d.Asset1Decimals-d.Asset2Decimals)
+
+	feeFactor := big.NewFloat(float64(1000000-d.FeePips) / 1000000)
+	forward = new(big.Float).Mul(price, feeFactor)
+	backward = new(big.Float).Mul(new(big.Float).Quo(big.NewFloat(1), price), feeFactor)
+
+	// Virtual reserves within the active range: x = L / sqrtP, y = L * sqrtP
+	liquidity := new(big.Float).SetInt(d.liquidity)
+	reserve1 = multiplyBy10PowX(new(big.Float).Quo(liquidity, sqrtPrice), -d.Asset1Decimals)
+	reserve2 = multiplyBy10PowX(new(big.Float).Mul(liquidity, sqrtPrice), -d.Asset2Decimals)
+	return forward, backward, reserve1, reserve2
+}
+
+func multiplyBy10PowX(value *big.Float, x int64) *big.Float {
+	result := new(big.Float).Set(value)
+	if x == 0 {
+		return result
+	}
+
+	exponent := x
+	if exponent < 0 {
+		exponent = -exponent
+	}
+	power := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(exponent), nil))
+	if x > 0 {
+		return result.Mul(result, power)
+	}
+	return result.Quo(result, power)
+}
+
+// Monitor sends a swap event for every swap, mint and burn on the pool until
+// ctx is cancelled, returning nil then, or returns the error that stopped it.
+func (d *Instance) Monitor(ctx context.Context, swapEventChan chan<- types.SwapEvent) error {
+	opts := &bind.WatchOpts{Context: ctx}
+
+	swapChan := make(chan *Uniswapv3poolSwap)
+	swapSub, err := d.PoolInterface.WatchSwap(opts, swapChan, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to watch swaps on %s: %v", d.AddressString, err)
+	}
+	defer swapSub.Unsubscribe()
+	mintChan := make(chan *Uniswapv3poolMint)
+	mintSub, err := d.PoolInterface.WatchMint(opts, mintChan, nil, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to watch mints on %s: %v", d.AddressString, err)
+	}
+	defer mintSub.Unsubscribe()
+	burnChan := make(chan *Uniswapv3poolBurn)
+	burnSub, err := d.PoolInterface.WatchBurn(opts, burnChan, nil, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to watch burns on %s: %v", d.AddressString, err)
+	}
+	defer burnSub.Unsubscribe()
+
+	log.Printf("Listening for swap events: %s/%s on %s (%s)", d.Asset1Name, d.Asset2Name, d.DEXName, d.AddressString)
+
+	for {
+		select {
+		case err := <-swapSub.Err():
+			return fmt.Errorf("subscription error: %v", err)
+		case err := <-mintSub.Err():
+			return fmt.Errorf("subscription error: %v", err)
+		case err := <-burnSub.Err():
+			return fmt.Errorf("subscription error: %v", err)
+		case <-ctx.Done():
+			return nil
+		case swap := <-swapChan:
+			d.applySwap(swap)
+		case mint := <-mintChan:
+			d.applyPosition(int(mint.TickLower.Int64()), int(mint.TickUpper.Int64()), mint.Amount, mint.Raw.BlockNumber)
+		case burn := <-burnChan:
+			d.applyPosition(int(burn.TickLower.Int64()), int(burn.TickUpper.Int64()), new(big.Int).Neg(burn.Amount), burn.Raw.BlockNumber)
+		}
+
+		// Send update to channel
+		select {
+		case swapEventChan <- d.swapEvent():
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// swapEvent describes the pool's current state as a swap event.
+func (d *Instance) swapEvent() types.SwapEvent {
+	forward, backward, reserve1, reserve2 := d.rates()
+
+	d.mu.RLock()
+	blockNumber := d.blockNumber
+	d.mu.RUnlock()
+
+	return types.SwapEvent{
+		DEXName:     d.DEXName,
+		Asset1Name:  d.Asset1Name,
+		Asset2Name:  d.Asset2Name,
+		Address:     d.AddressString,
+		AmountOut:   types.AmountOut{Amount1: forward, Amount2: backward},
+		Reserve1:    reserve1,
+		Reserve2:    reserve2,
+		BlockNumber: blockNumber,
+	}
+}
+
+// Snapshot reloads the pool's state and describes it as a swap event.
+func (d *Instance) Snapshot(ctx context.Context) (types.SwapEvent, error) {
+	if err := d.Load(ctx); err != nil {
+		return types.SwapEvent{}, err
+	}
+	return d.swapEvent(), nil
+}
+
+// PairTokens returns the tokens of asset1 and asset2, token0 and token1,
+// reading their addresses from the pool once.
+func (d *Instance) PairTokens(ctx context.Context) (types.Token, types.Token, error) {
+	d.mu.RLock()
+	tokens := d.tokens
+	d.mu.RUnlock()
+	if tokens != nil {
+		return tokens[0], tokens[1], nil
+	}
+
+	opts := &bind.CallOpts{Context: ctx}
+	token0, err := d.PoolInterface.Token0(opts)
+	if err != nil {
+		return types.Token{}, types.Token{}, fmt.Errorf("failed to read token0 of %s: %v", d.AddressString, err)
+	}
+	token1, err := d.PoolInterface.Token1(opts)
+	if err != nil {
+		return types.Token{}, types.Token{}, fmt.Errorf("failed to read token1 of %s: %v", d.AddressString, err)
+	}
+	tokens = []types.Token{
+		{Asset: d.Asset1Name, Address: token0, Decimals: d.Asset1Decimals},
+		{Asset: d.Asset2Name, Address: token1, Decimals: d.Asset2Decimals},
+	}
+
+	d.mu.Lock()
+	d.tokens = tokens
+	d.mu.Unlock()
+	return tokens[0], tokens[1], nil
+}
+
+// SwapCalls sells the executor's balance of assetIn through the pool with
+// the executor's swapV3, which pays the pool from its swap callback.
+func (d *Instance) SwapCalls(exec, tokenIn common.Address, assetIn string, minOut *big.Int) ([]types.Call, error) {
+	if assetIn != d.Asset1Name && assetIn != d.Asset2Name {
+		return nil, fmt.Errorf("%s is not an asset of %s %s/%s", assetIn, d.DEXName, d.Asset1Name, d.Asset2Name)
+	}
+	call, err := executor.SwapV3(exec, common.HexToAddress(d.AddressString), tokenIn, minOut)
+	if err != nil {
+		return nil, err
+	}
+	return []types.Call{call}, nil
+}
@@ -0,0 +1,225 @@
+package uniswapv3pool
+
+// Offline port of the Uniswap V3 core math libraries (TickMath, SqrtPriceMath,
+// SwapMath) used to quote exact-input swaps against locally tracked state.
+// Rounding follows the contracts so quotes match the pool to the wei.
+
+import (
+	"math/big"
+	"sort"
+)
+
+const (
+	MinTick = -887272
+	MaxTick = 887272
+)
+
+var (
+	MinSqrtRatio, _ = new(big.Int).SetString("4295128739", 10)
+	MaxSqrtRatio, _ = new(big.Int).SetString("1461446703485210103287273052203988822378723970342", 10)
+
+	q96        = new(big.Int).Lsh(big.NewInt(1), 96)
+	maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	pipsDenom  = big.NewInt(1000000)
+)
+
+var tickRatios = []string{
+	"fff97272373d413259a46990580e213a",
+	"fff2e50f5f656932ef12357cf3c7fdcc",
+	"ffe5caca7e10e4e61c3624eaa0941cd0",
+	"ffcb9843d60f6159c9db58835c926644",
+	"ff973b41fa98c081472e6896dfb254c0",
+	"ff2ea16466c96a3843ec78b326b52861",
+	"fe5dee046a99a2a811c461f1969c3053",
+	"fcbe86c7900a88aedcffc83b479aa3a4",
+	"f987a7253ac413176f2b074cf7815e54",
+	"f3392b0822b70005940c7a398e4b70f3",
+	"e7159475a2c29b7443b29c7fa6e889d9",
+	"d097f3bdfd2022b8845ad8f792aa5825",
+	"a9f746462d870fdf8a65dc1f90e061e5",
+	"70d869a156d2a1b890bb3df62baf32f7",
+	"31be135f97d08fd981231505542fcfa6",
+	"9aa508b5b7a84e1c677de54f3e99bc9",
+	"5d6af8dedb81196699c329225ee604",
+	"2216e584f5fa1ea926041bedfe98",
+	"48a170391f7dc42444e8fa2",
+}
+
+func hexInt(s string) *big.Int {
+	v, _ := new(big.Int).SetString(s, 16)
+	return v
+}
+
+// GetSqrtRatioAtTick returns sqrt(1.0001^tick) as a Q64.96 number.
+func GetSqrtRatioAtTick(tick int) *big.Int {
+	absTick := tick
+	if absTick < 0 {
+		absTick = -absTick
+	}
+
+	var ratio *big.Int
+	if absTick&0x1 != 0 {
+		ratio = hexInt("fffcb933bd6fad37aa2d162d1a594001")
+	} else {
+		ratio = hexInt("100000000000000000000000000000000")
+	}
+	for i, r := range tickRatios {
+		if absTick&(0x2<<i) != 0 {
+			ratio.Mul(ratio, hexInt(r))
+			ratio.Rsh(ratio, 128)
+		}
+	}
+	if tick > 0 {
+		ratio = new(big.Int).Quo(maxUint256, ratio)
+	}
+
+	// Round up when dividing by 1<<32 so the result is in Q64.96
+	sqrtPrice := new(big.Int).Rsh(ratio, 32)
+	if new(big.Int).And(ratio, big.NewInt(0xffffffff)).Sign() != 0 {
+		sqrtPrice.Add(sqrtPrice, big.NewInt(1))
+	}
+	return sqrtPrice
+}
+
+func mulDiv(a, b, denominator *big.Int) *big.Int {
+	product := new(big.Int).Mul(a, b)
+	return product.Quo(product, denominator)
+}
+
+func mulDivRoundingUp(a, b, denominator *big.Int) *big.Int {
+	product := new(big.Int).Mul(a, b)
+	result, remainder := new(big.Int).QuoRem(product, denominator, new(big.Int))
+	if remainder.Sign() != 0 {
+		result.Add(result, big.NewInt(1))
+	}
+	return result
+}
+
+func divRoundingUp(a, b *big.Int) *big.Int {
+	result, remainder := new(big.Int).QuoRem(a, b, new(big.Int))
+	if remainder.Sign() != 0 {
+		result.Add(result, big.NewInt(1))
+	}
+	return result
+}
+
+func getAmount0Delta(sqrtA, sqrtB, liquidity *big.Int, roundUp bool) *big.Int {
+	if sqrtA.Cmp(sqrtB) > 0 {
+		sqrtA, sqrtB = sqrtB, sqrtA
+	}
+	numerator1 := new(big.Int).Lsh(liquidity, 96)
+	numerator2 := new(big.Int).Sub(sqrtB, sqrtA)
+
+	if roundUp {
+		return divRoundingUp(mulDivRoundingUp(numerator1, numerator2, sqrtB), sqrtA)
+	}
+	return new(big.Int).Quo(mulDiv(numerator1, numerator2, sqrtB), sqrtA)
+}
+
+func getAmount1Delta(sqrtA, sqrtB, liquidity *big.Int, roundUp bool) *big.Int {
+	if sqrtA.Cmp(sqrtB) > 0 {
+		sqrtA, sqrtB = sqrtB, sqrtA
+	}
+	difference := new(big.Int).Sub(sqrtB, sqrtA)
+
+	if roundUp {
+		return mulDivRoundingUp(liquidity, difference, q96)
+	}
+	return mulDiv(liquidity, difference, q96)
+}
+
+func getNextSqrtPriceFromInput(sqrtPrice, liquidity, amountIn *big.Int, zeroForOne bool) *big.Int {
+	if zeroForOne {
+		// getNextSqrtPriceFromAmount0RoundingUp with add = true
+		if amountIn.Sign() == 0 {
+			return new(big.Int).Set(sqrtPrice)
+		}
+		numerator1 := new(big.Int).Lsh(liquidity, 96)
+		product := new(big.Int).Mul(amountIn, sqrtPrice)
+		denominator := new(big.Int).Add(numerator1, product)
+		if product.Cmp(maxUint256) <= 0 && denominator.Cmp(maxUint256) <= 0 {
+			return mulDivRoundingUp(numerator1, sqrtPrice, denominator)
+		}
+		return divRoundingUp(numerator1, new(big.Int).Add(new(big.Int).Quo(numerator1, sqrtPrice), amountIn))
+	}
+
+	// getNextSqrtPriceFromAmount1RoundingDown with add = true
+	quotient := mulDiv(amountIn, q96, liquidity)
+	return quotient.Add(quotient, sqrtPrice)
+}
+
+// computeSwapStep mirrors SwapMath.computeSwapStep for exact-input swaps.
+func computeSwapStep(sqrtCurrent, sqrtTarget, liquidity, amountRemaining *big.Int, feePips int64) (sqrtNext, amountIn, amountOut, feeAmount *big.Int) {
+	zeroForOne := sqrtCurrent.Cmp(sqrtTarget) >= 0
+	fee := big.NewInt(feePips)
+	feeComplement := new(big.Int).Sub(pipsDenom, fee)
+
+	amountRemainingLessFee := mulDiv(amountRemaining, feeComplement, pipsDenom)
+	if zeroForOne {
+		amountIn = getAmount0Delta(sqrtTarget, sqrtCurrent, liquidity, true)
+	} else {
+		amountIn = getAmount1Delta(sqrtCurrent, sqrtTarget, liquidity, true)
+	}
+	if amountRemainingLessFee.Cmp(amountIn) >= 0 {
+		sqrtNext = new(big.Int).Set(sqrtTarget)
+	} else {
+		sqrtNext = getNextSqrtPriceFromInput(sqrtCurrent, liquidity, amountRemainingLessFee, zeroForOne)
+	}
+
+	reached := sqrtNext.Cmp(sqrtTarget) == 0
+	if zeroForOne {
+		if !reached {
+			amountIn = getAmount0Delta(sqrtNext, sqrtCurrent, liquidity, true)
+		}
+		amountOut = getAmount1Delta(sqrtNext, sqrtCurrent, liquidity, false)
+	} else {
+		if !reached {
+			amountIn = getAmount1Delta(sqrtCurrent, sqrtNext, liquidity, true)
+		}
+		amountOut = getAmount0Delta(sqrtCurrent, sqrtNext, liquidity, false)
+	}
+
+	if !reached {
+		feeAmount = new(big.Int).Sub(amountRemaining, amountIn)
+	} else {
+		feeAmount = mulDivRoundingUp(amountIn, fee, feeComplement)
+	}
+	return sqrtNext, amountIn, amountOut, feeAmount
+}
+
+// compress converts a tick to its index in the tick bitmap, rounding towards
+// negative infinity.
+func compress(tick, tickSpacing int) int {
+	compressed := tick / tickSpacing
+	if tick < 0 && tick%tickSpacing != 0 {
+		compressed--
+	}
+	return compressed
+}
+
+// nextInitializedTickWithinOneWord mirrors TickBitmap.nextInitializedTickWithinOneWord
+// over a sorted slice of initialized compressed ticks.
+func nextInitializedTickWithinOneWord(initialized []int, tick, tickSpacing int, lte bool) (int, bool) {
+	compressed := compress(tick, tickSpacing)
+
+	if lte {
+		bitPos := compressed & 0xff
+		low := compressed - bitPos
+		// Largest initialized index <= compressed
+		i := sort.SearchInts(initialized, compressed+1) - 1
+		if i >= 0 && initialized[i] >= low {
+			return initialized[i] * tickSpacing, true
+		}
+		return low * tickSpacing, false
+	}
+
+	compressed++
+	bitPos := compressed & 0xff
+	high := compressed + (255 - bitPos)
+	// Smallest initialized index >= compressed
+	i := sort.SearchInts(initialized, compressed)
+	if i < len(initialized) && initialized[i] <= high {
+		return initialized[i] * tickSpacing, true
+	}
+	return high * tickSpacing, false
+}
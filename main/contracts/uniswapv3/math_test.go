@@ -0,0 +1,200 @@
+package uniswapv3pool
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+// sqrtRatio is sqrt(1.0001^tick) in Q64.96, worked out in 512-bit floats.
+func sqrtRatio(tick int) *big.Float {
+	base, _ := new(big.Float).SetPrec(512).SetString("1.0001")
+	if tick < 0 {
+		base.Quo(new(big.Float).SetPrec(512).SetInt64(1), base)
+		tick = -tick
+	}
+	ratio := new(big.Float).SetPrec(512).SetInt64(1)
+	for ; tick > 0; tick >>= 1 {
+		if tick&1 != 0 {
+			ratio.Mul(ratio, base)
+		}
+		base.Mul(base, base)
+	}
+	ratio.Sqrt(ratio)
+	return ratio.Mul(ratio, new(big.Float).SetInt(q96))
+}
+
+func TestGetSqrtRatioAtTick(t *testing.T) {
+	exact := []struct {
+		tick int
+		want *big.Int
+	}{
+		{MinTick, MinSqrtRatio},
+		{MaxTick, MaxSqrtRatio},
+		{0, q96},
+	}
+	for _, c := range exact {
+		if got := GetSqrtRatioAtTick(c.tick); got.Cmp(c.want) != 0 {
+			t.Errorf("tick %d: %s, want %s", c.tick, got, c.want)
+		}
+	}
+
+	// Elsewhere TickMath only rounds, so it agrees with the exact ratio
+	for _, tick := range []int{1, -1, 60, -60, 887, -887, 50000, -50000, 500000, -500000} {
+		got, _ := new(big.Float).Quo(new(big.Float).SetInt(GetSqrtRatioAtTick(tick)), sqrtRatio(tick)).Float64()
+		if math.Abs(got-1) > 1e-15 {
+			t.Errorf("tick %d: off by a relative %g", tick, got-1)
+		}
+	}
+}
+
+// position is liquidity over [lower, upper).
+type position struct {
+	lower, upper int
+	liquidity    float64
+}
+
+// testPool is a 0.3% pool at tick 0 holding positions, with only the tick
+// bitmap word around tick 0 loaded.
+func testPool(positions []position) *Instance {
+	d := &Instance{
+		FeePips:      3000,
+		TickSpacing:  60,
+		Asset1Name:   "A",
+		Asset2Name:   "B",
+		DEXName:      "UniswapV3",
+		sqrtPriceX96: GetSqrtRatioAtTick(0),
+		liquidity:    new(big.Int),
+		liquidityNet: make(map[int]*big.Int),
+		loadedLow:    -256,
+		loadedHigh:   255,
+	}
+	for _, p := range positions {
+		amount, _ := big.NewFloat(p.liquidity).Int(nil)
+		d.applyPosition(p.lower, p.upper, amount, 1)
+	}
+	return d
+}
+
+// referenceOut walks the positions' liquidity in float64, treating each range
+// between initialized ticks as a constant product of virtual reserves. It
+// reports false if the swap runs out of liquidity.
+func referenceOut(positions []position, zeroForOne bool, amountIn float64) (float64, bool) {
+	price := func(tick int) float64 { return math.Pow(1.0001, float64(tick)/2) }
+	active := func(low, high int) float64 {
+		var l float64
+		for _, p := range positions {
+			if p.lower <= low && high <= p.upper {
+				l += p.liquidity
+			}
+		}
+		return l
+	}
+	var ticks []int
+	for _, p := range positions {
+		ticks = append(ticks, p.lower, p.upper)
+	}
+
+	remaining, out, s, tick := amountIn*(1-0.003), 0.0, 1.0, 0
+	for remaining > 0 {
+		next, found := 0, false
+		for _, t := range ticks {
+			if zeroForOne && t < tick && (!found || t > next) || !zeroForOne && t > tick && (!found || t < next) {
+				next, found = t, true
+			}
+		}
+		if !found {
+			return 0, false
+		}
+		low, high := min(tick, next), max(tick, next)
+		l, sNext := active(low, high), price(next)
+		if l == 0 {
+			return 0, false
+		}
+		if zeroForOne {
+			capacity := l * (1/sNext - 1/s)
+			if remaining < capacity {
+				return out + l*(s-1/(1/s+remaining/l)), true
+			}
+			remaining -= capacity
+			out += l * (s - sNext)
+		} else {
+			capacity := l * (sNext - s)
+			if remaining < capacity {
+				return out + l*(1/s-1/(s+remaining/l)), true
+			}
+			remaining -= capacity
+			out += l * (1/s - 1/sNext)
+		}
+		s, tick = sNext, next
+	}
+	return out, true
+}
+
+func TestQuote(t *testing.T) {
+	// Between ticks -600 and 600 both positions are active; about 1.5e20 of
+	// either token moves the price there
+	positions := []position{{-600, 600, 1e21}, {-1200, 1200, 4e21}}
+	d := testPool(positions)
+
+	cases := []struct {
+		name     string
+		assetIn  string
+		amountIn float64
+		leaves   bool
+	}{
+		{"A for B within the range", "A", 1e18, false},
+		{"B for A within the range", "B", 1e18, false},
+		{"A for B across tick -600", "A", 2e20, false},
+		{"B for A across tick 600", "B", 2e20, false},
+		{"A for B past all liquidity", "A", 1e22, true},
+		{"B for A past all liquidity", "B", 1e22, true},
+	}
+	for _, c := range cases {
+		amountIn, _ := big.NewFloat(c.amountIn).Int(nil)
+		got, err := d.Quote(c.assetIn, amountIn)
+		if c.leaves {
+			if err == nil {
+				t.Errorf("%s: quoted %s beyond the loaded ticks", c.name, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		want, ok := referenceOut(positions, c.assetIn == "A", c.amountIn)
+		if !ok {
+			t.Fatalf("%s: reference ran out of liquidity", c.name)
+		}
+		out, _ := new(big.Float).SetInt(got).Float64()
+		if math.Abs(out/want-1) > 1e-9 {
+			t.Errorf("%s: quoted %s, want about %g", c.name, got, want)
+		}
+	}
+
+	if _, err := d.Quote("C", big.NewInt(1)); err == nil {
+		t.Error("quoted an asset the pool does not trade")
+	}
+}
+
+// TestQuoteCrossesWithPool checks that a quote crossing a tick uses the
+// liquidity on the far side, as a pool that swapped there would.
+func TestQuoteCrossesWithPool(t *testing.T) {
+	d := testPool([]position{{-600, 600, 1e21}, {-1200, 1200, 4e21}})
+	amountIn, _ := big.NewFloat(2e20).Int(nil)
+	across, err := d.Quote("B", amountIn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without the narrow position's liquidity leaving at tick 600, the same
+	// input would buy more
+	d.liquidityNet[600] = new(big.Int)
+	unchanged, err := d.Quote("B", amountIn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if across.Cmp(unchanged) >= 0 {
+		t.Errorf("crossing tick 600 quoted %s, not less than %s without the crossing", across, unchanged)
+	}
+}
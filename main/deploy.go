@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"bb/executor"
+	"bb/pipeline"
+	"bb/signer"
+)
+
+// deployExecutorCommand deploys an executor owned by the trading account on
+// one chain, for the chain's executor setting.
+func deployExecutorCommand(args []string) error {
+	opts := newOptions("deploy-executor", "[flags]", "Deploys an executor owned by the trading account, to set as the chain's executor.")
+	if _, err := opts.parse(args); err != nil {
+		return err
+	}
+	cfg, err := opts.load(false)
+	if err != nil {
+		return err
+	}
+	chain, err := opts.chain(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	clients, err := pipeline.DialPool(ctx, chain.NodeURLs)
+	if err != nil {
+		return err
+	}
+	defer clients.Close()
+	s, err := loadSigner(ctx)
+	if err != nil {
+		return err
+	}
+
+	client := clients.Next()
+	deployed, tx, err := executor.Deploy(signer.NewTransactOpts(s, big.NewInt(chain.ChainID)).Opts(ctx), client)
+	if err != nil {
+		return err
+	}
+	slog.Info("deploying executor", "chain", chain.Name, "account", s.Address().Hex(), "tx", tx.Hash().Hex())
+	receipt, err := bind.WaitMined(ctx, client, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status != ethtypes.ReceiptStatusSuccessful {
+		return fmt.Errorf("deployment %s reverted", tx.Hash().Hex())
+	}
+
+	d := deployment{Chain: chain.Name, Executor: deployed.Address.Hex(), Owner: s.Address().Hex(), Tx: tx.Hash().Hex()}
+	return opts.print(d, func(w io.Writer) {
+		fmt.Fprintf(w, "executor\t%s\n", d.Executor)
+		fmt.Fprintf(w, "owner\t%s\n", d.Owner)
+		fmt.Fprintf(w, "tx\t%s\n", d.Tx)
+	})
+}
+
+// deployment is the result of the deploy-executor command.
+type deployment struct {
+	Chain    string `json:"chain"`
+	Executor string `json:"executor"`
+	Owner    string `json:"owner"`
+	Tx       string `json:"tx"`
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"strings"
+	"time"
+
+	"bb/config"
+	"bb/pipeline"
+	"bb/strategy"
+	"bb/types"
+)
+
+// detectCommand evaluates the current state of every pair of the selected
+// chains, once or at an interval.
+func detectCommand(args []string) error {
+	opts := newOptions("detect", "[flags]", "Reads the current state of every pair and evaluates the graph for arbitrage,\nonce with --once or every --interval.")
+	once := opts.flags.Bool("once", false, "evaluate once and exit")
+	interval := opts.flags.Duration("interval", 12*time.Second, "time between evaluations without --once")
+	if _, err := opts.parse(args); err != nil {
+		return err
+	}
+	cfg, err := opts.load(false)
+	if err != nil {
+		return err
+	}
+	chains, err := opts.chains(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	var detectors []*detector
+	for _, chain := range chains {
+		if len(chain.NodeURLs) == 0 {
+			continue
+		}
+		d, err := newDetector(ctx, chain)
+		if err != nil {
+			return err
+		}
+		defer d.clients.Close()
+		detectors = append(detectors, d)
+	}
+	if len(detectors) == 0 {
+		return fmt.Errorf("no chain with node URLs configured")
+	}
+
+	for {
+		var results []detection
+		for _, d := range detectors {
+			result, err := d.detect(ctx)
+			if err != nil {
+				return err
+			}
+			results = append(results, result)
+		}
+		if err := opts.print(results, func(w io.Writer) {
+			for _, r := range results {
+				fmt.Fprintf(w, "%s\tblock %d\t%d/%d pairs\t", r.Chain, r.Block, r.Pairs-r.Failed, r.Pairs)
+				if r.Opportunity == nil {
+					fmt.Fprintf(w, "no opportunity\t\n")
+					continue
+				}
+				fmt.Fprintf(w, "%+.4f%%\t%s\t\n", 100*r.Opportunity.Return, strings.Join(r.Opportunity.Path, " > "))
+			}
+		}); err != nil {
+			return err
+		}
+		if *once {
+			return nil
+		}
+		time.Sleep(*interval)
+	}
+}
+
+// detector evaluates snapshots of a chain's pairs.
+type detector struct {
+	chain    config.Chain
+	clients  *pipeline.ClientPool
+	strategy *strategy.Strategy
+	log      *slog.Logger
+}
+
+func newDetector(ctx context.Context, chain config.Chain) (*detector, error) {
+	logger := slog.Default().With("chain", chain.Name)
+	clients, err := pipeline.DialPool(ctx, chain.NodeURLs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", chain.Name, err)
+	}
+	pairs, err := pipeline.BuildPairs(chain, clients)
+	if err != nil {
+		clients.Close()
+		return nil, fmt.Errorf("%s: %v", chain.Name, err)
+	}
+	return &detector{
+		chain:    chain,
+		clients:  clients,
+		strategy: pipeline.NewStrategy(chain, clients, pairs, logger),
+		log:      logger,
+	}, nil
+}
+
+// detection is the outcome of evaluating a chain once.
+type detection struct {
+	Chain       string       `json:"chain"`
+	Block       uint64       `json:"block"`
+	Pairs       int          `json:"pairs"`
+	Failed      int          `json:"failed"` // pairs whose state could not be read
+	Opportunity *opportunity `json:"opportunity"`
+}
+
+// detect snapshots every pair and evaluates the graph they make. Snapshots
+// of unknown block are taken to be at the head.
+func (d *detector) detect(ctx context.Context) (detection, error) {
+	head, err := d.clients.BlockNumber(ctx)
+	if err != nil {
+		return detection{}, fmt.Errorf("%s: failed to read head block: %v", d.chain.Name, err)
+	}
+
+	pairs := d.strategy.Pairs()
+	result := detection{Chain: d.chain.Name, Block: head, Pairs: len(pairs)}
+	var events []types.SwapEvent
+	for _, pair := range pairs {
+		snapshotter, ok := pair.(types.Snapshotter)
+		if !ok {
+			d.log.Warn("pair cannot be snapshot", "dex", pair.DEX(), "pair", pair.Address())
+			result.Failed++
+			continue
+		}
+		event, err := snapshotter.Snapshot(ctx)
+		if err != nil {
+			d.log.Warn("failed to read pair", "dex", pair.DEX(), "pair", pair.Address(), "err", err)
+			result.Failed++
+			continue
+		}
+		if event.BlockNumber == 0 {
+			event.BlockNumber = head
+		}
+		events = append(events, event)
+	}
+
+	if o := d.strategy.Evaluate(ctx, events); o != nil {
+		result.Opportunity = newOpportunity(o)
+	}
+	return result, nil
+}
+
+// opportunity is the printed form of a strategy.Opportunity.
+type opportunity struct {
+	Block    uint64    `json:"block"`
+	Path     []string  `json:"path"` // asset@dex
+	Rates    []float64 `json:"rates"`
+	Return   float64   `json:"return"`             // product of the rates, minus one
+	AmountIn float64   `json:"amountIn,omitempty"` // whole tokens of the first asset
+	GasUnits uint64    `json:"gasUnits,omitempty"`
+	GasCost  float64   `json:"gasCost,omitempty"` // in the native token
+}
+
+func newOpportunity(o *strategy.Opportunity) *opportunity {
+	view := &opportunity{Block: o.Block, Rates: o.Rates, AmountIn: o.AmountIn, GasUnits: o.GasUnits, Return: 1}
+	for _, node := range o.Path {
+		view.Path = append(view.Path, node.String())
+	}
+	for _, rate := range o.Rates {
+		view.Return *= rate
+	}
+	view.Return--
+	if o.GasPrice != nil {
+		view.GasCost, _ = new(big.Float).Quo(new(big.Float).SetInt(o.GasCost()), big.NewFloat(1e18)).Float64()
+	}
+	return view
+}
@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.21;
+
+interface IERC20 {
+    function balanceOf(address account) external view returns (uint);
+}
+
+interface IUniswapV2Pair {
+    function token0() external view returns (address);
+    function getReserves() external view returns (uint112 reserve0, uint112 reserve1, uint32 blockTimestampLast);
+    function swap(uint amount0Out, uint amount1Out, address to, bytes calldata data) external;
+}
+
+interface IUniswapV3Pool {
+    function token0() external view returns (address);
+    function swap(address recipient, bool zeroForOne, int amountSpecified, uint160 sqrtPriceLimitX96, bytes calldata data) external returns (int amount0, int amount1);
+}
+
+// Executor carries out a route of swaps for its owner in one transaction. It
+// takes the input from the owner, makes the route's calls and pays everything
+// back, reverting unless the output reaches a minimum. It holds nothing
+// between transactions.
+contract Executor {
+    // Call is a call the executor makes. If token is set, the executor first
+    // writes its balance of token into the 32-byte word of data at offset, so
+    // the call spends whatever the calls before it paid out.
+    struct Call {
+        address to;
+        bytes data;
+        address token;
+        uint offset;
+    }
+
+    // Price limits just inside the range of a Uniswap V3 pool, so that a
+    // swap runs until its input is spent.
+    uint160 private constant MIN_SQRT_RATIO = 4295128739 + 1;
+    uint160 private constant MAX_SQRT_RATIO = 1461446703485210103287273052203988822378723970342 - 1;
+
+    address public immutable owner;
+    address private swapping; // V3 pool whose swap may call back
+
+    constructor() {
+        owner = msg.sender;
+    }
+
+    // execute pulls amountIn of tokenIn from the owner, makes the calls and
+    // requires at least minAmountOut of tokenOut to be left. The output and
+    // any balance of the sweep tokens go back to the owner.
+    function execute(address tokenIn, uint amountIn, Call[] calldata calls, address tokenOut, uint minAmountOut, address[] calldata sweep) external returns (uint amountOut) {
+        require(msg.sender == owner, "Executor: not owner");
+        _token(tokenIn, abi.encodeWithSignature("transferFrom(address,address,uint256)", owner, address(this), amountIn));
+
+        for (uint i = 0; i < calls.length; i++) {
+            bytes memory data = calls[i].data;
+            if (calls[i].token != address(0)) {
+                uint amount = IERC20(calls[i].token).balanceOf(address(this));
+                uint offset = calls[i].offset;
+                require(offset + 32 <= data.length, "Executor: amount out of bounds");
+                assembly {
+                    mstore(add(add(data, 32), offset), amount)
+                }
+            }
+            (bool ok, bytes memory result) = calls[i].to.call(data);
+            if (!ok) {
+                assembly {
+                    revert(add(result, 32), mload(result))
+                }
+            }
+        }
+
+        amountOut = IERC20(tokenOut).balanceOf(address(this));
+        require(amountOut >= minAmountOut, "Executor: output below minimum");
+        _pay(tokenOut);
+        for (uint i = 0; i < sweep.length; i++) {
+            _pay(sweep[i]);
+        }
+    }
+
+    // swapV2 sells the executor's whole balance of tokenIn on a Uniswap V2
+    // fork pair charging feePips, for at least minAmountOut. Only the
+    // executor calls it, as one of the calls of a route.
+    function swapV2(address pair, address tokenIn, uint feePips, uint minAmountOut) external {
+        require(msg.sender == address(this), "Executor: not a route call");
+        uint amountIn = IERC20(tokenIn).balanceOf(address(this));
+        _token(tokenIn, abi.encodeWithSignature("transfer(address,uint256)", pair, amountIn));
+
+        (uint reserve0, uint reserve1,) = IUniswapV2Pair(pair).getReserves();
+        bool zeroForOne = tokenIn == IUniswapV2Pair(pair).token0();
+        (uint reserveIn, uint reserveOut) = zeroForOne ? (reserve0, reserve1) : (reserve1, reserve0);
+        uint amountInWithFee = amountIn * (1e6 - feePips);
+        uint amountOut = amountInWithFee * reserveOut / (reserveIn * 1e6 + amountInWithFee);
+        require(amountOut >= minAmountOut, "Executor: output below minimum");
+
+        (uint amount0Out, uint amount1Out) = zeroForOne ? (uint(0), amountOut) : (amountOut, uint(0));
+        IUniswapV2Pair(pair).swap(amount0Out, amount1Out, address(this), "");
+    }
+
+    // swapV3 sells the executor's whole balance of tokenIn on a Uniswap V3
+    // pool for at least minAmountOut, paying the pool from its callback. Only
+    // the executor calls it, as one of the calls of a route.
+    function swapV3(address pool, address tokenIn, uint minAmountOut) external {
+        require(msg.sender == address(this), "Executor: not a route call");
+        uint amountIn = IERC20(tokenIn).balanceOf(address(this));
+        bool zeroForOne = tokenIn == IUniswapV3Pool(pool).token0();
+
+        swapping = pool;
+        (int amount0, int amount1) = IUniswapV3Pool(pool).swap(address(this), zeroForOne, int(amountIn), zeroForOne ? MIN_SQRT_RATIO : MAX_SQRT_RATIO, abi.encode(tokenIn));
+        swapping = address(0);
+
+        uint amountOut = uint(-(zeroForOne ? amount1 : amount0));
+        require(amountOut >= minAmountOut, "Executor: output below minimum");
+    }
+
+    // uniswapV3SwapCallback pays the pool swapV3 is swapping on what it is
+    // owed. Calls from anywhere else revert.
+    function uniswapV3SwapCallback(int amount0Delta, int amount1Delta, bytes calldata data) external {
+        require(swapping != address(0) && msg.sender == swapping, "Executor: unexpected callback");
+        address token = abi.decode(data, (address));
+        uint amount = uint(amount0Delta > 0 ? amount0Delta : amount1Delta);
+        _token(token, abi.encodeWithSignature("transfer(address,uint256)", msg.sender, amount));
+    }
+
+    function _pay(address token) private {
+        uint balance = IERC20(token).balanceOf(address(this));
+        if (balance > 0) {
+            _token(token, abi.encodeWithSignature("transfer(address,uint256)", owner, balance));
+        }
+    }
+
+    // _token calls an ERC-20, accepting tokens that return nothing.
+    function _token(address token, bytes memory data) private {
+        (bool ok, bytes memory result) = token.call(data);
+        require(ok && (result.length == 0 || abi.decode(result, (bool))), "Executor: token transfer failed");
+    }
+}
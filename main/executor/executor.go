@@ -0,0 +1,188 @@
+// Package executor binds the Executor contract, which carries out a route of
+// swaps for its owner in one transaction: the input is taken, swapped hop by
+// hop and paid back as the output, or the whole transaction reverts. Nothing
+// the route moves is ever left with a pool between transactions.
+package executor
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"bb/types"
+)
+
+// combined.json is the output of solc 0.8.21 for Executor.sol.
+//
+//go:generate sh -c "solc --optimize --evm-version paris --combined-json abi,bin Executor.sol > combined.json"
+//go:embed combined.json
+var combinedJSON []byte
+
+var contractABI, contractBin = load()
+
+const erc20ABI = `[{"name":"approve","type":"function","stateMutability":"nonpayable","inputs":[{"name":"spender","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]}]`
+
+var erc20, _ = abi.JSON(strings.NewReader(erc20ABI))
+
+// Balance stands in, among the arguments of a call packed for Spend, for the
+// executor's balance of a token at the time of the call.
+var Balance = new(big.Int).SetBytes(crypto.Keccak256([]byte("bb/executor.Balance"))[:16])
+
+func load() (abi.ABI, []byte) {
+	var combined struct {
+		Contracts map[string]struct {
+			ABI json.RawMessage `json:"abi"`
+			Bin string          `json:"bin"`
+		} `json:"contracts"`
+	}
+	if err := json.Unmarshal(combinedJSON, &combined); err != nil {
+		panic(err)
+	}
+	c := combined.Contracts["Executor.sol:Executor"]
+	parsed, err := abi.JSON(strings.NewReader(string(c.ABI)))
+	if err != nil {
+		panic(err)
+	}
+	return parsed, common.FromHex(c.Bin)
+}
+
+// call is the ABI form of a types.Call.
+type call struct {
+	To     common.Address
+	Data   []byte
+	Token  common.Address
+	Offset *big.Int
+}
+
+// Route is what an executor carries out: AmountIn of TokenIn, taken from the
+// owner, is sold through Calls for at least MinAmountOut of TokenOut.
+type Route struct {
+	TokenIn      common.Address
+	AmountIn     *big.Int
+	Calls        []types.Call
+	TokenOut     common.Address
+	MinAmountOut *big.Int
+	Sweep        []common.Address // tokens the calls may leave over, paid back to the owner
+}
+
+// Executor is a deployed Executor contract.
+type Executor struct {
+	Address  common.Address
+	contract *bind.BoundContract
+}
+
+// New binds the executor deployed at address.
+func New(address common.Address, backend bind.ContractBackend) *Executor {
+	return &Executor{Address: address, contract: bind.NewBoundContract(address, contractABI, backend, backend, backend)}
+}
+
+// Deploy deploys an executor owned by opts.From.
+func Deploy(opts *bind.TransactOpts, backend bind.ContractBackend) (*Executor, *ethtypes.Transaction, error) {
+	address, tx, contract, err := bind.DeployContract(opts, contractABI, contractBin, backend)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to deploy executor: %v", err)
+	}
+	return &Executor{Address: address, contract: contract}, tx, nil
+}
+
+// Owner returns the account the executor trades for.
+func (e *Executor) Owner(ctx context.Context) (common.Address, error) {
+	var out []interface{}
+	if err := e.contract.Call(&bind.CallOpts{Context: ctx}, &out, "owner"); err != nil {
+		return common.Address{}, fmt.Errorf("failed to read executor owner: %v", err)
+	}
+	return *abi.ConvertType(out[0], new(common.Address)).(*common.Address), nil
+}
+
+// Execute carries out a route. The owner must have approved the executor to
+// spend AmountIn of TokenIn.
+func (e *Executor) Execute(opts *bind.TransactOpts, route Route) (*ethtypes.Transaction, error) {
+	calls := make([]call, len(route.Calls))
+	for i, c := range route.Calls {
+		calls[i] = call{To: c.To, Data: c.Data, Token: c.Token, Offset: big.NewInt(int64(c.Offset))}
+	}
+	minAmountOut := route.MinAmountOut
+	if minAmountOut == nil {
+		minAmountOut = new(big.Int)
+	}
+	sweep := route.Sweep
+	if sweep == nil {
+		sweep = []common.Address{}
+	}
+	tx, err := e.contract.Transact(opts, "execute", route.TokenIn, route.AmountIn, calls, route.TokenOut, minAmountOut, sweep)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute route: %v", err)
+	}
+	return tx, nil
+}
+
+// SwapV2 returns the call that sells an executor's whole balance of tokenIn
+// on a Uniswap V2 fork pair charging feePips, for at least minAmountOut. The
+// executor sends the input and takes the output in one call, at the reserves
+// the pair has then.
+func SwapV2(executor, pair, tokenIn common.Address, feePips int64, minAmountOut *big.Int) (types.Call, error) {
+	if minAmountOut == nil {
+		minAmountOut = new(big.Int)
+	}
+	data, err := contractABI.Pack("swapV2", pair, tokenIn, big.NewInt(feePips), minAmountOut)
+	if err != nil {
+		return types.Call{}, err
+	}
+	return types.Call{To: executor, Data: data}, nil
+}
+
+// SwapV3 returns the call that sells an executor's whole balance of tokenIn
+// on a Uniswap V3 pool for at least minAmountOut. The pool takes the input
+// through the executor's swap callback, which pays only the pool being
+// swapped on.
+func SwapV3(executor, pool, tokenIn common.Address, minAmountOut *big.Int) (types.Call, error) {
+	if minAmountOut == nil {
+		minAmountOut = new(big.Int)
+	}
+	data, err := contractABI.Pack("swapV3", pool, tokenIn, minAmountOut)
+	if err != nil {
+		return types.Call{}, err
+	}
+	return types.Call{To: executor, Data: data}, nil
+}
+
+// Spend returns the call of data to to that spends the executor's balance of
+// token: the one argument of data packed as Balance is replaced by the
+// balance when the call is made.
+func Spend(to common.Address, data []byte, token common.Address) (types.Call, error) {
+	word := common.LeftPadBytes(Balance.Bytes(), 32)
+	offset := -1
+	for i := 4; i+32 <= len(data); i += 32 {
+		if !bytes.Equal(data[i:i+32], word) {
+			continue
+		}
+		if offset >= 0 {
+			return types.Call{}, fmt.Errorf("call to %s spends the balance twice", to.Hex())
+		}
+		offset = i
+	}
+	if offset < 0 {
+		return types.Call{}, fmt.Errorf("call to %s does not spend the balance", to.Hex())
+	}
+	return types.Call{To: to, Data: data, Token: token, Offset: offset}, nil
+}
+
+// Approve returns the call by which the executor lets spender take its whole
+// balance of token, for pools that pull their input.
+func Approve(token, spender common.Address) (types.Call, error) {
+	data, err := erc20.Pack("approve", spender, Balance)
+	if err != nil {
+		return types.Call{}, err
+	}
+	return Spend(token, data, token)
+}
@@ -0,0 +1,64 @@
+package feed
+
+import (
+	"sync"
+	"time"
+)
+
+// Types of events.
+const (
+	Graph       = "graph"       // a strategy.Graph, after every evaluation
+	Opportunity = "opportunity" // a simulated ledger.Entry
+	Trade       = "trade"       // an executed ledger.Entry
+)
+
+// Event is something a pipeline did, for live views.
+type Event struct {
+	Type  string    `json:"type"`
+	Chain string    `json:"chain"`
+	Time  time.Time `json:"time"`
+	Data  any       `json:"data"`
+}
+
+// Feed fans events out to subscribers. Publishing never blocks: a subscriber
+// that falls behind misses events. A nil Feed publishes nothing, so that
+// pipelines can run without one.
+type Feed struct {
+	mu   sync.Mutex
+	subs map[chan Event]struct{}
+}
+
+// New creates a feed without subscribers.
+func New() *Feed {
+	return &Feed{subs: make(map[chan Event]struct{})}
+}
+
+// Publish sends an event to every subscriber with room for it.
+func (f *Feed) Publish(typ, chain string, data any) {
+	if f == nil {
+		return
+	}
+	event := Event{Type: typ, Chain: chain, Time: time.Now(), Data: data}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for sub := range f.subs {
+		select {
+		case sub <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel of the events published from now on, holding
+// up to buffer of them, and the function that ends the subscription.
+func (f *Feed) Subscribe(buffer int) (<-chan Event, func()) {
+	sub := make(chan Event, buffer)
+	f.mu.Lock()
+	f.subs[sub] = struct{}{}
+	f.mu.Unlock()
+	return sub, func() {
+		f.mu.Lock()
+		delete(f.subs, sub)
+		f.mu.Unlock()
+	}
+}
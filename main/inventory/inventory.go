@@ -0,0 +1,380 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Backend is the chain access the inventory needs.
+type Backend interface {
+	bind.ContractBackend
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+}
+
+// Token is an ERC-20 token held as an asset.
+type Token struct {
+	Asset    string
+	Address  common.Address
+	Decimals int64
+}
+
+// Balance is the holding of one asset.
+type Balance struct {
+	Asset     string
+	Native    bool    // the chain's native balance rather than a token
+	Amount    float64 // whole tokens
+	Reserved  float64 // committed to trades in flight
+	Available float64
+}
+
+const erc20ABI = `[
+	{"name":"balanceOf","type":"function","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"name":"allowance","type":"function","stateMutability":"view","inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"name":"approve","type":"function","stateMutability":"nonpayable","inputs":[{"name":"spender","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]}
+]`
+
+// transferTopic is the topic of the ERC-20 Transfer event.
+var transferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// Inventory tracks the native and ERC-20 balances of the trading account. It
+// reads them once, then follows Transfer events to and from the account and
+// the gas and value of our own transactions. The native balance pays for gas
+// and is kept apart from the tokens, which are what the strategy trades; a
+// wrapped native token may share its asset name.
+type Inventory struct {
+	Account common.Address
+	Native  string // asset name of the native balance
+	Backend Backend
+	Log     *slog.Logger
+
+	erc20   abi.ABI
+	tokens  map[common.Address]Token
+	byAsset map[string]Token
+
+	mu       sync.RWMutex
+	native   *big.Int
+	balances map[string]*big.Int // raw token units, by asset
+	reserved map[string]*big.Int
+}
+
+// New creates the inventory of account over the given tokens; native names
+// the chain's native balance.
+func New(backend Backend, account common.Address, native string, tokens []Token, logger *slog.Logger) (*Inventory, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	parsed, err := abi.JSON(strings.NewReader(erc20ABI))
+	if err != nil {
+		return nil, err
+	}
+	inv := &Inventory{
+		Account:  account,
+		Native:   native,
+		Backend:  backend,
+		Log:      logger,
+		erc20:    parsed,
+		native:   new(big.Int),
+		tokens:   make(map[common.Address]Token),
+		byAsset:  make(map[string]Token),
+		balances: make(map[string]*big.Int),
+		reserved: make(map[string]*big.Int),
+	}
+	for _, token := range tokens {
+		inv.tokens[token.Address] = token
+		inv.byAsset[token.Asset] = token
+	}
+	return inv, nil
+}
+
+// Load reads every balance from the chain.
+func (inv *Inventory) Load(ctx context.Context) error {
+	return inv.loadAt(ctx, nil)
+}
+
+// loadAt reads every balance as of a block, or the latest for nil.
+func (inv *Inventory) loadAt(ctx context.Context, block *big.Int) error {
+	native, err := inv.Backend.BalanceAt(ctx, inv.Account, block)
+	if err != nil {
+		return fmt.Errorf("failed to read %s balance: %v", inv.Native, err)
+	}
+	balances := make(map[string]*big.Int)
+
+	for address, token := range inv.tokens {
+		contract := bind.NewBoundContract(address, inv.erc20, inv.Backend, nil, nil)
+		var out []interface{}
+		if err := contract.Call(&bind.CallOpts{Context: ctx, BlockNumber: block}, &out, "balanceOf", inv.Account); err != nil {
+			return fmt.Errorf("failed to read %s balance: %v", token.Asset, err)
+		}
+		balances[token.Asset] = *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+	}
+
+	inv.mu.Lock()
+	inv.native = native
+	inv.balances = balances
+	inv.mu.Unlock()
+	return nil
+}
+
+// Watch follows Transfer events of the tracked tokens to and from the
+// account until ctx is cancelled. Once subscribed, it reloads every balance
+// at the head block and applies the transfers of later blocks only, so that
+// a run picks up whatever moved while the account was not watched.
+func (inv *Inventory) Watch(ctx context.Context) error {
+	addresses := make([]common.Address, 0, len(inv.tokens))
+	for address := range inv.tokens {
+		addresses = append(addresses, address)
+	}
+	if len(addresses) == 0 {
+		if err := inv.Load(ctx); err != nil {
+			return err
+		}
+		<-ctx.Done()
+		return nil
+	}
+	account := common.BytesToHash(inv.Account.Bytes())
+
+	// Topics match by position, so incoming and outgoing need a query each
+	logs := make(chan ethtypes.Log)
+	queries := []ethereum.FilterQuery{
+		{Addresses: addresses, Topics: [][]common.Hash{{transferTopic}, {account}}},
+		{Addresses: addresses, Topics: [][]common.Hash{{transferTopic}, nil, {account}}},
+	}
+	var subs []ethereum.Subscription
+	for _, query := range queries {
+		sub, err := inv.Backend.SubscribeFilterLogs(ctx, query, logs)
+		if err != nil {
+			for _, s := range subs {
+				s.Unsubscribe()
+			}
+			return fmt.Errorf("failed to subscribe to transfers: %v", err)
+		}
+		subs = append(subs, sub)
+	}
+	defer func() {
+		for _, s := range subs {
+			s.Unsubscribe()
+		}
+	}()
+
+	head, err := inv.Backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to read head block: %v", err)
+	}
+	if err := inv.loadAt(ctx, head.Number); err != nil {
+		return err
+	}
+	loaded := head.Number.Uint64()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-subs[0].Err():
+			return fmt.Errorf("transfer subscription ended: %v", err)
+		case err := <-subs[1].Err():
+			return fmt.Errorf("transfer subscription ended: %v", err)
+		case l := <-logs:
+			// The balances read include the transfers up to the head
+			if l.BlockNumber <= loaded && !l.Removed {
+				continue
+			}
+			inv.applyTransfer(l, l.Removed)
+		}
+	}
+}
+
+// applyTransfer applies a Transfer event, or reverts it for a log removed in
+// a reorg.
+func (inv *Inventory) applyTransfer(l ethtypes.Log, revert bool) {
+	token, ok := inv.tokens[l.Address]
+	if !ok || len(l.Topics) != 3 || len(l.Data) != 32 {
+		return
+	}
+	from := common.BytesToAddress(l.Topics[1].Bytes())
+	to := common.BytesToAddress(l.Topics[2].Bytes())
+	amount := new(big.Int).SetBytes(l.Data)
+	if revert {
+		amount.Neg(amount)
+	}
+
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	balance := inv.balance(token.Asset)
+	if to == inv.Account {
+		balance.Add(balance, amount)
+	}
+	if from == inv.Account {
+		balance.Sub(balance, amount)
+	}
+}
+
+// ApplyReceipt charges the native balance for one of our own mined
+// transactions: its gas and, unless it reverted, the value it sent.
+func (inv *Inventory) ApplyReceipt(tx *ethtypes.Transaction, receipt *ethtypes.Receipt) {
+	if receipt == nil {
+		return
+	}
+	spent := new(big.Int)
+	if receipt.EffectiveGasPrice != nil {
+		spent.Mul(receipt.EffectiveGasPrice, new(big.Int).SetUint64(receipt.GasUsed))
+	}
+	if receipt.Status == ethtypes.ReceiptStatusSuccessful && tx.Value() != nil {
+		spent.Add(spent, tx.Value())
+	}
+
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	inv.native.Sub(inv.native, spent)
+}
+
+// balance returns the mutable balance of an asset. inv.mu must be held.
+func (inv *Inventory) balance(asset string) *big.Int {
+	if inv.balances[asset] == nil {
+		inv.balances[asset] = new(big.Int)
+	}
+	return inv.balances[asset]
+}
+
+// Reserve commits amount whole tokens of asset to a trade in flight, so that
+// it is no longer available. The returned function releases it.
+func (inv *Inventory) Reserve(asset string, amount float64) func() {
+	raw := inv.raw(asset, amount)
+
+	inv.mu.Lock()
+	if inv.reserved[asset] == nil {
+		inv.reserved[asset] = new(big.Int)
+	}
+	inv.reserved[asset].Add(inv.reserved[asset], raw)
+	inv.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			inv.mu.Lock()
+			inv.reserved[asset].Sub(inv.reserved[asset], raw)
+			inv.mu.Unlock()
+		})
+	}
+}
+
+// Available returns the whole tokens of asset held and not reserved.
+// Assets that are not tracked tokens are never available.
+func (inv *Inventory) Available(asset string) float64 {
+	inv.mu.RLock()
+	defer inv.mu.RUnlock()
+	available := new(big.Int)
+	if balance := inv.balances[asset]; balance != nil {
+		available.Set(balance)
+	}
+	if reserved := inv.reserved[asset]; reserved != nil {
+		available.Sub(available, reserved)
+	}
+	if available.Sign() <= 0 {
+		return 0
+	}
+	return inv.whole(asset, available)
+}
+
+// Balances returns every balance, ordered by asset.
+func (inv *Inventory) Balances() []Balance {
+	inv.mu.RLock()
+	defer inv.mu.RUnlock()
+	balances := make([]Balance, 0, len(inv.balances)+1)
+	balances = append(balances, Balance{Asset: inv.Native, Native: true, Amount: weiToWhole(inv.native, 18)})
+	balances[0].Available = balances[0].Amount
+	for asset, amount := range inv.balances {
+		b := Balance{Asset: asset, Amount: inv.whole(asset, amount)}
+		if reserved := inv.reserved[asset]; reserved != nil {
+			b.Reserved = inv.whole(asset, reserved)
+		}
+		b.Available = max(b.Amount-b.Reserved, 0)
+		balances = append(balances, b)
+	}
+	tokens := balances[1:]
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].Asset < tokens[j].Asset })
+	return balances
+}
+
+// Tokens returns the tracked tokens.
+func (inv *Inventory) Tokens() []Token {
+	tokens := make([]Token, 0, len(inv.tokens))
+	for _, token := range inv.tokens {
+		tokens = append(tokens, token)
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].Asset < tokens[j].Asset })
+	return tokens
+}
+
+// Token returns the tracked token of an asset.
+func (inv *Inventory) Token(asset string) (Token, bool) {
+	token, ok := inv.byAsset[asset]
+	return token, ok
+}
+
+// Allowance returns the raw units of a tracked token spender may take from
+// the account.
+func (inv *Inventory) Allowance(ctx context.Context, asset string, spender common.Address) (*big.Int, error) {
+	token, ok := inv.byAsset[asset]
+	if !ok {
+		return nil, fmt.Errorf("%s is not a tracked token", asset)
+	}
+	contract := bind.NewBoundContract(token.Address, inv.erc20, inv.Backend, nil, nil)
+	var out []interface{}
+	if err := contract.Call(&bind.CallOpts{Context: ctx}, &out, "allowance", inv.Account, spender); err != nil {
+		return nil, fmt.Errorf("failed to read %s allowance: %v", asset, err)
+	}
+	return *abi.ConvertType(out[0], new(*big.Int)).(**big.Int), nil
+}
+
+// Approve lets spender take up to amount raw units of a tracked token from
+// the account, as an executor takes the input of its routes.
+func (inv *Inventory) Approve(opts *bind.TransactOpts, asset string, spender common.Address, amount *big.Int) (*ethtypes.Transaction, error) {
+	token, ok := inv.byAsset[asset]
+	if !ok {
+		return nil, fmt.Errorf("%s is not a tracked token", asset)
+	}
+	contract := bind.NewBoundContract(token.Address, inv.erc20, inv.Backend, inv.Backend, inv.Backend)
+	tx, err := contract.Transact(opts, "approve", spender, amount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to approve %s: %v", asset, err)
+	}
+	return tx, nil
+}
+
+func (inv *Inventory) whole(asset string, raw *big.Int) float64 {
+	return weiToWhole(raw, inv.byAsset[asset].Decimals)
+}
+
+func (inv *Inventory) raw(asset string, whole float64) *big.Int {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(inv.byAsset[asset].Decimals), nil)
+	raw, _ := new(big.Float).Mul(big.NewFloat(whole), new(big.Float).SetInt(scale)).Int(nil)
+	return raw
+}
+
+func weiToWhole(raw *big.Int, decimals int64) float64 {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(decimals), nil)
+	value, _ := new(big.Float).Quo(new(big.Float).SetInt(raw), new(big.Float).SetInt(scale)).Float64()
+	return value
+}
+
+// LogBalances writes every non-zero balance to the log.
+func (inv *Inventory) LogBalances() {
+	for _, b := range inv.Balances() {
+		if b.Amount == 0 {
+			continue
+		}
+		inv.Log.Info("balance", "account", inv.Account.Hex(), "asset", b.Asset, "native", b.Native, "amount", b.Amount, "reserved", b.Reserved)
+	}
+}
@@ -0,0 +1,63 @@
+package inventory
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"bb/testutil"
+)
+
+// waitAvailable waits for the inventory to hold want whole tokens of asset.
+func waitAvailable(t *testing.T, inv *Inventory, asset string, want float64) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for inv.Available(asset) != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("%g %s available, want %g", inv.Available(asset), asset, want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestWatchReloads follows transfers while watching, counting each once, and
+// picks up those made while not watching when it watches again.
+func TestWatchReloads(t *testing.T) {
+	chain := testutil.NewChain(t)
+	usdc := chain.DeployToken(t, "USDC", 6)
+	chain.Mint(t, usdc, chain.Auth.From, testutil.Amount(100, 6))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	inv, err := New(chain.Client, chain.Auth.From, "ETH", []Token{{Asset: "USDC", Address: usdc, Decimals: 6}}, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	watch := func() (stop func()) {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- inv.Watch(ctx) }()
+		return func() {
+			cancel()
+			if err := <-done; err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	stop := watch()
+	waitAvailable(t, inv, "USDC", 100)
+	chain.Mint(t, usdc, chain.Auth.From, testutil.Amount(50, 6))
+	waitAvailable(t, inv, "USDC", 150)
+	time.Sleep(100 * time.Millisecond)
+	if got := inv.Available("USDC"); got != 150 {
+		t.Fatalf("%g USDC available after the transfer settled, want 150", got)
+	}
+	stop()
+
+	chain.Mint(t, usdc, chain.Auth.From, testutil.Amount(25, 6))
+	stop = watch()
+	defer stop()
+	waitAvailable(t, inv, "USDC", 175)
+}
@@ -0,0 +1,176 @@
+package ledger
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kinds of entries.
+const (
+	Simulated = "simulated" // an opportunity evaluated but not traded
+	Executed  = "executed"  // a trade that reached a final state
+)
+
+// Hop is one swap of a cycle. Amounts are in whole tokens; for executed
+// trades they are the amounts the graph predicted, the realized result being
+// only known for the whole cycle.
+type Hop struct {
+	DEX       string  `json:"dex"`
+	Pair      string  `json:"pair"` // pair address
+	AssetIn   string  `json:"assetIn"`
+	AssetOut  string  `json:"assetOut"`
+	AmountIn  float64 `json:"amountIn"`
+	AmountOut float64 `json:"amountOut"`
+}
+
+// Entry is one cycle in the ledger.
+type Entry struct {
+	ID         string    `json:"id,omitempty"` // correlation ID of the triggering event in the logs
+	Time       time.Time `json:"time"`
+	Chain      string    `json:"chain"`
+	Kind       string    `json:"kind"`
+	Status     string    `json:"status,omitempty"` // final transaction status of executed entries
+	StartAsset string    `json:"startAsset"`
+	Hops       []Hop     `json:"hops"`
+	Block      uint64    `json:"block"`
+	TxHash     string    `json:"txHash,omitempty"`
+	GasUnits   uint64    `json:"gasUnits"`
+	GasCost    float64   `json:"gasCost"` // in the native token
+	PnL        float64   `json:"pnl"`     // in the start asset, gas included
+	PnLUSD     float64   `json:"pnlUSD"`  // gas included
+}
+
+// Shape is the cycle's sequence of assets, without the DEXes, e.g.
+// "ETH>USDC>DAI>ETH".
+func (e *Entry) Shape() string {
+	if len(e.Hops) == 0 {
+		return e.StartAsset
+	}
+	assets := []string{e.Hops[0].AssetIn}
+	for _, hop := range e.Hops {
+		assets = append(assets, hop.AssetOut)
+	}
+	return strings.Join(assets, ">")
+}
+
+// kept is how many of the latest entries of each chain and kind a ledger
+// keeps in memory.
+const kept = 100
+
+// Ledger is an append-only file of entries, one JSON object per line. Entries
+// are never rewritten, so the file survives crashes up to the last line
+// written. The latest entries are also kept in memory; see Recent.
+type Ledger struct {
+	Path string
+
+	mu     sync.Mutex
+	file   *os.File
+	recent map[string][]Entry // by chain and kind, oldest first; nil until read
+}
+
+// Open opens the ledger at path, creating it if it does not exist.
+func Open(path string) (*Ledger, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ledger: %v", err)
+	}
+	return &Ledger{Path: path, file: file}, nil
+}
+
+// Append writes an entry and syncs it to disk.
+func (l *Ledger) Append(e Entry) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode ledger entry: %v", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write ledger: %v", err)
+	}
+	if l.recent != nil {
+		l.keep(e)
+	}
+	return l.file.Sync()
+}
+
+// Recent returns up to n of the latest entries of a kind for a chain, oldest
+// first, and at most kept of them. The file is only read on the first call;
+// later calls are served from memory.
+func (l *Ledger) Recent(chain, kind string, n int) ([]Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.recent == nil {
+		entries, err := Read(l.Path)
+		if err != nil {
+			return nil, err
+		}
+		l.recent = make(map[string][]Entry)
+		for _, e := range entries {
+			l.keep(e)
+		}
+	}
+	entries := l.recent[chain+"|"+kind]
+	if len(entries) > n {
+		entries = entries[len(entries)-n:]
+	}
+	return append([]Entry(nil), entries...), nil
+}
+
+// keep adds an entry to the ones kept in memory.
+func (l *Ledger) keep(e Entry) {
+	key := e.Chain + "|" + e.Kind
+	entries := append(l.recent[key], e)
+	if len(entries) > 2*kept {
+		entries = append([]Entry(nil), entries[len(entries)-kept:]...)
+	}
+	l.recent[key] = entries
+}
+
+// Close closes the ledger file.
+func (l *Ledger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+// Read returns every entry of the ledger at path, oldest first. A missing
+// ledger has no entries; a torn last line, left by a crash, is skipped.
+func Read(path string) ([]Entry, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ledger: %v", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			if !scanner.Scan() {
+				break
+			}
+			return nil, fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ledger: %v", err)
+	}
+	return entries, nil
+}
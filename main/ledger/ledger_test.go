@@ -0,0 +1,189 @@
+package ledger
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// cycle is an entry trading ETH through USDC and DAI on two DEXes.
+func cycle(kind, status string, pnlUSD float64) Entry {
+	return Entry{
+		Time:       time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Chain:      "eth",
+		Kind:       kind,
+		Status:     status,
+		StartAsset: "ETH",
+		Hops: []Hop{
+			{DEX: "uni", Pair: "0x01", AssetIn: "ETH", AssetOut: "USDC"},
+			{DEX: "uni", Pair: "0x02", AssetIn: "USDC", AssetOut: "DAI"},
+			{DEX: "sushi", Pair: "0x03", AssetIn: "DAI", AssetOut: "ETH"},
+		},
+		GasCost: 0.03,
+		PnLUSD:  pnlUSD,
+	}
+}
+
+func TestShape(t *testing.T) {
+	e := cycle(Simulated, "", 0)
+	if got := e.Shape(); got != "ETH>USDC>DAI>ETH" {
+		t.Errorf("shape %q", got)
+	}
+	e.Hops = nil
+	if got := e.Shape(); got != "ETH" {
+		t.Errorf("shape of a cycle without hops %q", got)
+	}
+}
+
+func TestRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+	if entries, err := Read(path); err != nil || entries != nil {
+		t.Fatalf("missing ledger read as %v %v", entries, err)
+	}
+
+	l, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pnl := range []float64{1, 2} {
+		if err := l.Append(cycle(Executed, "mined", pnl)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A crash mid-write leaves a torn last line, which is skipped
+	torn := append(append([]byte(nil), data...), `{"chain":"eth","kind":"exe`...)
+	if err := os.WriteFile(path, torn, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].PnLUSD != 1 || entries[1].PnLUSD != 2 || entries[1].Shape() != "ETH>USDC>DAI>ETH" {
+		t.Fatalf("read %+v", entries)
+	}
+
+	// Anywhere else, a line that does not parse is an error
+	corrupt := append([]byte("not json\n"), data...)
+	if err := os.WriteFile(path, corrupt, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(path); err == nil {
+		t.Fatal("corrupt first line read")
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	entries := []Entry{
+		cycle(Executed, "mined", 6),
+		cycle(Executed, "reverted", -3),
+		cycle(Simulated, "", 9),
+	}
+	entries[1].Chain = "bsc"
+
+	byDEX, err := Aggregate(entries, ByDEX, Query{Chain: "eth"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Two of the three hops are on uni, so it gets two thirds of each entry
+	want := []Row{
+		{Key: "sushi", Simulated: 1, Executed: 1, GasCost: 0.01, PnLUSD: 2, SimPnLUSD: 3},
+		{Key: "uni", Simulated: 2, Executed: 2, GasCost: 0.02, PnLUSD: 4, SimPnLUSD: 6},
+	}
+	checkRows(t, byDEX, want)
+
+	byPair, err := Aggregate(entries, ByPair, Query{Kind: Executed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []Row{
+		{Key: "sushi DAI/ETH (0x03)", Executed: 2, Reverted: 1, GasCost: 0.02, PnLUSD: 1},
+		{Key: "uni DAI/USDC (0x02)", Executed: 2, Reverted: 1, GasCost: 0.02, PnLUSD: 1},
+		{Key: "uni ETH/USDC (0x01)", Executed: 2, Reverted: 1, GasCost: 0.02, PnLUSD: 1},
+	}
+	checkRows(t, byPair, want)
+
+	byShape, err := Aggregate(entries, ByShape, Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRows(t, byShape, []Row{{Key: "ETH>USDC>DAI>ETH", Simulated: 1, Executed: 2, Reverted: 1, GasCost: 0.06, PnLUSD: 3, SimPnLUSD: 9}})
+
+	if _, err := Aggregate(entries, "week", Query{}); err == nil {
+		t.Error("unknown grouping accepted")
+	}
+}
+
+func checkRows(t *testing.T, got, want []Row) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("rows %+v, want %+v", got, want)
+	}
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.Key != w.Key || g.Simulated != w.Simulated || g.Executed != w.Executed || g.Reverted != w.Reverted ||
+			!near(g.GasCost, w.GasCost) || !near(g.PnLUSD, w.PnLUSD) || !near(g.SimPnLUSD, w.SimPnLUSD) {
+			t.Errorf("row %+v, want %+v", g, w)
+		}
+	}
+}
+
+func TestRecent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+	l, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := l.Append(cycle(Simulated, "", float64(i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A reopened ledger starts from the file, then keeps up in memory
+	if l, err = Open(path); err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	if got, err := l.Recent("eth", Simulated, 2); err != nil || len(got) != 2 || got[0].PnLUSD != 1 || got[1].PnLUSD != 2 {
+		t.Fatalf("recent %+v %v", got, err)
+	}
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	for i := 3; i < 3+2*kept; i++ {
+		if err := l.Append(cycle(Simulated, "", float64(i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Append(cycle(Executed, "mined", -1)); err != nil {
+		t.Fatal(err)
+	}
+	got, err := l.Recent("eth", Simulated, 2*kept)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) < kept || got[len(got)-1].PnLUSD != float64(2+2*kept) {
+		t.Fatalf("%d recent entries, latest %+v", len(got), got[len(got)-1])
+	}
+	if got, _ := l.Recent("eth", Executed, 20); len(got) != 1 || got[0].PnLUSD != -1 {
+		t.Errorf("recent trades %+v", got)
+	}
+	if got, _ := l.Recent("bsc", Simulated, 20); len(got) != 0 {
+		t.Errorf("recent entries of another chain %+v", got)
+	}
+}
@@ -0,0 +1,132 @@
+package ledger
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// Groupings for Aggregate.
+const (
+	ByDay   = "day"
+	ByPair  = "pair"
+	ByDEX   = "dex"
+	ByShape = "shape"
+)
+
+// Query selects entries. Zero fields match everything.
+type Query struct {
+	Chain string
+	Kind  string
+	Since time.Time
+	Until time.Time
+}
+
+// Match reports whether an entry is selected.
+func (q Query) Match(e *Entry) bool {
+	return (q.Chain == "" || e.Chain == q.Chain) &&
+		(q.Kind == "" || e.Kind == q.Kind) &&
+		(q.Since.IsZero() || !e.Time.Before(q.Since)) &&
+		(q.Until.IsZero() || e.Time.Before(q.Until))
+}
+
+// Row is the aggregate of one group.
+type Row struct {
+	Key       string  `json:"key"`
+	Simulated int     `json:"simulated"`
+	Executed  int     `json:"executed"`
+	Reverted  int     `json:"reverted"`  // executed entries that did not succeed
+	GasCost   float64 `json:"gasCost"`   // in the native token, executed entries only
+	PnLUSD    float64 `json:"pnlUSD"`    // executed entries only
+	SimPnLUSD float64 `json:"simPnlUSD"` // simulated entries only
+}
+
+// Aggregate groups the entries selected by q by day (UTC), pair, DEX or cycle
+// shape. An entry counts once in every pair and DEX it swaps on, with its gas
+// and PnL split evenly between its hops, so that the groups still add up to
+// the total.
+func Aggregate(entries []Entry, by string, q Query) ([]Row, error) {
+	rows := make(map[string]*Row)
+	add := func(key string, e *Entry, share float64) {
+		row := rows[key]
+		if row == nil {
+			row = &Row{Key: key}
+			rows[key] = row
+		}
+		if e.Kind == Simulated {
+			row.Simulated++
+			row.SimPnLUSD += e.PnLUSD * share
+			return
+		}
+		row.Executed++
+		if e.Status != "mined" {
+			row.Reverted++
+		}
+		row.GasCost += e.GasCost * share
+		row.PnLUSD += e.PnLUSD * share
+	}
+
+	for i := range entries {
+		e := &entries[i]
+		if !q.Match(e) {
+			continue
+		}
+		switch by {
+		case ByDay:
+			add(e.Time.UTC().Format(time.DateOnly), e, 1)
+		case ByShape:
+			add(e.Shape(), e, 1)
+		case ByPair, ByDEX:
+			if len(e.Hops) == 0 {
+				continue
+			}
+			share := 1 / float64(len(e.Hops))
+			for _, hop := range e.Hops {
+				key := hop.DEX
+				if by == ByPair {
+					key = fmt.Sprintf("%s %s/%s (%s)", hop.DEX, hop.AssetIn, hop.AssetOut, hop.Pair)
+					if hop.AssetOut < hop.AssetIn {
+						key = fmt.Sprintf("%s %s/%s (%s)", hop.DEX, hop.AssetOut, hop.AssetIn, hop.Pair)
+					}
+				}
+				add(key, e, share)
+			}
+		default:
+			return nil, fmt.Errorf("unknown grouping %q, expected day, pair, dex or shape", by)
+		}
+	}
+
+	result := make([]Row, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, *row)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result, nil
+}
+
+// WriteTable writes rows as an aligned table with a total line.
+func WriteTable(w io.Writer, by string, rows []Row) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "%s\tsimulated\texecuted\treverted\tgas\tpnl USD\tsimulated pnl USD\t\n", by)
+	var total Row
+	for _, row := range rows {
+		writeRow(tw, row)
+		total.Simulated += row.Simulated
+		total.Executed += row.Executed
+		total.Reverted += row.Reverted
+		total.GasCost += row.GasCost
+		total.PnLUSD += row.PnLUSD
+		total.SimPnLUSD += row.SimPnLUSD
+	}
+	if by != ByPair && by != ByDEX {
+		total.Key = "total"
+		writeRow(tw, total)
+	}
+	return tw.Flush()
+}
+
+func writeRow(w io.Writer, row Row) {
+	fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%.6f\t%.2f\t%.2f\t\n", row.Key, row.Simulated, row.Executed, row.Reverted, row.GasCost, row.PnLUSD, row.SimPnLUSD)
+}
@@ -0,0 +1,114 @@
+package mempool
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"bb/contracts/uniswapv2"
+)
+
+// routerABI holds the swap methods of the Uniswap V2 Router02, which every V2
+// fork router shares.
+const routerABI = `[
+	{"name":"swapExactTokensForTokens","type":"function","inputs":[{"name":"amountIn","type":"uint256"},{"name":"amountOutMin","type":"uint256"},{"name":"path","type":"address[]"},{"name":"to","type":"address"},{"name":"deadline","type":"uint256"}],"outputs":[]},
+	{"name":"swapTokensForExactTokens","type":"function","inputs":[{"name":"amountOut","type":"uint256"},{"name":"amountInMax","type":"uint256"},{"name":"path","type":"address[]"},{"name":"to","type":"address"},{"name":"deadline","type":"uint256"}],"outputs":[]},
+	{"name":"swapExactETHForTokens","type":"function","stateMutability":"payable","inputs":[{"name":"amountOutMin","type":"uint256"},{"name":"path","type":"address[]"},{"name":"to","type":"address"},{"name":"deadline","type":"uint256"}],"outputs":[]},
+	{"name":"swapTokensForExactETH","type":"function","inputs":[{"name":"amountOut","type":"uint256"},{"name":"amountInMax","type":"uint256"},{"name":"path","type":"address[]"},{"name":"to","type":"address"},{"name":"deadline","type":"uint256"}],"outputs":[]},
+	{"name":"swapExactTokensForETH","type":"function","inputs":[{"name":"amountIn","type":"uint256"},{"name":"amountOutMin","type":"uint256"},{"name":"path","type":"address[]"},{"name":"to","type":"address"},{"name":"deadline","type":"uint256"}],"outputs":[]},
+	{"name":"swapETHForExactTokens","type":"function","stateMutability":"payable","inputs":[{"name":"amountOut","type":"uint256"},{"name":"path","type":"address[]"},{"name":"to","type":"address"},{"name":"deadline","type":"uint256"}],"outputs":[]},
+	{"name":"swapExactTokensForTokensSupportingFeeOnTransferTokens","type":"function","inputs":[{"name":"amountIn","type":"uint256"},{"name":"amountOutMin","type":"uint256"},{"name":"path","type":"address[]"},{"name":"to","type":"address"},{"name":"deadline","type":"uint256"}],"outputs":[]},
+	{"name":"swapExactETHForTokensSupportingFeeOnTransferTokens","type":"function","stateMutability":"payable","inputs":[{"name":"amountOutMin","type":"uint256"},{"name":"path","type":"address[]"},{"name":"to","type":"address"},{"name":"deadline","type":"uint256"}],"outputs":[]},
+	{"name":"swapExactTokensForETHSupportingFeeOnTransferTokens","type":"function","inputs":[{"name":"amountIn","type":"uint256"},{"name":"amountOutMin","type":"uint256"},{"name":"path","type":"address[]"},{"name":"to","type":"address"},{"name":"deadline","type":"uint256"}],"outputs":[]}
+]`
+
+var (
+	routerMethods abi.ABI
+	pairMethods   *abi.ABI
+)
+
+func init() {
+	var err error
+	routerMethods, err = abi.JSON(strings.NewReader(routerABI))
+	if err != nil {
+		panic(err)
+	}
+	pairMethods, err = uniswapv2pair.Uniswapv2pairMetaData.GetAbi()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// RouterSwap is a decoded router swap along Path. ExactIn swaps fix Amount as
+// the input, the others fix it as the output of the last hop.
+type RouterSwap struct {
+	Method  string
+	Path    []common.Address
+	ExactIn bool
+	Amount  *big.Int
+}
+
+// PairSwap is a decoded direct call of a pair's swap method.
+type PairSwap struct {
+	Amount0Out *big.Int
+	Amount1Out *big.Int
+}
+
+// DecodeRouterSwap decodes the calldata of a router swap. Swaps paying ETH in
+// take their input from the transaction value.
+func DecodeRouterSwap(tx *ethtypes.Transaction) (*RouterSwap, error) {
+	args, method, err := unpack(&routerMethods, tx.Data())
+	if err != nil {
+		return nil, err
+	}
+
+	swap := &RouterSwap{Method: method.Name}
+	swap.Path, _ = args["path"].([]common.Address)
+	if len(swap.Path) < 2 {
+		return nil, fmt.Errorf("%s has no path", method.Name)
+	}
+
+	switch {
+	case args["amountIn"] != nil:
+		swap.ExactIn, swap.Amount = true, args["amountIn"].(*big.Int)
+	case args["amountOut"] != nil:
+		swap.ExactIn, swap.Amount = false, args["amountOut"].(*big.Int)
+	default:
+		swap.ExactIn, swap.Amount = true, tx.Value()
+	}
+	return swap, nil
+}
+
+// DecodePairSwap decodes the calldata of a pair's swap method.
+func DecodePairSwap(tx *ethtypes.Transaction) (*PairSwap, error) {
+	args, method, err := unpack(pairMethods, tx.Data())
+	if err != nil {
+		return nil, err
+	}
+	if method.Name != "swap" {
+		return nil, fmt.Errorf("%s is not a swap", method.Name)
+	}
+	return &PairSwap{
+		Amount0Out: args["amount0Out"].(*big.Int),
+		Amount1Out: args["amount1Out"].(*big.Int),
+	}, nil
+}
+
+func unpack(contract *abi.ABI, data []byte) (map[string]interface{}, *abi.Method, error) {
+	if len(data) < 4 {
+		return nil, nil, fmt.Errorf("no method selector")
+	}
+	method, err := contract.MethodById(data[:4])
+	if err != nil {
+		return nil, nil, err
+	}
+	args := make(map[string]interface{})
+	if err := method.Inputs.UnpackIntoMap(args, data[4:]); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode %s: %v", method.Name, err)
+	}
+	return args, method, nil
+}
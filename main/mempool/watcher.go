@@ -0,0 +1,286 @@
+package mempool
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"bb/contracts/uniswapv2"
+	"bb/logging"
+	"bb/types"
+)
+
+// Source streams full pending transactions, as gethclient.Client does.
+type Source interface {
+	SubscribeFullPendingTransactions(ctx context.Context, ch chan<- *ethtypes.Transaction) (*rpc.ClientSubscription, error)
+}
+
+// HeadReader reports the latest block, which simulated events are stamped
+// with.
+type HeadReader interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// PendingSwap is a pending transaction that trades against our pairs, with
+// the swap events our pairs would emit once it is mined.
+type PendingSwap struct {
+	ID     string // correlation ID the swap was simulated with
+	Tx     common.Hash
+	Events []types.SwapEvent
+}
+
+type tokenPair struct {
+	dex            string
+	token0, token1 common.Address
+}
+
+// Watcher decodes pending transactions sent to V2 routers or straight to our
+// V2 pairs and simulates them against the reserves the pairs last saw. Each
+// transaction is simulated on its own; pending transactions are not stacked.
+type Watcher struct {
+	Source Source
+	Head   HeadReader
+	Log    *slog.Logger
+
+	routers map[common.Address]string // router address to DEX name
+
+	mu      sync.RWMutex
+	pairs   map[common.Address]*uniswapv2pair.Instance
+	byToken map[tokenPair]*uniswapv2pair.Instance
+}
+
+// NewWatcher indexes the V2 pairs among pairs by address and tokens. routers
+// maps DEX names to router addresses; pairs on DEXes without a router are
+// only watched for direct swap calls.
+func NewWatcher(ctx context.Context, source Source, head HeadReader, pairs []types.Pair, routers map[string]string, logger *slog.Logger) (*Watcher, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	w := &Watcher{
+		Source:  source,
+		Head:    head,
+		Log:     logger,
+		routers: make(map[common.Address]string),
+		pairs:   make(map[common.Address]*uniswapv2pair.Instance),
+		byToken: make(map[tokenPair]*uniswapv2pair.Instance),
+	}
+	for dex, router := range routers {
+		w.routers[common.HexToAddress(router)] = dex
+	}
+	if err := w.Add(ctx, pairs); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Add starts decoding pending swaps against the V2 pairs among pairs.
+func (w *Watcher) Add(ctx context.Context, pairs []types.Pair) error {
+	for _, pair := range pairs {
+		v2, ok := pair.(*uniswapv2pair.Instance)
+		if !ok {
+			continue
+		}
+		token0, token1, err := v2.Tokens(ctx)
+		if err != nil {
+			return err
+		}
+		w.mu.Lock()
+		w.pairs[common.HexToAddress(v2.AddressString)] = v2
+		w.byToken[newTokenPair(v2.DEXName, token0, token1)] = v2
+		w.mu.Unlock()
+	}
+	return nil
+}
+
+// Remove stops decoding pending swaps against pairs.
+func (w *Watcher) Remove(pairs []types.Pair) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, pair := range pairs {
+		address := common.HexToAddress(pair.Address())
+		if v2, ok := w.pairs[address]; ok && v2 == pair {
+			delete(w.pairs, address)
+		}
+		for key, v2 := range w.byToken {
+			if v2 == pair {
+				delete(w.byToken, key)
+			}
+		}
+	}
+}
+
+func newTokenPair(dex string, tokenA, tokenB common.Address) tokenPair {
+	if bytes.Compare(tokenA.Bytes(), tokenB.Bytes()) > 0 {
+		tokenA, tokenB = tokenB, tokenA
+	}
+	return tokenPair{dex: dex, token0: tokenA, token1: tokenB}
+}
+
+// Run subscribes to pending transactions and sends a PendingSwap for each one
+// that moves our pairs, until ctx is cancelled or the subscription ends.
+func (w *Watcher) Run(ctx context.Context, pendingChan chan<- PendingSwap) error {
+	txChan := make(chan *ethtypes.Transaction)
+	sub, err := w.Source.SubscribeFullPendingTransactions(ctx, txChan)
+	if err != nil {
+		return fmt.Errorf("pending transaction subscription failed: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	w.mu.RLock()
+	w.Log.Info("listening for pending swaps", "pairs", len(w.pairs), "routers", len(w.routers))
+	w.mu.RUnlock()
+
+	for {
+		select {
+		case err := <-sub.Err():
+			return fmt.Errorf("pending transaction subscription ended: %v", err)
+		case <-ctx.Done():
+			return nil
+		case tx := <-txChan:
+			if tx.To() == nil {
+				continue
+			}
+			id := logging.NewID()
+			events, err := w.Simulate(logging.WithID(ctx, id), tx)
+			if err != nil {
+				w.Log.DebugContext(logging.WithID(ctx, id), "skipping pending tx", "tx", tx.Hash().Hex(), "err", err)
+				continue
+			}
+			if len(events) == 0 {
+				continue
+			}
+			select {
+			case pendingChan <- PendingSwap{ID: id, Tx: tx.Hash(), Events: events}:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+}
+
+// Simulate returns the swap events tx would cause on our pairs, or none if tx
+// does not trade against them.
+func (w *Watcher) Simulate(ctx context.Context, tx *ethtypes.Transaction) ([]types.SwapEvent, error) {
+	state := make(map[*uniswapv2pair.Instance]uniswapv2pair.Reserves)
+	var order []*uniswapv2pair.Instance
+	reserves := func(pair *uniswapv2pair.Instance) (uniswapv2pair.Reserves, error) {
+		if r, ok := state[pair]; ok {
+			return r, nil
+		}
+		r, err := pair.Reserves(ctx)
+		if err != nil {
+			return r, err
+		}
+		state[pair] = r
+		order = append(order, pair)
+		return r, nil
+	}
+
+	to := *tx.To()
+	if dex, ok := w.routers[to]; ok {
+		swap, err := DecodeRouterSwap(tx)
+		if err != nil {
+			return nil, nil // not a swap, e.g. adding liquidity
+		}
+		hops := make([]*uniswapv2pair.Instance, len(swap.Path)-1)
+		w.mu.RLock()
+		for i := range hops {
+			hops[i] = w.byToken[newTokenPair(dex, swap.Path[i], swap.Path[i+1])]
+		}
+		w.mu.RUnlock()
+		for _, hop := range hops {
+			if hop == nil {
+				return nil, nil // the path leaves our pairs
+			}
+		}
+		if err := simulatePath(hops, swap, reserves, state); err != nil {
+			return nil, fmt.Errorf("%s: %v", swap.Method, err)
+		}
+	} else if pair, ok := w.pair(to); ok {
+		swap, err := DecodePairSwap(tx)
+		if err != nil {
+			return nil, nil
+		}
+		r, err := reserves(pair)
+		if err != nil {
+			return nil, err
+		}
+		if state[pair], err = pair.SimulateSwap(r, swap.Amount0Out, swap.Amount1Out); err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, nil
+	}
+
+	head, err := w.Head.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch head block: %v", err)
+	}
+	events := make([]types.SwapEvent, 0, len(order))
+	for _, pair := range order {
+		events = append(events, pair.SwapEventAt(state[pair], head))
+	}
+	return events, nil
+}
+
+func (w *Watcher) pair(address common.Address) (*uniswapv2pair.Instance, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	pair, ok := w.pairs[address]
+	return pair, ok
+}
+
+// simulatePath applies a router swap hop by hop. Exact output swaps are
+// priced backwards from the last hop, as the router does, then applied.
+func simulatePath(hops []*uniswapv2pair.Instance, swap *RouterSwap, reserves func(*uniswapv2pair.Instance) (uniswapv2pair.Reserves, error), state map[*uniswapv2pair.Instance]uniswapv2pair.Reserves) error {
+	amounts := make([]*big.Int, len(swap.Path))
+	if swap.ExactIn {
+		amounts[0] = swap.Amount
+	} else {
+		amounts[len(amounts)-1] = swap.Amount
+		for i := len(hops) - 1; i >= 0; i-- {
+			r, err := reserves(hops[i])
+			if err != nil {
+				return err
+			}
+			amountIn, _, err := hops[i].SimulateExactOut(r, swap.Path[i], amounts[i+1])
+			if err != nil {
+				return err
+			}
+			amounts[i] = amountIn
+		}
+	}
+
+	for i, pair := range hops {
+		r, err := reserves(pair)
+		if err != nil {
+			return err
+		}
+		amountOut, after, err := pair.SimulateExactIn(r, swap.Path[i], amounts[i])
+		if err != nil {
+			return err
+		}
+		if amounts[i+1] == nil {
+			amounts[i+1] = amountOut
+		}
+		state[pair] = after
+	}
+	return nil
+}
+
+// String describes a pending swap for the log.
+func (p PendingSwap) String() string {
+	pairs := make([]string, 0, len(p.Events))
+	for _, event := range p.Events {
+		pairs = append(pairs, fmt.Sprintf("%s %s/%s", event.DEXName, event.Asset1Name, event.Asset2Name))
+	}
+	return fmt.Sprintf("%s (%s)", p.Tx.Hex(), strings.Join(pairs, ", "))
+}
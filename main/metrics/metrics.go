@@ -0,0 +1,203 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "bb"
+
+// Metrics holds the Prometheus metrics of every pipeline. Each metric is
+// labeled with the chain; Chain returns the view of one chain.
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	swapEvents    *prometheus.CounterVec
+	pairLag       *prometheus.GaugeVec
+	reconnects    *prometheus.CounterVec
+	evaluations   *prometheus.HistogramVec
+	graphNodes    *prometheus.GaugeVec
+	graphEdges    *prometheus.GaugeVec
+	opportunities *prometheus.CounterVec
+	trades        *prometheus.CounterVec
+	rejected      *prometheus.CounterVec
+	gasSpent      *prometheus.CounterVec
+	pnl           *prometheus.GaugeVec
+	halted        *prometheus.GaugeVec
+}
+
+// New creates the metrics on a fresh registry, along with the Go runtime and
+// process metrics.
+func New() *Metrics {
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+		swapEvents: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Name: "swap_events_total",
+			Help: "Swap events received, by pair.",
+		}, []string{"chain", "dex", "pair"}),
+		pairLag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace, Name: "pair_lag_blocks",
+			Help: "Blocks between the head and the pair's latest event, at the last evaluation.",
+		}, []string{"chain", "dex", "pair"}),
+		reconnects: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Name: "subscription_reconnects_total",
+			Help: "Subscriptions re-established after they ended.",
+		}, []string{"chain", "subscription"}),
+		evaluations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Name: "evaluation_seconds",
+			Help:    "Time taken to evaluate the graph for arbitrage.",
+			Buckets: prometheus.ExponentialBuckets(0.001, 2, 14),
+		}, []string{"chain"}),
+		graphNodes: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace, Name: "graph_nodes",
+			Help: "Asset/DEX nodes in the last evaluated graph.",
+		}, []string{"chain"}),
+		graphEdges: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace, Name: "graph_edges",
+			Help: "Edges in the last evaluated graph.",
+		}, []string{"chain"}),
+		opportunities: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Name: "opportunities_total",
+			Help: "Arbitrage cycles detected, after mined swaps or pending ones (backruns).",
+		}, []string{"chain", "source"}),
+		trades: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Name: "trades_total",
+			Help: "Trades executed, by final status.",
+		}, []string{"chain", "status"}),
+		rejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Name: "trades_rejected_total",
+			Help: "Trades refused by the risk engine.",
+		}, []string{"chain"}),
+		gasSpent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Name: "gas_spent",
+			Help: "Gas spent by trades, in the native token.",
+		}, []string{"chain"}),
+		pnl: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace, Name: "realized_pnl_usd",
+			Help: "Realized PnL of trades since start, gas included.",
+		}, []string{"chain"}),
+		halted: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace, Name: "kill_switch_tripped",
+			Help: "1 while the risk engine halts trading.",
+		}, []string{"chain"}),
+	}
+	m.Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.swapEvents, m.pairLag, m.reconnects, m.evaluations, m.graphNodes, m.graphEdges,
+		m.opportunities, m.trades, m.rejected, m.gasSpent, m.pnl, m.halted,
+	)
+	return m
+}
+
+// Chain returns the metrics of one chain.
+func (m *Metrics) Chain(name string) *Chain {
+	return &Chain{m: m, name: name}
+}
+
+// Serve exposes the metrics at /metrics on addr until ctx is cancelled.
+func (m *Metrics) Serve(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{}))
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		// Let scrapes in progress finish, so they see the final counts
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+	slog.Info("serving metrics", "addr", addr, "path", "/metrics")
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Chain records the metrics of one chain. A nil Chain records nothing, so
+// that components can be used without metrics.
+type Chain struct {
+	m    *Metrics
+	name string
+}
+
+// SwapEvent counts an event of a pair.
+func (c *Chain) SwapEvent(dex, pair string) {
+	if c == nil {
+		return
+	}
+	c.m.swapEvents.WithLabelValues(c.name, dex, pair).Inc()
+}
+
+// PairLag sets how many blocks behind the head a pair's latest event is.
+func (c *Chain) PairLag(dex, pair string, blocks uint64) {
+	if c == nil {
+		return
+	}
+	c.m.pairLag.WithLabelValues(c.name, dex, pair).Set(float64(blocks))
+}
+
+// Reconnect counts a subscription re-established.
+func (c *Chain) Reconnect(subscription string) {
+	if c == nil {
+		return
+	}
+	c.m.reconnects.WithLabelValues(c.name, subscription).Inc()
+}
+
+// Evaluation records the duration of an evaluation and the size of its graph.
+func (c *Chain) Evaluation(took time.Duration, nodes, edges int) {
+	if c == nil {
+		return
+	}
+	c.m.evaluations.WithLabelValues(c.name).Observe(took.Seconds())
+	c.m.graphNodes.WithLabelValues(c.name).Set(float64(nodes))
+	c.m.graphEdges.WithLabelValues(c.name).Set(float64(edges))
+}
+
+// Opportunity counts a cycle detected after a mined ("mined") or pending
+// ("pending") swap.
+func (c *Chain) Opportunity(source string) {
+	if c == nil {
+		return
+	}
+	c.m.opportunities.WithLabelValues(c.name, source).Inc()
+}
+
+// Trade records the final status, gas and realized PnL of a trade.
+func (c *Chain) Trade(status string, gas, pnlUSD float64) {
+	if c == nil {
+		return
+	}
+	c.m.trades.WithLabelValues(c.name, status).Inc()
+	c.m.gasSpent.WithLabelValues(c.name).Add(gas)
+	c.m.pnl.WithLabelValues(c.name).Add(pnlUSD)
+}
+
+// Rejected counts a trade refused by the risk engine.
+func (c *Chain) Rejected() {
+	if c == nil {
+		return
+	}
+	c.m.rejected.WithLabelValues(c.name).Inc()
+}
+
+// Halted sets whether the kill switch halts trading.
+func (c *Chain) Halted(halted bool) {
+	if c == nil {
+		return
+	}
+	value := 0.0
+	if halted {
+		value = 1
+	}
+	c.m.halted.WithLabelValues(c.name).Set(value)
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+
+	"github.com/joho/godotenv"
+
+	"bb/config"
+	"bb/logging"
+)
+
+// Output formats of the commands.
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// defaultEnv is the env file loaded unless --env names another.
+const defaultEnv = ".env.mainnet-test"
+
+// options are the flags every command takes: the env file and config to load,
+// the chain to work on and the format to print results in.
+type options struct {
+	Env    string
+	Config string
+	Chain  string
+	Output string
+
+	flags *flag.FlagSet
+}
+
+// newOptions creates the flag set of a command, with the common flags
+// registered. usage is the command line after the command name.
+func newOptions(name, usage, summary string) *options {
+	o := &options{flags: flag.NewFlagSet(name, flag.ContinueOnError)}
+	config := os.Getenv("CONFIG_PATH")
+	if config == "" {
+		config = "config.json"
+	}
+	o.flags.StringVar(&o.Env, "env", defaultEnv, "env file to load")
+	o.flags.StringVar(&o.Config, "config", config, "config file, defaults to $CONFIG_PATH or config.json")
+	o.flags.StringVar(&o.Chain, "chain", "", "chain to work on, all configured chains if empty")
+	o.flags.StringVar(&o.Output, "output", outputText, "output format, text or json")
+	o.flags.Usage = func() {
+		fmt.Fprintf(o.flags.Output(), "usage: bb %s %s\n\n%s\n\nflags:\n", name, usage, summary)
+		o.flags.PrintDefaults()
+	}
+	return o
+}
+
+// parse parses the command line of a command and returns its positional
+// arguments.
+func (o *options) parse(args []string) ([]string, error) {
+	if err := o.flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if o.Output != outputText && o.Output != outputJSON {
+		return nil, fmt.Errorf("unknown output format %q, expected text or json", o.Output)
+	}
+	return o.flags.Args(), nil
+}
+
+// load loads the env file, which may be missing unless named explicitly, and
+// the config, and sets up logging. The banner is shown when banner is set and
+// logs are text.
+func (o *options) load(banner bool) (*config.Config, error) {
+	if err := godotenv.Load(o.Env); err != nil {
+		if o.Env != defaultEnv || !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to load env file %s: %v", o.Env, err)
+		}
+	}
+
+	cfg, err := config.Load(o.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Structured logging; the standard logger, still used by the contract
+	// bindings, writes through it too
+	logger, err := logging.New(os.Stderr, cfg.Log.Format, cfg.Log.Level)
+	if err != nil {
+		return nil, err
+	}
+	if banner && cfg.Log.Format == logging.Text {
+		startLog()
+	}
+	slog.SetDefault(logger)
+	return cfg, nil
+}
+
+// chains returns the configured chains selected by --chain.
+func (o *options) chains(cfg *config.Config) ([]config.Chain, error) {
+	if o.Chain == "" {
+		return cfg.Chains, nil
+	}
+	for _, chain := range cfg.Chains {
+		if chain.Name == o.Chain {
+			return []config.Chain{chain}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown chain %q", o.Chain)
+}
+
+// chain returns the one chain a command works on: the one named by --chain,
+// or the only chain with nodes configured.
+func (o *options) chain(cfg *config.Config) (config.Chain, error) {
+	chains, err := o.chains(cfg)
+	if err != nil {
+		return config.Chain{}, err
+	}
+	if o.Chain == "" {
+		var connected []config.Chain
+		for _, chain := range chains {
+			if len(chain.NodeURLs) > 0 {
+				connected = append(connected, chain)
+			}
+		}
+		chains = connected
+	}
+	switch len(chains) {
+	case 0:
+		return config.Chain{}, fmt.Errorf("no chain with node URLs configured")
+	case 1:
+		return chains[0], nil
+	}
+	return config.Chain{}, fmt.Errorf("several chains configured, select one with --chain")
+}
+
+// print writes v as JSON, or calls text with a tab writer for the text
+// output.
+func (o *options) print(v any, text func(w io.Writer)) error {
+	if o.Output == outputJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	text(w)
+	return w.Flush()
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	"bb/config"
+	"bb/contracts/uniswapv2"
+	"bb/pipeline"
+	"bb/types"
+)
+
+// validatePairsCommand checks every configured pair of the selected chains:
+// V2 pairs must belong to their fork's factory, and every pair must have a
+// readable state with liquidity on both sides.
+func validatePairsCommand(args []string) error {
+	opts := newOptions("validate-pairs", "[flags]", "Checks that every configured pair exists, belongs to its DEX and has liquidity.")
+	if _, err := opts.parse(args); err != nil {
+		return err
+	}
+	cfg, err := opts.load(false)
+	if err != nil {
+		return err
+	}
+	chains, err := opts.chains(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	var checks []pairCheck
+	failed := 0
+	for _, chain := range chains {
+		if len(chain.NodeURLs) == 0 {
+			continue
+		}
+		clients, err := pipeline.DialPool(ctx, chain.NodeURLs)
+		if err != nil {
+			return fmt.Errorf("%s: %v", chain.Name, err)
+		}
+		pairs, err := pipeline.BuildPairs(chain, clients)
+		if err != nil {
+			clients.Close()
+			return fmt.Errorf("%s: %v", chain.Name, err)
+		}
+		for _, pair := range pairs {
+			check := checkPair(ctx, chain.Name, pair)
+			if check.Error != "" {
+				failed++
+			}
+			checks = append(checks, check)
+		}
+		clients.Close()
+	}
+
+	if err := opts.print(checks, func(w io.Writer) {
+		fmt.Fprintf(w, "chain\tdex\tpair\taddress\treserves\tstatus\n")
+		for _, c := range checks {
+			status := "ok"
+			if c.Error != "" {
+				status = c.Error
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s / %s\t%s\n", c.Chain, c.DEX, c.Assets, c.Address, c.Reserve1, c.Reserve2, status)
+		}
+	}); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d pairs failed validation", failed, len(checks))
+	}
+	return nil
+}
+
+// pairCheck is the outcome of validating a pair.
+type pairCheck struct {
+	Chain    string `json:"chain"`
+	DEX      string `json:"dex"`
+	Assets   string `json:"assets"`
+	Address  string `json:"address"`
+	Reserve1 string `json:"reserve1,omitempty"` // whole tokens
+	Reserve2 string `json:"reserve2,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+func checkPair(ctx context.Context, chain string, pair types.Pair) pairCheck {
+	check := pairCheck{Chain: chain, DEX: pair.DEX(), Assets: pair.Asset1() + "/" + pair.Asset2(), Address: pair.Address()}
+
+	if v2, ok := pair.(*uniswapv2pair.Instance); ok {
+		if err := v2.Validate(ctx); err != nil {
+			check.Error = err.Error()
+			return check
+		}
+	}
+	snapshotter, ok := pair.(types.Snapshotter)
+	if !ok {
+		check.Error = "state cannot be read"
+		return check
+	}
+	event, err := snapshotter.Snapshot(ctx)
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+	if event.Reserve1 != nil && event.Reserve2 != nil {
+		check.Reserve1, check.Reserve2 = event.Reserve1.Text('g', 8), event.Reserve2.Text('g', 8)
+	}
+	if event.Reserve1 == nil || event.Reserve1.Sign() <= 0 || event.Reserve2 == nil || event.Reserve2.Sign() <= 0 {
+		check.Error = "no liquidity"
+	}
+	return check
+}
+
+// discoverCommand derives the pair address of every pair of known tokens on
+// every V2 fork with an init code hash, and lists those deployed with
+// liquidity that are not configured yet.
+func discoverCommand(args []string) error {
+	opts := newOptions("discover", "[flags]", "Finds V2 fork pairs of the tokens the config knows that have liquidity but are\nnot configured. The json output is a list of pairs ready for the config.")
+	dexName := opts.flags.String("dex", "", "only search this DEX")
+	if _, err := opts.parse(args); err != nil {
+		return err
+	}
+	cfg, err := opts.load(false)
+	if err != nil {
+		return err
+	}
+	chain, err := opts.chain(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	clients, err := pipeline.DialPool(ctx, chain.NodeURLs)
+	if err != nil {
+		return err
+	}
+	defer clients.Close()
+	pairs, err := pipeline.BuildPairs(chain, clients)
+	if err != nil {
+		return err
+	}
+
+	tokens := knownTokens(ctx, chain, pairs)
+	configured := make(map[string]bool, len(pairs))
+	for _, pair := range pairs {
+		configured[strings.ToLower(pair.Address())] = true
+	}
+
+	var names []string
+	for name, dex := range chain.DEXes {
+		if dex.Kind == config.KindUniswapV2 && dex.InitCodeHash != "" && (*dexName == "" || name == *dexName) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	if len(names) == 0 {
+		return fmt.Errorf("no V2 fork with an init code hash configured on %s", chain.Name)
+	}
+
+	var found []discovery
+	for _, name := range names {
+		dex := chain.DEXes[name]
+		fork := uniswapv2pair.Fork{Name: name, Factory: common.HexToAddress(dex.Factory), InitCodeHash: common.HexToHash(dex.InitCodeHash)}
+		for i := range tokens {
+			for j := i + 1; j < len(tokens); j++ {
+				token0, token1 := tokens[i], tokens[j]
+				if bytes.Compare(token0.Address.Bytes(), token1.Address.Bytes()) > 0 {
+					token0, token1 = token1, token0
+				}
+				address, err := fork.PairAddress(token0.Address, token1.Address)
+				if err != nil {
+					return err
+				}
+				if configured[strings.ToLower(address.Hex())] {
+					continue
+				}
+				d, ok := probePair(ctx, clients, name, address, token0, token1)
+				if ok {
+					found = append(found, d)
+				}
+			}
+		}
+	}
+
+	if opts.Output == outputJSON {
+		candidates := make([]config.Pair, len(found))
+		for i, d := range found {
+			candidates[i] = d.Pair
+		}
+		return opts.print(candidates, nil)
+	}
+	return opts.print(nil, func(w io.Writer) {
+		fmt.Fprintf(w, "dex\tpair\taddress\treserves\n")
+		for _, d := range found {
+			fmt.Fprintf(w, "%s\t%s/%s\t%s\t%s / %s\n", d.DEX, d.Assets[0], d.Assets[1], d.Address, d.Reserve1, d.Reserve2)
+		}
+		fmt.Fprintf(w, "%d pairs not configured\n", len(found))
+	})
+}
+
+// knownToken is a token the config names, by address or through a pair.
+type knownToken struct {
+	Asset    string
+	Address  common.Address
+	Decimals int64
+}
+
+// knownTokens returns the chain's listed tokens and the tokens of its V2
+// pairs, sorted by asset.
+func knownTokens(ctx context.Context, chain config.Chain, pairs []types.Pair) []knownToken {
+	byAsset := make(map[string]knownToken)
+	for asset, t := range chain.Tokens {
+		byAsset[asset] = knownToken{Asset: asset, Address: common.HexToAddress(t.Address), Decimals: t.Decimals}
+	}
+	decimals := make(map[string]int64)
+	for _, p := range chain.Pairs {
+		for i, asset := range p.Assets {
+			if i < len(p.Decimals) {
+				decimals[asset] = p.Decimals[i]
+			}
+			if len(p.Tokens) == len(p.Assets) {
+				if _, ok := byAsset[asset]; !ok {
+					byAsset[asset] = knownToken{Asset: asset, Address: common.HexToAddress(p.Tokens[i]), Decimals: p.Decimals[i]}
+				}
+			}
+		}
+	}
+	for _, pair := range pairs {
+		v2, ok := pair.(*uniswapv2pair.Instance)
+		if !ok {
+			continue
+		}
+		_, known1 := byAsset[v2.Asset1()]
+		_, known2 := byAsset[v2.Asset2()]
+		if known1 && known2 {
+			continue
+		}
+		token0, token1, err := v2.Tokens(ctx)
+		if err != nil {
+			slog.Warn("failed to read pair tokens", "pair", v2.Address(), "err", err)
+			continue
+		}
+		if !known1 {
+			byAsset[v2.Asset1()] = knownToken{Asset: v2.Asset1(), Address: token0, Decimals: decimals[v2.Asset1()]}
+		}
+		if !known2 {
+			byAsset[v2.Asset2()] = knownToken{Asset: v2.Asset2(), Address: token1, Decimals: decimals[v2.Asset2()]}
+		}
+	}
+
+	tokens := make([]knownToken, 0, len(byAsset))
+	for _, t := range byAsset {
+		tokens = append(tokens, t)
+	}
+	slices.SortFunc(tokens, func(a, b knownToken) int { return strings.Compare(a.Asset, b.Asset) })
+	return tokens
+}
+
+// discovery is a deployed pair that is not configured.
+type discovery struct {
+	config.Pair
+	Reserve1 string // whole tokens
+	Reserve2 string
+}
+
+// probePair reads the reserves of a derived pair address, reporting pairs
+// that are deployed and have liquidity.
+func probePair(ctx context.Context, clients *pipeline.ClientPool, dex string, address common.Address, token0, token1 knownToken) (discovery, bool) {
+	pair, err := uniswapv2pair.NewUniswapv2pair(address, clients.Next())
+	if err != nil {
+		return discovery{}, false
+	}
+	reserves, err := pair.GetReserves(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		slog.Debug("no pair", "dex", dex, "asset1", token0.Asset, "asset2", token1.Asset, "address", address.Hex())
+		return discovery{}, false
+	}
+	if reserves.Reserve0.Sign() == 0 || reserves.Reserve1.Sign() == 0 {
+		return discovery{}, false
+	}
+	return discovery{
+		Pair: config.Pair{
+			DEX:      dex,
+			Address:  address.Hex(),
+			Assets:   []string{token0.Asset, token1.Asset},
+			Decimals: []int64{token0.Decimals, token1.Decimals},
+		},
+		Reserve1: toWhole(reserves.Reserve0, token0.Decimals).FloatString(2),
+		Reserve2: toWhole(reserves.Reserve1, token1.Decimals).FloatString(2),
+	}, true
+}
@@ -0,0 +1,77 @@
+package pipeline
+
+import (
+	"fmt"
+	"time"
+
+	"bb/alert"
+	"bb/ledger"
+	"bb/txmanager"
+)
+
+// SetAlerts raises the pipeline's alerts, and its kill switch's, with a.
+func (p *Pipeline) SetAlerts(a *alert.Alerter) {
+	p.Alerts = a
+	p.Risk.OnTrip = func(reason string) {
+		a.Notify(alert.Alert{
+			Kind:     alert.KillSwitch,
+			Severity: alert.Critical,
+			Chain:    p.Name,
+			Title:    "Kill switch tripped",
+			Text:     fmt.Sprintf("Trading is halted until an operator resets it: %s", reason),
+			Key:      reason,
+		})
+	}
+}
+
+// alertEntry raises the alert of a ledger entry: executed trades always
+// alert, opportunities only from the alerter's threshold.
+func (p *Pipeline) alertEntry(e ledger.Entry) {
+	if p.Alerts == nil {
+		return
+	}
+	switch {
+	case e.Kind == ledger.Simulated:
+		if p.Alerts.MinOpportunityUSD <= 0 || e.PnLUSD < p.Alerts.MinOpportunityUSD {
+			return
+		}
+		p.Alerts.Notify(alert.Alert{
+			Kind:     alert.Opportunity,
+			Severity: alert.Info,
+			Chain:    p.Name,
+			Title:    fmt.Sprintf("Opportunity worth $%.2f", e.PnLUSD),
+			Text:     fmt.Sprintf("%s at block %d: %+f %s after gas (id %s)", e.Shape(), e.Block, e.PnL, e.StartAsset, e.ID),
+			Key:      e.Shape(),
+		})
+	case e.Status == txmanager.Reverted.String():
+		p.Alerts.Notify(alert.Alert{
+			Kind:     alert.Revert,
+			Severity: alert.Warning,
+			Chain:    p.Name,
+			Title:    "Trade reverted",
+			Text:     fmt.Sprintf("%s in %s, gas lost $%.2f (id %s)", e.Shape(), e.TxHash, -e.PnLUSD, e.ID),
+			Key:      e.TxHash,
+		})
+	default:
+		p.Alerts.Notify(alert.Alert{
+			Kind:     alert.Trade,
+			Severity: alert.Info,
+			Chain:    p.Name,
+			Title:    fmt.Sprintf("Trade %s, PnL $%.2f", e.Status, e.PnLUSD),
+			Text:     fmt.Sprintf("%s in %s: %+f %s after gas (id %s)", e.Shape(), e.TxHash, e.PnL, e.StartAsset, e.ID),
+			Key:      e.TxHash,
+		})
+	}
+}
+
+// alertOutage raises an outage alert for a subscription down since a time.
+func (p *Pipeline) alertOutage(name string, since time.Time, err error) {
+	p.Alerts.Notify(alert.Alert{
+		Kind:     alert.Outage,
+		Severity: alert.Warning,
+		Chain:    p.Name,
+		Title:    fmt.Sprintf("%s subscription down", name),
+		Text:     fmt.Sprintf("Down for %s, still reconnecting: %v", time.Since(since).Round(time.Second), err),
+		Key:      name,
+	})
+}
@@ -0,0 +1,59 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ClientPool spreads subscriptions and calls for a chain over several nodes.
+type ClientPool struct {
+	clients []*ethclient.Client
+	next    atomic.Uint64
+}
+
+// DialPool connects to every node URL of a chain.
+func DialPool(ctx context.Context, urls []string) (*ClientPool, error) {
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no node URLs")
+	}
+
+	pool := &ClientPool{}
+	for _, url := range urls {
+		client, err := ethclient.DialContext(ctx, url)
+		if err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("failed to dial node: %v", err)
+		}
+		pool.clients = append(pool.clients, client)
+	}
+	return pool, nil
+}
+
+// Next returns the clients in round-robin order.
+func (p *ClientPool) Next() *ethclient.Client {
+	return p.clients[(p.next.Add(1)-1)%uint64(len(p.clients))]
+}
+
+func (p *ClientPool) BlockNumber(ctx context.Context) (uint64, error) {
+	return p.Next().BlockNumber(ctx)
+}
+
+func (p *ClientPool) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return p.Next().SuggestGasPrice(ctx)
+}
+
+func (p *ClientPool) TransactionReceipt(ctx context.Context, txHash common.Hash) (*ethtypes.Receipt, error) {
+	return p.Next().TransactionReceipt(ctx, txHash)
+}
+
+func (p *ClientPool) Close() {
+	for _, client := range p.clients {
+		client.Close()
+	}
+}
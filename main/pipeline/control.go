@@ -0,0 +1,140 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"bb/config"
+	"bb/types"
+)
+
+// Pause stops the pipeline from sending trades and rebalancing swaps. The
+// graph is still evaluated and opportunities still recorded.
+func (p *Pipeline) Pause() {
+	if !p.paused.Swap(true) {
+		p.Log.Warn("execution paused")
+	}
+}
+
+// Resume lets a paused pipeline trade again.
+func (p *Pipeline) Resume() {
+	if p.paused.Swap(false) {
+		p.Log.Warn("execution resumed")
+	}
+}
+
+// Paused reports whether execution is paused.
+func (p *Pipeline) Paused() bool {
+	return p.paused.Load()
+}
+
+// AddPair builds a pair from its config and adds it to the graph and the
+// mempool watcher, monitoring it right away if the pipeline is running. Pools
+// with more than two assets add a pair per coin pair; all of them are
+// returned.
+func (p *Pipeline) AddPair(ctx context.Context, pair config.Pair) ([]types.Pair, error) {
+	chain := p.chain
+	chain.Pairs = []config.Pair{pair}
+	added, err := BuildPairs(chain, p.Clients)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pairs := p.Strategy.Pairs()
+	for _, existing := range pairs {
+		for _, a := range added {
+			if pairKey(existing) == pairKey(a) {
+				return nil, fmt.Errorf("%s %s/%s (%s) is already monitored", a.DEX(), a.Asset1(), a.Asset2(), a.Address())
+			}
+		}
+	}
+	if p.Mempool != nil {
+		if err := p.Mempool.Add(ctx, added); err != nil {
+			return nil, fmt.Errorf("failed to watch pending swaps: %v", err)
+		}
+	}
+	p.Strategy.SetPairs(append(pairs, added...))
+	p.indexTokens(ctx)
+	if p.events != nil {
+		for _, a := range added {
+			p.monitor(a)
+		}
+	}
+	for _, a := range added {
+		p.Log.Info("pair added", "dex", a.DEX(), "asset1", a.Asset1(), "asset2", a.Asset2(), "pair", a.Address())
+	}
+	return added, nil
+}
+
+// RemovePair stops monitoring every pair at an address and takes it out of
+// the graph, returning the pairs removed.
+func (p *Pipeline) RemovePair(address string) ([]types.Pair, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var kept, removed []types.Pair
+	for _, pair := range p.Strategy.Pairs() {
+		if strings.EqualFold(pair.Address(), address) {
+			removed = append(removed, pair)
+		} else {
+			kept = append(kept, pair)
+		}
+	}
+	if len(removed) == 0 {
+		return nil, fmt.Errorf("no pair at %s", address)
+	}
+	p.Strategy.SetPairs(kept)
+	p.indexTokens(context.Background())
+	if p.Mempool != nil {
+		p.Mempool.Remove(removed)
+	}
+	for _, pair := range removed {
+		if stop, ok := p.monitors[pair]; ok {
+			stop()
+			delete(p.monitors, pair)
+		}
+		delete(p.latest, pairKey(pair))
+		p.Strategy.Filter.Forget(pair)
+		p.Log.Info("pair removed", "dex", pair.DEX(), "asset1", pair.Asset1(), "asset2", pair.Asset2(), "pair", pair.Address())
+	}
+	return removed, nil
+}
+
+// LatestEvent returns the latest swap event seen for a pair, with the
+// reserves and rates it was last quoted at.
+func (p *Pipeline) LatestEvent(pair types.Pair) (types.SwapEvent, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	event, ok := p.latest[pairKey(pair)]
+	return event, ok
+}
+
+// monitor starts monitoring a pair until the pipeline stops or the pair is
+// removed, resubscribing whenever its monitor fails. p.mu must be held.
+func (p *Pipeline) monitor(pair types.Pair) {
+	if p.runCtx.Err() != nil {
+		return
+	}
+	ctx, stop := context.WithCancel(p.runCtx)
+	p.monitors[pair] = stop
+	name := fmt.Sprintf("%s %s/%s", pair.DEX(), pair.Asset1(), pair.Asset2())
+	p.work(func() {
+		p.resubscribe(ctx, name, func(ctx context.Context) error { return pair.Monitor(ctx, p.events) })
+	})
+}
+
+// observe keeps a swap event as the latest of its pair.
+func (p *Pipeline) observe(event types.SwapEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.latest[strings.ToLower(event.Address)+":"+event.Asset1Name+"/"+event.Asset2Name] = event
+}
+
+// pairKey identifies a pair by address and assets, since pools with more than
+// two assets expose several pairs at one address.
+func pairKey(pair types.Pair) string {
+	return strings.ToLower(pair.Address()) + ":" + pair.Asset1() + "/" + pair.Asset2()
+}
@@ -0,0 +1,83 @@
+package pipeline
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"bb/types"
+)
+
+// EventLog records swap events, one JSON object per line, so that a session
+// can be replayed through the strategy later.
+type EventLog struct {
+	Path string
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// CreateEventLog opens the event log at path for appending, creating it if
+// it does not exist.
+func CreateEventLog(path string) (*EventLog, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event log: %v", err)
+	}
+	return &EventLog{Path: path, file: file}, nil
+}
+
+// Append writes a swap event. A nil log records nothing.
+func (l *EventLog) Append(e types.SwapEvent) error {
+	if l == nil {
+		return nil
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode swap event: %v", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write event log: %v", err)
+	}
+	return nil
+}
+
+// Close closes the event log file.
+func (l *EventLog) Close() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+// ReadEvents returns the swap events of the event log at path, in the order
+// they were recorded.
+func ReadEvents(path string) ([]types.SwapEvent, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event log: %v", err)
+	}
+	defer file.Close()
+
+	var events []types.SwapEvent
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var e types.SwapEvent
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+		events = append(events, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read event log: %v", err)
+	}
+	return events, nil
+}
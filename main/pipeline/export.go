@@ -0,0 +1,56 @@
+package pipeline
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"bb/strategy"
+)
+
+// exportGraph writes a snapshot of the graph, in every configured format, at
+// the first evaluation at or past each multiple of EveryBlocks blocks.
+func (p *Pipeline) exportGraph(graph *strategy.Graph) {
+	export := p.chain.GraphExport
+	if export == nil {
+		return
+	}
+	bucket := graph.Block/export.EveryBlocks + 1
+	p.mu.Lock()
+	due := bucket > p.exported
+	if due {
+		p.exported = bucket
+	}
+	p.mu.Unlock()
+	if !due {
+		return
+	}
+
+	if err := os.MkdirAll(export.Dir, 0o755); err != nil {
+		p.Log.Warn("failed to export graph", "err", err)
+		return
+	}
+	for _, format := range export.Formats {
+		path, err := WriteGraph(export.Dir, graph, format)
+		if err != nil {
+			p.Log.Warn("failed to export graph", "format", format, "err", err)
+			continue
+		}
+		p.Log.Debug("graph exported", "path", path, "block", graph.Block)
+	}
+}
+
+// WriteGraph writes a graph to <chain>-<block>.<format> in dir and returns
+// the path written.
+func WriteGraph(dir string, graph *strategy.Graph, format string) (string, error) {
+	var b bytes.Buffer
+	if err := graph.Write(&b, format); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s-%d.%s", graph.Chain, graph.Block, format))
+	if err := os.WriteFile(path, b.Bytes(), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write graph: %v", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,97 @@
+package pipeline
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"bb/feed"
+	"bb/ledger"
+	"bb/logging"
+	"bb/strategy"
+	"bb/txmanager"
+)
+
+// entry builds the ledger entry of an opportunity, with the amount of every
+// hop predicted by the graph rates from its amount in, or from one token.
+// Steps between DEXes of the same asset are not hops.
+func (p *Pipeline) entry(o *strategy.Opportunity, prices map[string]float64) ledger.Entry {
+	start := o.Path[0].Asset
+	amountIn := o.AmountIn
+	if amountIn <= 0 {
+		amountIn = 1
+	}
+
+	e := ledger.Entry{Chain: p.Name, Kind: ledger.Simulated, StartAsset: start, Block: o.Block, GasUnits: o.GasUnits}
+	amount := amountIn
+	for i := 0; i+1 < len(o.Path) && i < len(o.Rates); i++ {
+		from, to := o.Path[i], o.Path[i+1]
+		out := amount * o.Rates[i]
+		if from.Asset != to.Asset {
+			hop := ledger.Hop{DEX: from.DEX, AssetIn: from.Asset, AssetOut: to.Asset, AmountIn: amount, AmountOut: out}
+			if i < len(o.Pairs) && o.Pairs[i] != nil {
+				hop.Pair = o.Pairs[i].Address()
+			}
+			e.Hops = append(e.Hops, hop)
+		}
+		amount = out
+	}
+
+	e.GasCost, _ = new(big.Float).Quo(new(big.Float).SetInt(o.GasCost()), big.NewFloat(1e18)).Float64()
+	gasUSD := e.GasCost * prices[p.Native]
+	e.PnLUSD = (amount-amountIn)*prices[start] - gasUSD
+	e.PnL = amount - amountIn
+	if prices[start] > 0 {
+		e.PnL -= gasUSD / prices[start]
+	}
+	return e
+}
+
+// executed completes an opportunity's entry with the final state of its
+// trade and the realized PnL from the account's net token flows.
+func (p *Pipeline) executed(ctx context.Context, e ledger.Entry, tx *txmanager.Tx, prices map[string]float64) ledger.Entry {
+	e.Kind = ledger.Executed
+	e.Status = tx.Status().String()
+	e.TxHash = tx.Hash().Hex()
+	e.GasUnits = tx.GasUsed()
+	if receipt := tx.Receipt(); receipt != nil && receipt.BlockNumber != nil {
+		e.Block = receipt.BlockNumber.Uint64()
+	}
+	e.GasCost, _ = new(big.Float).Quo(new(big.Float).SetInt(tx.GasCost()), big.NewFloat(1e18)).Float64()
+	gasUSD := e.GasCost * prices[p.Native]
+
+	outputs := tx.Outputs()
+	e.PnLUSD = p.valueUSD(outputs, prices) - gasUSD
+	e.PnL = 0
+	for address, amount := range outputs {
+		if t, ok := p.known(address); ok && t.Asset == e.StartAsset {
+			whole, _ := new(big.Float).Quo(new(big.Float).SetInt(amount), new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(t.Decimals), nil))).Float64()
+			e.PnL += whole
+		}
+	}
+	if price := prices[e.StartAsset]; price > 0 {
+		e.PnL -= gasUSD / price
+	}
+	return e
+}
+
+// record publishes an entry, alerts on it and appends it to the ledger, if
+// the pipeline keeps one, under the correlation ID of ctx.
+func (p *Pipeline) record(ctx context.Context, e ledger.Entry) {
+	e.ID = logging.ID(ctx)
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	if e.Kind == ledger.Executed {
+		p.Feed.Publish(feed.Trade, p.Name, e)
+	} else {
+		p.Feed.Publish(feed.Opportunity, p.Name, e)
+	}
+	p.alertEntry(e)
+	if p.Ledger == nil {
+		return
+	}
+	if err := p.Ledger.Append(e); err != nil {
+		p.Log.ErrorContext(ctx, "failed to record cycle", "err", err)
+	}
+}
@@ -0,0 +1,102 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"bb/config"
+	"bb/types"
+
+	"bb/contracts/balancer"
+	"bb/contracts/curve"
+	"bb/contracts/uniswapv2"
+	"bb/contracts/uniswapv3"
+)
+
+// BuildPairs instantiates every pair configured for a chain, spreading them
+// over the client pool. Curve and Balancer pools contribute one pair per coin
+// pair.
+func BuildPairs(chain config.Chain, clients *ClientPool) ([]types.Pair, error) {
+	var pairs []types.Pair
+
+	for _, p := range chain.Pairs {
+		client := clients.Next()
+		dex, ok := chain.DEXes[p.DEX]
+		if !ok {
+			return nil, fmt.Errorf("pair %s uses unknown dex %q", p.Address, p.DEX)
+		}
+		if len(p.Assets) < 2 || len(p.Assets) != len(p.Decimals) {
+			return nil, fmt.Errorf("pair %s on %s needs matching assets and decimals", p.Address, p.DEX)
+		}
+
+		switch dex.Kind {
+		case config.KindUniswapV2:
+			fork := newFork(p.DEX, dex)
+			if pips := p.Fee.Pips(); pips != 0 {
+				// A fee configured on the pair is an explicit override of
+				// whatever the fork would read on-chain.
+				fork.FeePips = pips
+				fork.FeeMethod, fork.DynamicFee = "", false
+			}
+			address := p.Address
+			if address == "" {
+				if len(p.Tokens) != 2 {
+					return nil, fmt.Errorf("%s %s/%s needs an address or two tokens", p.DEX, p.Assets[0], p.Assets[1])
+				}
+				derived, err := fork.PairAddress(common.HexToAddress(p.Tokens[0]), common.HexToAddress(p.Tokens[1]))
+				if err != nil {
+					return nil, err
+				}
+				address = derived.Hex()
+			}
+			pair, err := uniswapv2pair.NewInstance(address, client, fork, p.Assets[0], p.Assets[1], p.Decimals[0], p.Decimals[1])
+			if err != nil {
+				return nil, fmt.Errorf("%s %s/%s: %v", p.DEX, p.Assets[0], p.Assets[1], err)
+			}
+			if err := pair.Validate(context.Background()); err != nil {
+				slog.Warn("pair failed validation", "chain", chain.Name, "err", err)
+			}
+			pairs = append(pairs, pair)
+		case config.KindUniswapV3:
+			pool, err := uniswapv3pool.NewInstance(p.Address, client, p.Assets[0], p.Assets[1], p.Decimals[0], p.Decimals[1])
+			if err != nil {
+				return nil, fmt.Errorf("%s %s/%s: %v", p.DEX, p.Assets[0], p.Assets[1], err)
+			}
+			pool.DEXName = p.DEX
+			pairs = append(pairs, pool)
+		case config.KindCurve:
+			pool, err := curvepool.NewPool(p.Address, client, p.Assets, p.Decimals)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", p.DEX, err)
+			}
+			pool.DEXName = p.DEX
+			pairs = append(pairs, pool.Pairs()...)
+		case config.KindBalancer:
+			pool, err := balancerpool.NewPool(p.Address, client, p.Assets, p.Decimals)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", p.DEX, err)
+			}
+			pool.DEXName = p.DEX
+			pairs = append(pairs, pool.Pairs()...)
+		default:
+			return nil, fmt.Errorf("dex %s has unknown kind %q", p.DEX, dex.Kind)
+		}
+	}
+
+	return pairs, nil
+}
+
+func newFork(name string, dex config.DEX) uniswapv2pair.Fork {
+	return uniswapv2pair.Fork{
+		Name:           name,
+		Factory:        common.HexToAddress(dex.Factory),
+		InitCodeHash:   common.HexToHash(dex.InitCodeHash),
+		FeePips:        dex.Fee.Pips(),
+		FeeMethod:      dex.FeeMethod,
+		FeeDenominator: dex.FeeDenominator,
+		DynamicFee:     dex.DynamicFee,
+	}
+}
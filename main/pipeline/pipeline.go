@@ -0,0 +1,547 @@
+package pipeline
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+
+	"bb/alert"
+	"bb/bundle"
+	"bb/config"
+	"bb/executor"
+	"bb/feed"
+	"bb/inventory"
+	"bb/ledger"
+	"bb/logging"
+	"bb/mempool"
+	"bb/metrics"
+	"bb/rebalance"
+	"bb/risk"
+	"bb/signer"
+	"bb/strategy"
+	"bb/txmanager"
+	"bb/types"
+)
+
+// Stats counts what a pipeline has seen and done.
+type Stats struct {
+	SwapEvents    atomic.Uint64
+	Evaluations   atomic.Uint64
+	Opportunities atomic.Uint64
+	PendingSwaps  atomic.Uint64
+	Backruns      atomic.Uint64
+	Trades        atomic.Uint64
+	Rejected      atomic.Uint64 // trades refused by the risk engine
+}
+
+// Pipeline runs the strategy for one chain: it monitors the chain's pairs and
+// evaluates the graph on every swap event. Pipelines for different chains are
+// independent and can run side by side.
+type Pipeline struct {
+	Name       string
+	ChainID    *big.Int
+	Clients    *ClientPool
+	Strategy   *strategy.Strategy // holds the pairs; see AddPair and RemovePair
+	Mempool    *mempool.Watcher   // nil unless the chain enables it
+	Bundles    *bundle.Submitter  // nil unless the chain has a relay; sends every transaction
+	Txs        *txmanager.Manager
+	Executor   *executor.Executor   // nil unless the chain has one; carries out every swap
+	Transact   *signer.TransactOpts // transactor factory shared by every trade
+	Risk       *risk.Engine
+	Inventory  *inventory.Inventory
+	Rebalancer *rebalance.Rebalancer // nil unless the chain sets targets
+	Ledger     *ledger.Ledger        // records every cycle if set
+	Metrics    *metrics.Chain        // nil records nothing; see SetMetrics
+	Feed       *feed.Feed            // nil publishes nothing
+	Alerts     *alert.Alerter        // nil raises nothing; see SetAlerts
+	Events     *EventLog             // records every swap event if set
+	Native     string                // asset gas is paid in
+	Stats      Stats
+	Log        *slog.Logger
+
+	chain    config.Chain
+	paused   atomic.Bool
+	closing  atomic.Bool        // set by Shutdown; no new trades
+	exec     context.Context    // outlives Run, for trades in flight
+	stopExec context.CancelFunc // ends exec
+	inflight sync.WaitGroup     // trades not yet final
+	workers  sync.WaitGroup     // goroutines started by Run
+	mu       sync.Mutex
+	events   chan types.SwapEvent // nil until Run
+	runCtx   context.Context
+	monitors map[types.Pair]context.CancelFunc
+	latest   map[string]types.SwapEvent // by pair key
+	exported uint64                     // block bucket of the latest graph export, plus one
+	approval map[string]*txmanager.Tx   // latest executor approval of each asset
+	tokensMu sync.RWMutex
+	tokens   map[common.Address]token // by address; see indexTokens
+}
+
+// New dials a chain's nodes, builds its pairs and strategy and reads the
+// balances of its inventory. Every trade is signed by s. Everything the
+// pipeline logs is labeled with the chain.
+func New(ctx context.Context, chain config.Chain, s signer.Signer) (*Pipeline, error) {
+	logger := slog.Default().With("chain", chain.Name)
+
+	clients, err := DialPool(ctx, chain.NodeURLs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", chain.Name, err)
+	}
+	logger.Info("connected", "nodes", len(chain.NodeURLs))
+
+	pairs, err := BuildPairs(chain, clients)
+	if err != nil {
+		clients.Close()
+		return nil, fmt.Errorf("%s: %v", chain.Name, err)
+	}
+
+	transact := signer.NewTransactOpts(s, big.NewInt(chain.ChainID))
+
+	limits := risk.Limits{
+		MaxNotionalUSD:        chain.Risk.MaxNotionalUSD,
+		MaxDailyLossUSD:       chain.Risk.MaxDailyLossUSD,
+		MaxGasPerHour:         chain.Risk.MaxGasPerHour,
+		MaxConsecutiveReverts: chain.Risk.MaxConsecutiveReverts,
+		MaxExposureUSD:        chain.Risk.MaxExposureUSD,
+	}
+	riskEngine, err := risk.NewEngine(limits, chain.Risk.StateFile, logger)
+	if err != nil {
+		clients.Close()
+		return nil, fmt.Errorf("%s: %v", chain.Name, err)
+	}
+
+	p := &Pipeline{
+		Name:     chain.Name,
+		ChainID:  big.NewInt(chain.ChainID),
+		Clients:  clients,
+		Strategy: NewStrategy(chain, clients, pairs, logger),
+		Txs:      txmanager.NewManager(clients.Next(), transact.Opts(context.Background()), logger),
+		Transact: transact,
+		Risk:     riskEngine,
+		Native:   chain.NativeAsset,
+		Log:      logger,
+		chain:    chain,
+		monitors: make(map[types.Pair]context.CancelFunc),
+		latest:   make(map[string]types.SwapEvent),
+		approval: make(map[string]*txmanager.Tx),
+	}
+	p.exec, p.stopExec = context.WithCancel(context.Background())
+
+	p.indexTokens(ctx)
+	tokens := p.tokens
+	for asset, t := range chain.Tokens {
+		tokens[common.HexToAddress(t.Address)] = token{Asset: asset, Decimals: t.Decimals}
+	}
+	held := make([]inventory.Token, 0, len(tokens))
+	for address, t := range tokens {
+		held = append(held, inventory.Token{Asset: t.Asset, Address: address, Decimals: t.Decimals})
+	}
+	if p.Inventory, err = inventory.New(clients.Next(), s.Address(), chain.NativeAsset, held, logger); err != nil {
+		clients.Close()
+		return nil, fmt.Errorf("%s: %v", chain.Name, err)
+	}
+	if err := p.Inventory.Load(ctx); err != nil {
+		clients.Close()
+		return nil, fmt.Errorf("%s: %v", chain.Name, err)
+	}
+	p.Inventory.LogBalances()
+	p.Strategy.Holdings = p.Inventory
+
+	if chain.Executor != "" {
+		p.Executor = executor.New(common.HexToAddress(chain.Executor), clients.Next())
+		owner, err := p.Executor.Owner(ctx)
+		if err != nil {
+			clients.Close()
+			return nil, fmt.Errorf("%s: %v", chain.Name, err)
+		}
+		if owner != s.Address() {
+			clients.Close()
+			return nil, fmt.Errorf("%s: executor %s is owned by %s, not the trading account", chain.Name, chain.Executor, owner.Hex())
+		}
+		logger.Info("swapping through executor", "address", chain.Executor)
+	}
+
+	if r := chain.Rebalance; r != nil {
+		p.Rebalancer = &rebalance.Rebalancer{
+			Targets:     r.Targets,
+			Threshold:   r.Threshold,
+			MinTradeUSD: r.MinTradeUSD,
+			MaxHops:     r.MaxHops,
+			Slippage:    r.Slippage,
+			Interval:    time.Duration(r.IntervalSeconds) * time.Second,
+			DryRun:      r.DryRun,
+			Paused:      p.Paused,
+			Pairs:       p.Strategy.Pairs,
+			Inventory:   p.Inventory,
+			Executor:    p.Executor,
+			Risk:        riskEngine,
+			Txs:         p.Txs,
+			Prices:      p.Strategy.Prices,
+			Gas:         p.Strategy.Gas,
+			Client:      clients,
+			Native:      chain.NativeAsset,
+			Log:         logger,
+			Detach:      p.track,
+		}
+	}
+
+	if chain.Mempool {
+		routers := make(map[string]string)
+		for name, dex := range chain.DEXes {
+			if dex.Kind == config.KindUniswapV2 && dex.Router != "" {
+				routers[name] = dex.Router
+			}
+		}
+		source := gethclient.New(clients.Next().Client())
+		p.Mempool, err = mempool.NewWatcher(ctx, source, clients, pairs, routers, logger)
+		if err != nil {
+			clients.Close()
+			return nil, fmt.Errorf("%s: %v", chain.Name, err)
+		}
+	}
+
+	if chain.Relay != nil && chain.Relay.URL != "" {
+		var authKey *ecdsa.PrivateKey
+		if chain.Relay.AuthKey != "" {
+			if authKey, err = crypto.HexToECDSA(strings.TrimPrefix(chain.Relay.AuthKey, "0x")); err != nil {
+				clients.Close()
+				return nil, fmt.Errorf("%s: invalid relay auth key: %v", chain.Name, err)
+			}
+		}
+		relay, err := bundle.NewRelay(chain.Relay.URL, authKey)
+		if err != nil {
+			clients.Close()
+			return nil, fmt.Errorf("%s: %v", chain.Name, err)
+		}
+		p.Bundles = bundle.NewSubmitter(relay, clients, chain.Relay.MaxBlocks, chain.Relay.Simulate, logger)
+		p.Txs.Private = p.Bundles
+		logger.Info("submitting bundles", "relay", chain.Relay.URL)
+	}
+	return p, nil
+}
+
+// NewStrategy creates the strategy of a chain's pairs, with the chain's
+// filters and gas model.
+func NewStrategy(chain config.Chain, client strategy.ChainReader, pairs []types.Pair, logger *slog.Logger) *strategy.Strategy {
+	gas := strategy.GasModel{
+		BaseGas:         chain.Gas.BaseGas,
+		GasPerHop:       chain.Gas.GasPerHop,
+		PriceMultiplier: chain.Gas.PriceMultiplier,
+	}
+	return strategy.NewStrategy(chain.Name, client, pairs, NewFilter(chain.Filters), gas, logger)
+}
+
+// NewFilter builds the pair filter for a chain's filter settings.
+func NewFilter(filters config.Filters) *strategy.Filter {
+	overrides := make(map[string]strategy.PairRule)
+	for address, rule := range filters.Pairs {
+		overrides[address] = strategy.PairRule{
+			MinLiquidityUSD: rule.MinLiquidityUSD,
+			MaxStaleBlocks:  rule.MaxStaleBlocks,
+		}
+	}
+
+	global := strategy.PairRule{
+		MinLiquidityUSD: filters.MinLiquidityUSD,
+		MaxStaleBlocks:  filters.MaxStaleBlocks,
+	}
+	return strategy.NewFilter(global, overrides, filters.StableAssets)
+}
+
+// Run monitors the pairs and evaluates the strategy until ctx is cancelled,
+// and returns once every pair monitor and subscription has stopped. Trades in
+// flight, trades and rebalancing swaps, are still followed; see Shutdown.
+func (p *Pipeline) Run(ctx context.Context) {
+	swapEventChan := make(chan types.SwapEvent)
+
+	p.mu.Lock()
+	p.events, p.runCtx = swapEventChan, ctx
+	for _, pair := range p.Strategy.Pairs() {
+		p.monitor(pair)
+	}
+	p.mu.Unlock()
+
+	var pendingChan chan mempool.PendingSwap
+	if p.Mempool != nil {
+		pendingChan = make(chan mempool.PendingSwap)
+		p.work(func() {
+			p.resubscribe(ctx, "mempool", func(ctx context.Context) error {
+				return p.Mempool.Run(ctx, pendingChan)
+			})
+		})
+	}
+
+	go p.Txs.Run(p.exec)
+
+	p.work(func() { p.resubscribe(ctx, "inventory", p.Inventory.Watch) })
+
+	if p.Rebalancer != nil {
+		p.work(func() { p.Rebalancer.Run(ctx) })
+	}
+
+	p.monitorProcesses(ctx, swapEventChan, pendingChan)
+	p.workers.Wait()
+}
+
+// work runs f in a goroutine that Run waits for.
+func (p *Pipeline) work(f func()) {
+	p.workers.Add(1)
+	go func() {
+		defer p.workers.Done()
+		f()
+	}()
+}
+
+// Trade sends a transaction for an opportunity, starting with amountIn whole
+// tokens of its first asset. The pipeline must not be paused or shutting
+// down, its first asset and the native token must have USD prices, and the
+// trade must pass the risk engine; it is then sent through the transaction
+// manager with amountIn reserved in the inventory, and its outcome is
+// reported to the strategy, the risk engine, the inventory and the ledger
+// once it is final.
+func (p *Pipeline) Trade(ctx context.Context, opportunity *strategy.Opportunity, amountIn float64, build func(opts *bind.TransactOpts) (*ethtypes.Transaction, error)) (*txmanager.Tx, error) {
+	if p.Paused() {
+		return nil, fmt.Errorf("trade rejected: execution paused")
+	}
+	if p.closing.Load() {
+		return nil, fmt.Errorf("trade rejected: shutting down")
+	}
+	prices := p.Strategy.Prices()
+	asset := opportunity.Path[0].Asset
+	// Without prices the limits, and the PnL the kill switch watches, would
+	// value the trade at nothing
+	for _, priced := range []string{asset, p.Native} {
+		if prices[priced] <= 0 {
+			p.Stats.Rejected.Add(1)
+			p.Metrics.Rejected()
+			return nil, fmt.Errorf("trade rejected: no price for %s", priced)
+		}
+	}
+	gasCost, _ := new(big.Float).Quo(new(big.Float).SetInt(opportunity.GasCost()), big.NewFloat(1e18)).Float64()
+
+	position, err := p.Risk.Open(risk.Trade{
+		Asset:       asset,
+		NotionalUSD: amountIn * prices[asset],
+		GasCost:     gasCost,
+	})
+	if err != nil {
+		p.Stats.Rejected.Add(1)
+		p.Metrics.Rejected()
+		p.Log.WarnContext(ctx, "trade rejected", "err", err)
+		halted, _ := p.Risk.Status()
+		p.Metrics.Halted(halted)
+		return nil, fmt.Errorf("trade rejected: %v", err)
+	}
+
+	release := p.Inventory.Reserve(asset, amountIn)
+	tx, err := p.Txs.Transact(ctx, fmt.Sprintf("trade %v", opportunity.Path), build)
+	if err != nil {
+		release()
+		p.Risk.Close(position, nil)
+		return nil, err
+	}
+	p.Stats.Trades.Add(1)
+
+	// The outcome is followed past the evaluation that led to the trade, and
+	// past Run, until Shutdown gives up on it
+	p.inflight.Add(1)
+	go func() {
+		defer p.inflight.Done()
+		defer release()
+		ctx, cancel := p.detach(ctx)
+		defer cancel()
+		status, err := tx.Wait(ctx)
+		if err != nil {
+			return
+		}
+		p.Inventory.ApplyReceipt(tx.Current(), tx.Receipt())
+		outputs := tx.Outputs()
+		gasCost, _ := new(big.Float).Quo(new(big.Float).SetInt(tx.GasCost()), big.NewFloat(1e18)).Float64()
+		outcome := &risk.Outcome{
+			Reverted: status == txmanager.Reverted,
+			PnLUSD:   p.valueUSD(outputs, prices) - gasCost*prices[p.Native],
+			GasCost:  gasCost,
+		}
+		p.Risk.Close(position, outcome)
+		p.Metrics.Trade(status.String(), outcome.GasCost, outcome.PnLUSD)
+		halted, _ := p.Risk.Status()
+		p.Metrics.Halted(halted)
+		p.Strategy.Record(ctx, strategy.Execution{
+			Opportunity: opportunity,
+			TxHash:      tx.Hash(),
+			Status:      status.String(),
+			GasUsed:     tx.GasUsed(),
+			GasCost:     tx.GasCost(),
+			Outputs:     outputs,
+		})
+		p.record(ctx, p.executed(ctx, p.entry(opportunity, prices), tx, prices))
+	}()
+	return tx, nil
+}
+
+// SetMetrics records the pipeline's metrics, and its strategy's, in m.
+func (p *Pipeline) SetMetrics(m *metrics.Metrics) {
+	p.Metrics = m.Chain(p.Name)
+	p.Strategy.Metrics = p.Metrics
+	halted, _ := p.Risk.Status()
+	p.Metrics.Halted(halted)
+}
+
+// resubscribe runs a subscription until ctx is cancelled, starting it again
+// with a growing delay whenever it ends, and counts the reconnects. A run
+// lasting longer than the longest delay counts as recovered; a subscription
+// that has not recovered within the alerter's OutageAfter raises an alert.
+func (p *Pipeline) resubscribe(ctx context.Context, name string, run func(ctx context.Context) error) {
+	const minDelay, maxDelay = time.Second, time.Minute
+	delay := minDelay
+	var down time.Time // start of the current outage
+	alerted := false
+	for {
+		started := time.Now()
+		err := run(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			err = fmt.Errorf("ended")
+		}
+		if time.Since(started) > maxDelay {
+			delay = minDelay
+			down, alerted = time.Time{}, false
+		}
+		if down.IsZero() {
+			down = time.Now()
+		}
+		p.Log.Warn("subscription ended, reconnecting", "subscription", name, "err", err, "delay", delay)
+		if p.Alerts != nil && !alerted && time.Since(down) >= p.Alerts.OutageAfter {
+			p.alertOutage(name, down, err)
+			alerted = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxDelay)
+		p.Metrics.Reconnect(name)
+	}
+}
+
+// monitorProcesses evaluates the graph on every mined swap and on the state
+// after every pending swap. A new event of either kind cancels the evaluation
+// in progress; pending swaps are not kept in the history. Each event gets a
+// correlation ID that its evaluation, and any trade it leads to, is logged
+// with.
+func (p *Pipeline) monitorProcesses(ctx context.Context, swapEventChan <-chan types.SwapEvent, pendingChan <-chan mempool.PendingSwap) {
+	var swapEvents []types.SwapEvent
+	var wg sync.WaitGroup
+	tradeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			cancel()
+			wg.Wait() // Wait for all goroutines to finish before returning
+			return
+		case swapEvent := <-swapEventChan:
+			p.Stats.SwapEvents.Add(1)
+			p.Metrics.SwapEvent(swapEvent.DEXName, swapEvent.Address)
+			p.observe(swapEvent)
+			if err := p.Events.Append(swapEvent); err != nil {
+				p.Log.Warn("failed to record swap event", "err", err)
+			}
+			id := logging.NewID()
+			p.Log.InfoContext(logging.WithID(ctx, id), "swap event", "dex", swapEvent.DEXName, "asset1", swapEvent.Asset1Name, "asset2", swapEvent.Asset2Name, "pair", swapEvent.Address, "block", swapEvent.BlockNumber)
+
+			// Store the swapEvent in the slice for history tracking
+			swapEvents = append(swapEvents, swapEvent)
+
+			// Cancel any ongoing trade execution and start a new one
+			cancel()
+			wg.Wait() // Wait for the previous goroutine to finish
+			tradeCtx, cancel = context.WithCancel(ctx)
+
+			wg.Add(1)
+			go func(ctx context.Context, swapEvents []types.SwapEvent) {
+				defer wg.Done()
+				p.Stats.Evaluations.Add(1)
+				opportunity := p.Strategy.Evaluate(ctx, swapEvents)
+				p.publishGraph()
+				if opportunity != nil {
+					p.Stats.Opportunities.Add(1)
+					p.Metrics.Opportunity("mined")
+					p.record(ctx, p.entry(opportunity, p.Strategy.Prices()))
+					p.execute(ctx, opportunity)
+				}
+			}(logging.WithID(tradeCtx, id), swapEvents)
+		case pending := <-pendingChan:
+			p.Stats.PendingSwaps.Add(1)
+			p.Log.InfoContext(logging.WithID(ctx, pending.ID), "pending swap", "swap", pending.String())
+
+			// Evaluate the state after the pending swap on top of the history
+			events := append(append([]types.SwapEvent(nil), swapEvents...), pending.Events...)
+
+			cancel()
+			wg.Wait()
+			tradeCtx, cancel = context.WithCancel(ctx)
+
+			wg.Add(1)
+			go func(ctx context.Context, events []types.SwapEvent) {
+				defer wg.Done()
+				p.Stats.Evaluations.Add(1)
+				opportunity := p.Strategy.Evaluate(ctx, events)
+				p.publishGraph()
+				if opportunity != nil {
+					p.Stats.Opportunities.Add(1)
+					p.Stats.Backruns.Add(1)
+					p.Metrics.Opportunity("pending")
+					// The cycle is priced on a state that only exists once the
+					// pending transaction lands, so it is recorded, not traded
+					p.Log.InfoContext(ctx, "backrun opportunity, not traded", "pendingTx", pending.Tx.Hex())
+					p.record(ctx, p.entry(opportunity, p.Strategy.Prices()))
+				}
+			}(logging.WithID(tradeCtx, pending.ID), events)
+		}
+	}
+}
+
+// publishGraph publishes the graph of the latest evaluation, and exports it
+// when a snapshot is due.
+func (p *Pipeline) publishGraph() {
+	if graph := p.Strategy.Graph(); graph != nil {
+		p.Feed.Publish(feed.Graph, p.Name, graph)
+		p.exportGraph(graph)
+	}
+}
+
+// LogSummary writes the counters of every pipeline, and their totals, to the
+// default logger.
+func LogSummary(pipelines []*Pipeline) {
+	var events, evaluations, opportunities, pending, backruns, trades, rejected uint64
+	for _, p := range pipelines {
+		e, v, o := p.Stats.SwapEvents.Load(), p.Stats.Evaluations.Load(), p.Stats.Opportunities.Load()
+		ps, b := p.Stats.PendingSwaps.Load(), p.Stats.Backruns.Load()
+		t, r := p.Stats.Trades.Load(), p.Stats.Rejected.Load()
+		p.Log.Info("summary", "swapEvents", e, "pendingSwaps", ps, "evaluations", v, "opportunities", o, "backruns", b, "trades", t, "rejected", r)
+		events, evaluations, opportunities, pending, backruns = events+e, evaluations+v, opportunities+o, pending+ps, backruns+b
+		trades, rejected = trades+t, rejected+r
+		if halted, reason := p.Risk.Status(); halted {
+			p.Log.Warn("trading halted by kill switch", "reason", reason)
+		}
+	}
+	slog.Info("summary", "chain", "all", "swapEvents", events, "pendingSwaps", pending, "evaluations", evaluations, "opportunities", opportunities, "backruns", backruns, "trades", trades, "rejected", rejected)
+}
@@ -0,0 +1,86 @@
+package pipeline
+
+import (
+	"context"
+	"time"
+
+	"bb/config"
+	"bb/txmanager"
+)
+
+// Shutdown handles the trades in flight once Run has returned, refusing new
+// ones. With the cancel policy every pending transaction is first replaced by
+// a cancellation; with either policy they are then followed until final, so
+// that their outcomes still reach the risk engine, the inventory and the
+// ledger, or until ctx ends. It then stops following transactions, closes the
+// node connections and returns the transactions still pending.
+func (p *Pipeline) Shutdown(ctx context.Context, policy string) []*txmanager.Tx {
+	p.closing.Store(true)
+
+	pending := p.Txs.Pending()
+	if len(pending) > 0 {
+		p.Log.Info("waiting for transactions in flight", "transactions", len(pending), "policy", policy)
+	}
+	if policy == config.ShutdownCancel {
+		for _, tx := range pending {
+			if tx.Cancelling() {
+				continue
+			}
+			if err := p.Txs.Cancel(ctx, tx); err != nil {
+				p.Log.Warn("failed to cancel transaction", "label", tx.Label, "tx", tx.Hash().Hex(), "err", err)
+			}
+		}
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		p.inflight.Wait()
+		close(drained)
+	}()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for done := false; !done || len(p.Txs.Pending()) > 0; {
+		select {
+		case <-ctx.Done():
+			p.Log.Warn("gave up on transactions in flight", "transactions", len(p.Txs.Pending()))
+			return p.close()
+		case <-drained:
+			drained, done = nil, true
+		case <-ticker.C:
+		}
+	}
+	return p.close()
+}
+
+// close stops following transactions and disconnects from the nodes,
+// returning the transactions still pending.
+func (p *Pipeline) close() []*txmanager.Tx {
+	p.stopExec()
+	pending := p.Txs.Pending()
+	p.Clients.Close()
+	return pending
+}
+
+// detach returns a context with the values of ctx, such as its correlation
+// ID, that ends when the pipeline stops following trades rather than with
+// ctx.
+func (p *Pipeline) detach(ctx context.Context) (context.Context, context.CancelFunc) {
+	detached, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(p.exec, cancel)
+	return detached, func() {
+		stop()
+		cancel()
+	}
+}
+
+// track returns a detached context for work that must be followed past Run,
+// like a rebalance, and counts it among the trades in flight until done is
+// called.
+func (p *Pipeline) track(ctx context.Context) (context.Context, func()) {
+	p.inflight.Add(1)
+	ctx, cancel := p.detach(ctx)
+	return ctx, func() {
+		cancel()
+		p.inflight.Done()
+	}
+}
@@ -0,0 +1,70 @@
+package pipeline
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"bb/types"
+)
+
+type token struct {
+	Asset    string
+	Decimals int64
+}
+
+// indexTokens rebuilds the map of token addresses to assets from the
+// inventory and every pair that can tell its tokens. Pairs read their token
+// addresses once, so this only calls the chain for pairs it has not seen;
+// it runs whenever pairs are added or removed.
+func (p *Pipeline) indexTokens(ctx context.Context) {
+	tokens := make(map[common.Address]token)
+	if p.Inventory != nil {
+		for _, t := range p.Inventory.Tokens() {
+			tokens[t.Address] = token{Asset: t.Asset, Decimals: t.Decimals}
+		}
+	}
+	for _, pair := range p.Strategy.Pairs() {
+		reader, ok := pair.(types.TokenReader)
+		if !ok {
+			continue
+		}
+		token1, token2, err := reader.PairTokens(ctx)
+		if err != nil {
+			p.Log.WarnContext(ctx, "failed to read pair tokens", "dex", pair.DEX(), "pair", pair.Address(), "err", err)
+			continue
+		}
+		for _, t := range []types.Token{token1, token2} {
+			tokens[t.Address] = token{Asset: t.Asset, Decimals: t.Decimals}
+		}
+	}
+
+	p.tokensMu.Lock()
+	p.tokens = tokens
+	p.tokensMu.Unlock()
+}
+
+// known returns the token of an address, if the inventory or a pair has it.
+func (p *Pipeline) known(address common.Address) (token, bool) {
+	p.tokensMu.RLock()
+	defer p.tokensMu.RUnlock()
+	t, ok := p.tokens[address]
+	return t, ok
+}
+
+// valueUSD values net token flows at prices. Tokens without a known asset or
+// price count as worthless.
+func (p *Pipeline) valueUSD(outputs map[common.Address]*big.Int, prices map[string]float64) float64 {
+	var total float64
+	for address, amount := range outputs {
+		t, ok := p.known(address)
+		if !ok {
+			continue
+		}
+		whole := new(big.Float).Quo(new(big.Float).SetInt(amount), new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(t.Decimals), nil)))
+		value, _ := whole.Float64()
+		total += value * prices[t.Asset]
+	}
+	return total
+}
@@ -0,0 +1,63 @@
+package pipeline
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"bb/strategy"
+	"bb/testutil"
+	"bb/types"
+)
+
+// listedPool is a test pool that tells its tokens, counting the reads.
+type listedPool struct {
+	*testutil.Pool
+	addresses [2]common.Address
+	reads     int
+}
+
+func (l *listedPool) PairTokens(ctx context.Context) (types.Token, types.Token, error) {
+	l.reads++
+	return types.Token{Asset: l.Asset1(), Address: l.addresses[0], Decimals: 18},
+		types.Token{Asset: l.Asset2(), Address: l.addresses[1], Decimals: 6}, nil
+}
+
+// TestIndexTokens values trade outputs from the tokens indexed when the pairs
+// last changed, without asking the pairs again.
+func TestIndexTokens(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	eth, usdc := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	listed := &listedPool{Pool: testutil.NewPool("curve", "ETH", "USDC", big.NewInt(1), big.NewInt(1)), addresses: [2]common.Address{eth, usdc}}
+	unlisted := testutil.NewPool("uni", "ETH", "DAI", big.NewInt(1), big.NewInt(1))
+	p := &Pipeline{
+		Strategy: strategy.NewStrategy("sim", headReader{}, []types.Pair{listed, unlisted}, strategy.NewFilter(strategy.PairRule{}, nil, nil), strategy.GasModel{}, logger),
+		Log:      logger,
+	}
+	p.indexTokens(context.Background())
+
+	outputs := map[common.Address]*big.Int{
+		eth:                         testutil.Amount(2, 18),
+		usdc:                        testutil.Amount(-100, 6),
+		common.HexToAddress("0x03"): testutil.Amount(7, 18),
+	}
+	prices := map[string]float64{"ETH": 2000, "USDC": 1}
+	for i := 0; i < 2; i++ {
+		if got := p.valueUSD(outputs, prices); got != 3900 {
+			t.Fatalf("outputs valued at %g, want 3900", got)
+		}
+	}
+	if listed.reads != 1 {
+		t.Errorf("pair tokens read %d times, want once", listed.reads)
+	}
+
+	p.Strategy.SetPairs([]types.Pair{unlisted})
+	p.indexTokens(context.Background())
+	if got := p.valueUSD(outputs, prices); got != 0 {
+		t.Errorf("outputs of a removed pair valued at %g", got)
+	}
+}
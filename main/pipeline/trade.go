@@ -0,0 +1,146 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"bb/executor"
+	"bb/strategy"
+)
+
+// execute trades an opportunity's cycle through the executor. Opportunities
+// without an amount to trade, found on a chain without an executor or while
+// execution is paused are only recorded, and so are those whose quoted gain
+// does not beat their gas cost by the chain's minProfitUSD. The first trade
+// of an asset waits for the executor to be approved to take it.
+func (p *Pipeline) execute(ctx context.Context, opportunity *strategy.Opportunity) {
+	if p.Executor == nil || opportunity.AmountIn <= 0 {
+		return
+	}
+	if p.Paused() {
+		p.Log.InfoContext(ctx, "not trading, execution paused")
+		return
+	}
+	route, err := p.cycleRoute(opportunity)
+	if err != nil {
+		p.Log.WarnContext(ctx, "cannot trade opportunity", "err", err)
+		return
+	}
+	gainUSD, gasUSD, err := p.expectedGain(opportunity, route.AmountIn)
+	if err != nil {
+		p.Log.WarnContext(ctx, "cannot value opportunity", "err", err)
+		return
+	}
+	if gainUSD <= gasUSD+p.chain.Gas.MinProfitUSD {
+		p.Log.InfoContext(ctx, "not trading, gain does not cover gas", "gainUSD", gainUSD, "gasUSD", gasUSD, "minProfitUSD", p.chain.Gas.MinProfitUSD)
+		return
+	}
+	if !p.approved(ctx, opportunity.Path[0].Asset, route.AmountIn) {
+		return
+	}
+	_, err = p.Trade(ctx, opportunity, opportunity.AmountIn, func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return p.Executor.Execute(opts, route)
+	})
+	if err != nil {
+		p.Log.WarnContext(ctx, "trade not sent", "err", err)
+	}
+}
+
+// cycleRoute builds the executor route of an opportunity's cycle. Each hop
+// sells whatever the hop before paid out, and the route reverts unless it
+// pays back at least the amount it took: a cycle can lose its gas, never its
+// input.
+func (p *Pipeline) cycleRoute(o *strategy.Opportunity) (executor.Route, error) {
+	start := o.Path[0].Asset
+	in, ok := p.Inventory.Token(start)
+	if !ok {
+		return executor.Route{}, fmt.Errorf("%s is not a tracked token", start)
+	}
+	amount, _ := new(big.Float).Mul(big.NewFloat(o.AmountIn), new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(in.Decimals), nil))).Int(nil)
+	route := executor.Route{TokenIn: in.Address, AmountIn: amount, TokenOut: in.Address, MinAmountOut: amount}
+	for i := 0; i+1 < len(o.Path); i++ {
+		from, to := o.Path[i].Asset, o.Path[i+1].Asset
+		if from == to {
+			continue
+		}
+		if i >= len(o.Pairs) || o.Pairs[i] == nil {
+			return executor.Route{}, fmt.Errorf("no pair to sell %s for %s", from, to)
+		}
+		tokenIn, ok := p.Inventory.Token(from)
+		if !ok {
+			return executor.Route{}, fmt.Errorf("%s is not a tracked token", from)
+		}
+		calls, err := o.Pairs[i].SwapCalls(p.Executor.Address, tokenIn.Address, from, nil)
+		if err != nil {
+			return executor.Route{}, err
+		}
+		route.Calls = append(route.Calls, calls...)
+		if tokenIn.Address != in.Address {
+			route.Sweep = append(route.Sweep, tokenIn.Address)
+		}
+	}
+	if len(route.Calls) == 0 {
+		return executor.Route{}, fmt.Errorf("cycle %v has no hops", o.Path)
+	}
+	return route, nil
+}
+
+// expectedGain values in USD what an opportunity's cycle is quoted to gain
+// on amount raw units of its first asset, at the pairs' last known states, and
+// its estimated gas cost.
+func (p *Pipeline) expectedGain(o *strategy.Opportunity, amount *big.Int) (gainUSD, gasUSD float64, err error) {
+	prices := p.Strategy.Prices()
+	start := o.Path[0].Asset
+	if prices[start] <= 0 {
+		return 0, 0, fmt.Errorf("no price for %s", start)
+	}
+	if prices[p.Native] <= 0 {
+		return 0, 0, fmt.Errorf("no price for %s", p.Native)
+	}
+	out := amount
+	for i := 0; i+1 < len(o.Path) && i < len(o.Pairs); i++ {
+		if o.Pairs[i] == nil {
+			continue
+		}
+		if out, err = o.Pairs[i].Quote(o.Path[i].Asset, out); err != nil {
+			return 0, 0, fmt.Errorf("failed to quote %s on %s: %v", o.Path[i].Asset, o.Pairs[i].DEX(), err)
+		}
+	}
+	token, _ := p.Inventory.Token(start)
+	gain, _ := new(big.Float).Quo(new(big.Float).SetInt(new(big.Int).Sub(out, amount)), new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(token.Decimals), nil))).Float64()
+	gasCost, _ := new(big.Float).Quo(new(big.Float).SetInt(o.GasCost()), big.NewFloat(1e18)).Float64()
+	return gain * prices[start], gasCost * prices[p.Native], nil
+}
+
+// approved reports whether the executor may take amount of asset from the
+// account. If it may not, the executor is approved for all of it, once:
+// trades of the asset wait until the approval is final. Evaluations run one
+// at a time, and so does approved.
+func (p *Pipeline) approved(ctx context.Context, asset string, amount *big.Int) bool {
+	if approval := p.approval[asset]; approval != nil && !approval.Status().Final() {
+		return false
+	}
+	allowance, err := p.Inventory.Allowance(ctx, asset, p.Executor.Address)
+	if err != nil {
+		p.Log.WarnContext(ctx, "failed to read executor allowance", "asset", asset, "err", err)
+		return false
+	}
+	if allowance.Cmp(amount) >= 0 {
+		return true
+	}
+	approval, err := p.Txs.Transact(ctx, fmt.Sprintf("approve %s for the executor", asset), func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return p.Inventory.Approve(opts, asset, p.Executor.Address, abi.MaxUint256)
+	})
+	if err != nil {
+		p.Log.WarnContext(ctx, "failed to approve executor", "asset", asset, "err", err)
+		return false
+	}
+	p.approval[asset] = approval
+	p.Log.InfoContext(ctx, "approving executor, trading once approved", "asset", asset, "tx", approval.Hash().Hex())
+	return false
+}
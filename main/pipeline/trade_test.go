@@ -0,0 +1,237 @@
+package pipeline
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"bb/contracts/uniswapv2"
+	"bb/inventory"
+	"bb/risk"
+	"bb/strategy"
+	"bb/testutil"
+	"bb/txmanager"
+	"bb/types"
+)
+
+// TestExecuteOpportunity trades an ETH cycle between two pairs that price ETH
+// apart, on a simulated chain. Nothing goes out while execution is paused or
+// the risk engine is halted, nor for a cycle whose quoted gain does not beat
+// its gas by the margin; otherwise the cycle goes out through the executor
+// once it is approved.
+func TestExecuteOpportunity(t *testing.T) {
+	chain := testutil.NewChain(t)
+	eth := chain.DeployToken(t, "ETH", 18)
+	dai := chain.DeployToken(t, "DAI", 18)
+	names := map[common.Address]string{eth: "ETH", dai: "DAI"}
+	pair := func(dex string, reserveETH, reserveDAI float64) *uniswapv2pair.Instance {
+		factory := chain.DeployFactory(t)
+		token0, token1 := testutil.SortTokens(eth, dai)
+		reserves := map[common.Address]*big.Int{eth: testutil.Amount(reserveETH, 18), dai: testutil.Amount(reserveDAI, 18)}
+		address := chain.DeployPair(t, testutil.PairState{Factory: factory, Token0: token0, Token1: token1, Reserve0: reserves[token0], Reserve1: reserves[token1]})
+		fork := uniswapv2pair.Fork{Name: dex, Factory: factory, InitCodeHash: testutil.PairInitCodeHash(), FeePips: 3000}
+		p, err := uniswapv2pair.NewInstance(address.Hex(), chain.Client, fork, names[token0], names[token1], 18, 18)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	cheap, dear := pair("uni", 100, 200000), pair("sushi", 100, 220000)
+	chain.Mint(t, eth, chain.Auth.From, testutil.Amount(10, 18))
+	exec := chain.DeployExecutor(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		ticker := time.NewTicker(20 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				chain.Backend.Commit()
+			}
+		}
+	}()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	held := []inventory.Token{{Asset: "ETH", Address: eth, Decimals: 18}, {Asset: "DAI", Address: dai, Decimals: 18}}
+	inv, err := inventory.New(chain.Client, chain.Auth.From, "ETH", held, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := inv.Load(ctx); err != nil {
+		t.Fatal(err)
+	}
+	engine, err := risk.NewEngine(risk.Limits{MaxNotionalUSD: 1e6, MaxDailyLossUSD: 1e6}, "", logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := txmanager.NewManager(chain.Client, chain.Auth, logger)
+	manager.Poll = 10 * time.Millisecond
+	go manager.Run(ctx)
+
+	p := &Pipeline{
+		Name:      "sim",
+		Strategy:  strategy.NewStrategy("sim", chain.Client, []types.Pair{cheap, dear}, strategy.NewFilter(strategy.PairRule{}, nil, []string{"DAI"}), strategy.GasModel{}, logger),
+		Txs:       manager,
+		Executor:  exec,
+		Risk:      engine,
+		Inventory: inv,
+		Native:    "ETH",
+		Log:       logger,
+		approval:  make(map[string]*txmanager.Tx),
+	}
+	p.exec, p.stopExec = context.WithCancel(context.Background())
+	defer p.stopExec()
+	p.chain.Gas.MinProfitUSD = 10
+
+	// Evaluate the pairs once, for the prices trades are valued at
+	var events []types.SwapEvent
+	for _, pair := range []*uniswapv2pair.Instance{cheap, dear} {
+		event, err := pair.Snapshot(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, event)
+	}
+	p.Strategy.Evaluate(ctx, events)
+	if prices := p.Strategy.Prices(); prices["ETH"] <= 0 {
+		t.Fatalf("prices %v", prices)
+	}
+
+	// Sell ETH where it is dear and buy it back where it is cheap
+	opportunity := &strategy.Opportunity{
+		Chain:    "sim",
+		Path:     []strategy.AssetDEX{{Asset: "ETH", DEX: "sushi"}, {Asset: "DAI", DEX: "sushi"}, {Asset: "DAI", DEX: "uni"}, {Asset: "ETH", DEX: "uni"}},
+		AmountIn: 1,
+		Pairs:    []types.Pair{dear, nil, cheap},
+	}
+	nonce := func() uint64 {
+		n, err := chain.Client.PendingNonceAt(ctx, chain.Auth.From)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	start := nonce()
+
+	p.Pause()
+	p.execute(ctx, opportunity)
+	if n := nonce(); n != start {
+		t.Fatalf("paused pipeline sent %d transactions", n-start)
+	}
+	p.Resume()
+
+	// The first trade of ETH only approves the executor
+	p.execute(ctx, opportunity)
+	approval := p.approval["ETH"]
+	if approval == nil {
+		t.Fatal("executor not approved")
+	}
+	if status, err := approval.Wait(ctx); err != nil || status != txmanager.Mined {
+		t.Fatalf("approval %v %v", status, err)
+	}
+	if p.Stats.Trades.Load() != 0 {
+		t.Fatal("traded before the executor was approved")
+	}
+
+	engine.Halt("test")
+	before := nonce()
+	p.execute(ctx, opportunity)
+	if n := nonce(); n != before || p.Stats.Rejected.Load() != 1 {
+		t.Fatalf("halted risk engine let %d transactions through, rejected %d", n-before, p.Stats.Rejected.Load())
+	}
+	engine.Reset("test")
+
+	// The reverse cycle loses on both pairs; the profitable one gains less
+	// than its gas at 10 kgwei
+	losing := &strategy.Opportunity{
+		Chain:    "sim",
+		Path:     []strategy.AssetDEX{{Asset: "ETH", DEX: "uni"}, {Asset: "DAI", DEX: "uni"}, {Asset: "DAI", DEX: "sushi"}, {Asset: "ETH", DEX: "sushi"}},
+		AmountIn: 1,
+		Pairs:    []types.Pair{cheap, nil, dear},
+	}
+	costly := *opportunity
+	costly.GasUnits, costly.GasPrice = 300000, big.NewInt(1e13)
+	for _, o := range []*strategy.Opportunity{losing, &costly} {
+		p.execute(ctx, o)
+		if n := nonce(); n != before {
+			t.Fatalf("unprofitable cycle sent %d transactions", n-before)
+		}
+	}
+
+	// Nor does a gain that beats the gas but not the margin
+	p.chain.Gas.MinProfitUSD = 1e6
+	p.execute(ctx, opportunity)
+	if n := nonce(); n != before {
+		t.Fatalf("cycle under the margin sent %d transactions", n-before)
+	}
+	p.chain.Gas.MinProfitUSD = 10
+
+	p.execute(ctx, opportunity)
+	if p.Stats.Trades.Load() != 1 {
+		t.Fatalf("%d trades sent, want 1", p.Stats.Trades.Load())
+	}
+	p.inflight.Wait()
+	executions := p.Strategy.Executions()
+	if len(executions) != 1 || executions[0].Status != txmanager.Mined.String() {
+		t.Fatalf("executions %+v", executions)
+	}
+	if got := chain.BalanceOf(t, eth, chain.Auth.From); got.Cmp(testutil.Amount(10, 18)) <= 0 {
+		t.Errorf("%s ETH after the cycle, want more than 10", got)
+	}
+	for token, asset := range names {
+		if got := chain.BalanceOf(t, token, exec.Address); got.Sign() != 0 {
+			t.Errorf("executor holds %s %s", got, asset)
+		}
+	}
+}
+
+// headReader is a chain at a fixed head block and gas price.
+type headReader struct{}
+
+func (headReader) BlockNumber(ctx context.Context) (uint64, error) { return 1, nil }
+
+func (headReader) SuggestGasPrice(ctx context.Context) (*big.Int, error) { return big.NewInt(1e9), nil }
+
+// TestTradeNeedsPrices refuses trades the risk engine could only value at
+// nothing: from an asset without a price, or while gas has none.
+func TestTradeNeedsPrices(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	engine, err := risk.NewEngine(risk.Limits{MaxNotionalUSD: 1, MaxDailyLossUSD: 1}, "", logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Pipeline{
+		Name:     "sim",
+		Strategy: strategy.NewStrategy("sim", headReader{}, nil, strategy.NewFilter(strategy.PairRule{}, nil, []string{"DAI"}), strategy.GasModel{}, logger),
+		Risk:     engine,
+		Native:   "ETH",
+		Log:      logger,
+	}
+	// Only the stable asset has a price
+	p.Strategy.Evaluate(context.Background(), nil)
+
+	for _, c := range []struct{ start, unpriced string }{{"WBTC", "WBTC"}, {"DAI", "ETH"}} {
+		opportunity := &strategy.Opportunity{Chain: "sim", Path: []strategy.AssetDEX{{Asset: c.start, DEX: "uni"}, {Asset: "ETH", DEX: "uni"}, {Asset: c.start, DEX: "uni"}}}
+		_, err := p.Trade(context.Background(), opportunity, 1e9, func(*bind.TransactOpts) (*ethtypes.Transaction, error) {
+			t.Fatal("trade built")
+			return nil, nil
+		})
+		if err == nil {
+			t.Fatalf("trade from %s passed without a price for %s", c.start, c.unpriced)
+		}
+	}
+	if got := p.Stats.Rejected.Load(); got != 2 {
+		t.Errorf("%d trades rejected, want 2", got)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"bb/ledger"
+)
+
+// ledgerCommand prints the ledger aggregated by day, pair, DEX or cycle
+// shape.
+func ledgerCommand(args []string) error {
+	opts := newOptions("ledger", "[flags] day|pair|dex|shape [chain]", "Aggregates the ledger by day (UTC), pair, DEX or cycle shape.")
+	kind := opts.flags.String("kind", "", "only entries of this kind, simulated or executed")
+	since := opts.flags.String("since", "", "only entries from this day on, as 2006-01-02")
+	until := opts.flags.String("until", "", "only entries before this day, as 2006-01-02")
+	args, err := opts.parse(args)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 || len(args) > 2 {
+		opts.flags.Usage()
+		return fmt.Errorf("expected day, pair, dex or shape")
+	}
+	cfg, err := opts.load(false)
+	if err != nil {
+		return err
+	}
+
+	query := ledger.Query{Chain: opts.Chain, Kind: *kind}
+	if len(args) > 1 {
+		query.Chain = args[1]
+	}
+	if query.Since, err = parseDay(*since); err != nil {
+		return err
+	}
+	if query.Until, err = parseDay(*until); err != nil {
+		return err
+	}
+
+	entries, err := ledger.Read(cfg.Ledger)
+	if err != nil {
+		return err
+	}
+	rows, err := ledger.Aggregate(entries, args[0], query)
+	if err != nil {
+		return err
+	}
+	if opts.Output == outputJSON {
+		return opts.print(rows, nil)
+	}
+	return ledger.WriteTable(os.Stdout, args[0], rows)
+}
+
+// parseDay parses a UTC day, the zero time if day is empty.
+func parseDay(day string) (time.Time, error) {
+	if day == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.DateOnly, day)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid day %q, expected 2006-01-02", day)
+	}
+	return t, nil
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"slices"
+	"strings"
+
+	"bb/config"
+	"bb/pipeline"
+	"bb/types"
+)
+
+// quoteCommand quotes a swap of a whole-token amount through one configured
+// pair at its current state.
+func quoteCommand(args []string) error {
+	opts := newOptions("quote", "[flags] <pair address> <amount>", "Quotes swapping amount whole tokens through a configured pair at its current state.")
+	assetIn := opts.flags.String("asset", "", "asset to swap in, the pair's first asset if empty")
+	assetOut := opts.flags.String("to", "", "asset to swap to, for pools of more than two assets")
+	args, err := opts.parse(args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 2 {
+		opts.flags.Usage()
+		return fmt.Errorf("expected a pair address and an amount")
+	}
+	cfg, err := opts.load(false)
+	if err != nil {
+		return err
+	}
+	chain, err := opts.chain(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	clients, err := pipeline.DialPool(ctx, chain.NodeURLs)
+	if err != nil {
+		return err
+	}
+	defer clients.Close()
+
+	pair, decimals, err := findPair(chain, clients, args[0], *assetIn, *assetOut)
+	if err != nil {
+		return err
+	}
+	if *assetIn == "" {
+		*assetIn = pair.Asset1()
+		if *assetOut == pair.Asset1() {
+			*assetIn = pair.Asset2()
+		}
+	}
+	out := pair.Asset2()
+	if *assetIn == pair.Asset2() {
+		out = pair.Asset1()
+	}
+
+	amountIn, err := toRaw(args[1], decimals[*assetIn])
+	if err != nil {
+		return err
+	}
+	amountOut, err := pair.Quote(*assetIn, amountIn)
+	if err != nil {
+		return err
+	}
+
+	q := quote{
+		Chain:        chain.Name,
+		DEX:          pair.DEX(),
+		Pair:         pair.Address(),
+		AssetIn:      *assetIn,
+		AssetOut:     out,
+		AmountIn:     toWhole(amountIn, decimals[*assetIn]).FloatString(int(decimals[*assetIn])),
+		AmountOut:    toWhole(amountOut, decimals[out]).FloatString(int(decimals[out])),
+		RawAmountIn:  amountIn.String(),
+		RawAmountOut: amountOut.String(),
+	}
+	q.Price, _ = new(big.Rat).Quo(toWhole(amountOut, decimals[out]), toWhole(amountIn, decimals[*assetIn])).Float64()
+	return opts.print(q, func(w io.Writer) {
+		fmt.Fprintf(w, "%s %s %s/%s (%s)\n", q.Chain, q.DEX, pair.Asset1(), pair.Asset2(), q.Pair)
+		fmt.Fprintf(w, "in\t%s %s\t(%s raw)\n", q.AmountIn, q.AssetIn, q.RawAmountIn)
+		fmt.Fprintf(w, "out\t%s %s\t(%s raw)\n", q.AmountOut, q.AssetOut, q.RawAmountOut)
+		fmt.Fprintf(w, "price\t%g %s per %s\t\n", q.Price, q.AssetOut, q.AssetIn)
+	})
+}
+
+// quote is the result of the quote command.
+type quote struct {
+	Chain        string  `json:"chain"`
+	DEX          string  `json:"dex"`
+	Pair         string  `json:"pair"`
+	AssetIn      string  `json:"assetIn"`
+	AssetOut     string  `json:"assetOut"`
+	AmountIn     string  `json:"amountIn"`  // whole tokens
+	AmountOut    string  `json:"amountOut"` // whole tokens
+	RawAmountIn  string  `json:"rawAmountIn"`
+	RawAmountOut string  `json:"rawAmountOut"`
+	Price        float64 `json:"price"` // assetOut per assetIn, price impact and fees included
+}
+
+// findPair builds the configured pair at address trading assetIn, and
+// assetOut if set, and returns it with the decimals of its assets. Pairs
+// configured by tokens have their address derived.
+func findPair(chain config.Chain, clients *pipeline.ClientPool, address, assetIn, assetOut string) (types.Pair, map[string]int64, error) {
+	for _, p := range chain.Pairs {
+		if p.Address != "" && !strings.EqualFold(p.Address, address) {
+			continue
+		}
+		if assetIn != "" && !slices.Contains(p.Assets, assetIn) {
+			continue
+		}
+		one := chain
+		one.Pairs = []config.Pair{p}
+		pairs, err := pipeline.BuildPairs(one, clients)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, pair := range pairs {
+			if !strings.EqualFold(pair.Address(), address) {
+				continue
+			}
+			if assetIn != "" && pair.Asset1() != assetIn && pair.Asset2() != assetIn {
+				continue
+			}
+			if assetOut != "" && pair.Asset1() != assetOut && pair.Asset2() != assetOut {
+				continue
+			}
+			decimals := make(map[string]int64, len(p.Assets))
+			for i, asset := range p.Assets {
+				decimals[asset] = p.Decimals[i]
+			}
+			return pair, decimals, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("no pair %s configured on %s trading the given assets", address, chain.Name)
+}
+
+// toRaw converts a decimal amount of whole tokens to raw token units,
+// truncating digits beyond the token's decimals.
+func toRaw(amount string, decimals int64) (*big.Int, error) {
+	whole, ok := new(big.Rat).SetString(amount)
+	if !ok || whole.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid amount %q", amount)
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(decimals), nil)
+	whole.Mul(whole, new(big.Rat).SetInt(scale))
+	raw := new(big.Int).Quo(whole.Num(), whole.Denom())
+	if raw.Sign() == 0 {
+		return nil, fmt.Errorf("amount %q is below one token unit", amount)
+	}
+	return raw, nil
+}
+
+// toWhole converts raw token units to whole tokens.
+func toWhole(raw *big.Int, decimals int64) *big.Rat {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(decimals), nil)
+	return new(big.Rat).SetFrac(raw, scale)
+}
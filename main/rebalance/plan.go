@@ -0,0 +1,269 @@
+package rebalance
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+
+	"bb/types"
+)
+
+// Drift is how far an asset's share of the inventory is from its target.
+type Drift struct {
+	Asset    string
+	ValueUSD float64
+	Weight   float64 // current share of the targeted value
+	Target   float64
+}
+
+// Hop is one swap of a route.
+type Hop struct {
+	Pair     types.Pair
+	AssetIn  string
+	AssetOut string
+}
+
+func (h Hop) String() string {
+	return fmt.Sprintf("%s->%s on %s", h.AssetIn, h.AssetOut, h.Pair.DEX())
+}
+
+// Swap moves value from an over-weight asset to an under-weight one along the
+// cheapest route through the pairs.
+type Swap struct {
+	From      string
+	To        string
+	AmountIn  float64 // whole tokens of From
+	AmountOut float64 // quoted whole tokens of To
+	Route     []Hop
+	GasCost   float64 // estimated, in the native token
+	CostUSD   float64 // value lost to fees, price impact and gas
+}
+
+func (s Swap) String() string {
+	hops := make([]string, len(s.Route))
+	for i, hop := range s.Route {
+		hops[i] = hop.String()
+	}
+	return fmt.Sprintf("%f %s -> %f %s via [%s], cost $%.2f", s.AmountIn, s.From, s.AmountOut, s.To, strings.Join(hops, ", "), s.CostUSD)
+}
+
+// Plan is what a rebalance would do.
+type Plan struct {
+	TotalUSD float64
+	Drifts   []Drift
+	Breached bool // some weight drifted past the threshold
+	Swaps    []Swap
+}
+
+// Log writes the plan to a logger.
+func (p *Plan) Log(ctx context.Context, logger *slog.Logger) {
+	for _, d := range p.Drifts {
+		logger.InfoContext(ctx, "allocation", "asset", d.Asset, "weight", d.Weight, "target", d.Target, "valueUSD", d.ValueUSD, "totalUSD", p.TotalUSD)
+	}
+	if !p.Breached {
+		logger.InfoContext(ctx, "allocation within threshold, no rebalance needed")
+		return
+	}
+	if len(p.Swaps) == 0 {
+		logger.InfoContext(ctx, "allocation drifted, but no swap is routable or large enough")
+		return
+	}
+	for _, s := range p.Swaps {
+		logger.InfoContext(ctx, "rebalance swap planned", "swap", s.String())
+	}
+}
+
+// Plan compares the inventory against the targets and, if any weight has
+// drifted past the threshold, plans the swaps bringing every targeted asset
+// back to its weight. The largest surplus is matched with the largest deficit
+// first, each along its cheapest route.
+func (r *Rebalancer) Plan(ctx context.Context) (*Plan, error) {
+	prices := r.Prices()
+
+	var targetSum float64
+	for _, weight := range r.Targets {
+		targetSum += weight
+	}
+	if targetSum <= 0 {
+		return nil, fmt.Errorf("no target weights")
+	}
+
+	plan := &Plan{}
+	values := make(map[string]float64)
+	for asset := range r.Targets {
+		price, ok := prices[asset]
+		if !ok || price <= 0 {
+			return nil, fmt.Errorf("no price for %s", asset)
+		}
+		values[asset] = r.Inventory.Available(asset) * price
+		plan.TotalUSD += values[asset]
+	}
+	if plan.TotalUSD <= 0 {
+		return plan, nil
+	}
+
+	type delta struct {
+		asset string
+		usd   float64
+	}
+	var surplus, deficit []delta
+	for asset, weight := range r.Targets {
+		d := Drift{Asset: asset, ValueUSD: values[asset], Weight: values[asset] / plan.TotalUSD, Target: weight / targetSum}
+		plan.Drifts = append(plan.Drifts, d)
+		if math.Abs(d.Weight-d.Target) > r.Threshold {
+			plan.Breached = true
+		}
+		switch excess := d.ValueUSD - d.Target*plan.TotalUSD; {
+		case excess > 0:
+			surplus = append(surplus, delta{asset, excess})
+		case excess < 0:
+			deficit = append(deficit, delta{asset, -excess})
+		}
+	}
+	sort.Slice(plan.Drifts, func(i, j int) bool { return plan.Drifts[i].Asset < plan.Drifts[j].Asset })
+	if !plan.Breached {
+		return plan, nil
+	}
+
+	byValue := func(d []delta) func(i, j int) bool {
+		return func(i, j int) bool { return d[i].usd > d[j].usd }
+	}
+	sort.Slice(surplus, byValue(surplus))
+	sort.Slice(deficit, byValue(deficit))
+
+	gasUnits, gasPrice, err := r.Gas.Estimate(ctx, r.Client, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate gas: %v", err)
+	}
+	gasPerHop, _ := new(big.Float).Quo(new(big.Float).SetInt(new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(gasUnits))), big.NewFloat(1e18)).Float64()
+
+	for i, j := 0, 0; i < len(surplus) && j < len(deficit); {
+		usd := math.Min(surplus[i].usd, deficit[j].usd)
+		from, to := surplus[i].asset, deficit[j].asset
+		surplus[i].usd -= usd
+		deficit[j].usd -= usd
+		if surplus[i].usd <= 0 {
+			i++
+		}
+		if deficit[j].usd <= 0 {
+			j++
+		}
+		if usd < r.MinTradeUSD {
+			continue
+		}
+
+		swap, err := r.route(from, to, usd/prices[from], prices, gasPerHop)
+		if err != nil {
+			r.Log.WarnContext(ctx, "rebalance swap not routable", "from", from, "to", to, "err", err)
+			continue
+		}
+		plan.Swaps = append(plan.Swaps, *swap)
+	}
+	return plan, nil
+}
+
+// path is a partial route and what it yields, in raw units.
+type path struct {
+	hops   []Hop
+	amount *big.Int
+}
+
+func (p path) visits(asset string) bool {
+	for _, hop := range p.hops {
+		if hop.AssetIn == asset {
+			return true
+		}
+	}
+	return false
+}
+
+// route finds the route from one asset to another that leaves the most value
+// after gas. best holds, per asset, the largest raw amount reachable with up
+// to k hops; every route length up to MaxHops is priced and the cheapest
+// kept.
+func (r *Rebalancer) route(from, to string, amountIn float64, prices map[string]float64, gasPerHop float64) (*Swap, error) {
+	tokenIn, ok := r.Inventory.Token(from)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a tracked token", from)
+	}
+	tokenOut, ok := r.Inventory.Token(to)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a tracked token", to)
+	}
+	raw, _ := new(big.Float).Mul(big.NewFloat(amountIn), new(big.Float).SetInt(pow10(tokenIn.Decimals))).Int(nil)
+
+	pairs := r.Pairs()
+	best := map[string]path{from: {amount: raw}}
+	var chosen *path
+	chosenValue := math.Inf(-1)
+	for k := 1; k <= r.MaxHops; k++ {
+		next := make(map[string]path, len(best))
+		for asset, p := range best {
+			next[asset] = p
+		}
+		for asset, p := range best {
+			if asset == to {
+				continue
+			}
+			for _, pair := range pairs {
+				var out string
+				switch asset {
+				case pair.Asset1():
+					out = pair.Asset2()
+				case pair.Asset2():
+					out = pair.Asset1()
+				default:
+					continue
+				}
+				if out == from || p.visits(out) {
+					continue
+				}
+				amount, err := pair.Quote(asset, p.amount)
+				if err != nil || amount.Sign() <= 0 {
+					continue
+				}
+				if current, ok := next[out]; ok && current.amount.Cmp(amount) >= 0 {
+					continue
+				}
+				hops := append(append([]Hop(nil), p.hops...), Hop{Pair: pair, AssetIn: asset, AssetOut: out})
+				next[out] = path{hops: hops, amount: amount}
+			}
+		}
+		best = next
+
+		if p, ok := best[to]; ok && len(p.hops) == k {
+			value := toWhole(p.amount, tokenOut.Decimals)*prices[to] - float64(k)*gasPerHop*prices[r.Native]
+			if value > chosenValue {
+				chosen, chosenValue = &p, value
+			}
+		}
+	}
+	if chosen == nil {
+		return nil, fmt.Errorf("no route within %d hops", r.MaxHops)
+	}
+
+	amountOut := toWhole(chosen.amount, tokenOut.Decimals)
+	gasCost := float64(len(chosen.hops)) * gasPerHop
+	return &Swap{
+		From:      from,
+		To:        to,
+		AmountIn:  amountIn,
+		AmountOut: amountOut,
+		Route:     chosen.hops,
+		GasCost:   gasCost,
+		CostUSD:   amountIn*prices[from] - chosenValue,
+	}, nil
+}
+
+func pow10(decimals int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(decimals), nil)
+}
+
+func toWhole(raw *big.Int, decimals int64) float64 {
+	value, _ := new(big.Float).Quo(new(big.Float).SetInt(raw), new(big.Float).SetInt(pow10(decimals))).Float64()
+	return value
+}
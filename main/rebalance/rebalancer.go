@@ -0,0 +1,287 @@
+package rebalance
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"bb/executor"
+	"bb/inventory"
+	"bb/logging"
+	"bb/risk"
+	"bb/strategy"
+	"bb/txmanager"
+	"bb/types"
+)
+
+// Rebalancer swaps the inventory back toward target weights as arbitrage
+// drifts it into whatever assets the profitable cycles end in. Its swaps go
+// through the same risk engine and transaction manager as the trades.
+type Rebalancer struct {
+	Targets     map[string]float64 // weight by asset
+	Threshold   float64            // drift of any weight that triggers a rebalance
+	MinTradeUSD float64
+	MaxHops     int
+	Slippage    float64       // fraction of a route's quote it may fall short by
+	Interval    time.Duration // between checks
+	DryRun      bool          // log plans without trading
+
+	Paused    func() bool // if set, plans are only logged while it reports true
+	Pairs     func() []types.Pair
+	Inventory *inventory.Inventory
+	Executor  *executor.Executor // carries out each swap's route in one transaction
+	Risk      *risk.Engine
+	Txs       *txmanager.Manager
+	Prices    func() map[string]float64 // USD price by asset
+	Gas       strategy.GasModel
+	Client    strategy.ChainReader
+	Native    string // asset gas is paid in
+	Log       *slog.Logger
+
+	// Detach, if set, returns the context a plan is carried out on, which
+	// outlives the one Run was given, and the func that ends it once the plan
+	// is done. Run then carries out plans in the background, one at a time,
+	// and returns without waiting for the swap in flight.
+	Detach func(ctx context.Context) (context.Context, func())
+}
+
+// Run checks the allocation every Interval until ctx is cancelled, and
+// carries out the plan unless DryRun is set. Each check has its own
+// correlation ID. No check starts while a plan is still being carried out.
+func (r *Rebalancer) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+	var running chan struct{} // closed once the latest plan is done
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if running != nil {
+			select {
+			case <-running:
+			default:
+				continue
+			}
+		}
+
+		ctx := logging.WithID(ctx, logging.NewID())
+		plan, err := r.Plan(ctx)
+		if err != nil {
+			r.Log.WarnContext(ctx, "rebalance check failed", "err", err)
+			continue
+		}
+		if !plan.Breached {
+			continue
+		}
+		plan.Log(ctx, r.Log)
+		if r.DryRun {
+			r.Log.InfoContext(ctx, "rebalance dry run, not trading")
+			continue
+		}
+		if r.Paused != nil && r.Paused() {
+			r.Log.InfoContext(ctx, "execution paused, not rebalancing")
+			continue
+		}
+		if r.Detach == nil {
+			r.Execute(ctx, plan)
+			continue
+		}
+		follow, done := r.Detach(ctx)
+		running = make(chan struct{})
+		go func(running chan struct{}) {
+			defer close(running)
+			defer done()
+			r.execute(ctx, follow, plan)
+		}(running)
+	}
+}
+
+// Execute carries out the swaps of a plan in order, stopping at the first
+// that the risk engine refuses or that does not complete.
+func (r *Rebalancer) Execute(ctx context.Context, plan *Plan) {
+	r.execute(ctx, ctx, plan)
+}
+
+// execute carries out a plan, sending no new swap once ctx ends but following
+// each swap sent on follow.
+func (r *Rebalancer) execute(ctx, follow context.Context, plan *Plan) {
+	for _, swap := range plan.Swaps {
+		if ctx.Err() != nil {
+			r.Log.InfoContext(follow, "rebalance interrupted", "remaining", swap.String())
+			return
+		}
+		if err := r.swap(follow, swap); err != nil {
+			r.Log.WarnContext(ctx, "rebalance swap failed", "from", swap.From, "to", swap.To, "err", err)
+			return
+		}
+	}
+}
+
+// swap sends a swap's whole route to the executor as one transaction, which
+// reverts unless the route pays out at least its quote less Slippage. The
+// executor is approved to take the input first if it may not yet.
+func (r *Rebalancer) swap(ctx context.Context, swap Swap) error {
+	if r.Executor == nil {
+		return fmt.Errorf("no executor to swap through")
+	}
+	prices := r.Prices()
+	for _, priced := range []string{swap.From, r.Native} {
+		if prices[priced] <= 0 {
+			return fmt.Errorf("rejected: no price for %s", priced)
+		}
+	}
+	position, err := r.Risk.Open(risk.Trade{
+		Asset:       swap.From,
+		NotionalUSD: swap.AmountIn * prices[swap.From],
+		GasCost:     swap.GasCost,
+	})
+	if err != nil {
+		return fmt.Errorf("rejected: %v", err)
+	}
+	release := r.Inventory.Reserve(swap.From, swap.AmountIn)
+	defer release()
+
+	token, _ := r.Inventory.Token(swap.From)
+	amount, _ := new(big.Float).Mul(big.NewFloat(swap.AmountIn), new(big.Float).SetInt(pow10(token.Decimals))).Int(nil)
+	route, err := r.executorRoute(swap, amount)
+	if err != nil {
+		r.Risk.Close(position, nil)
+		return err
+	}
+
+	gasSpent := new(big.Int)
+	sent := false
+	outcome := &risk.Outcome{}
+	defer func() {
+		if !sent {
+			r.Risk.Close(position, nil)
+			return
+		}
+		outcome.GasCost, _ = new(big.Float).Quo(new(big.Float).SetInt(gasSpent), big.NewFloat(1e18)).Float64()
+		gasUSD := outcome.GasCost * prices[r.Native]
+		if outcome.Reverted {
+			outcome.PnLUSD = -gasUSD
+		}
+		r.Risk.Close(position, outcome)
+	}()
+
+	// wait follows a transaction sent for the swap to its end
+	wait := func(tx *txmanager.Tx) error {
+		sent = true
+		status, err := tx.Wait(ctx)
+		if err != nil {
+			return err
+		}
+		r.Inventory.ApplyReceipt(tx.Current(), tx.Receipt())
+		gasSpent.Add(gasSpent, tx.GasCost())
+		if status != txmanager.Mined {
+			outcome.Reverted = true
+			return fmt.Errorf("%s %s", tx.Label, status)
+		}
+		return nil
+	}
+
+	approval, err := r.approve(ctx, swap.From, amount)
+	if err != nil {
+		return err
+	}
+	if approval != nil {
+		if err := wait(approval); err != nil {
+			return err
+		}
+	}
+
+	tx, err := r.Txs.Transact(ctx, fmt.Sprintf("rebalance %s", swap), func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return r.Executor.Execute(opts, route)
+	})
+	if err != nil {
+		return err
+	}
+	if err := wait(tx); err != nil {
+		return err
+	}
+	received := tx.Outputs()[route.TokenOut]
+	if received == nil || received.Sign() <= 0 {
+		outcome.Reverted = true
+		return fmt.Errorf("%s paid out nothing", swap)
+	}
+
+	token, _ = r.Inventory.Token(swap.To)
+	amountOut := toWhole(received, token.Decimals)
+	gasCost, _ := new(big.Float).Quo(new(big.Float).SetInt(gasSpent), big.NewFloat(1e18)).Float64()
+	outcome.PnLUSD = amountOut*prices[swap.To] - swap.AmountIn*prices[swap.From] - gasCost*prices[r.Native]
+	r.Log.InfoContext(ctx, "rebalanced", "from", swap.From, "amountIn", swap.AmountIn, "to", swap.To, "amountOut", amountOut)
+	return nil
+}
+
+// executorRoute builds the executor route of a swap of amount raw units. Each hop
+// sells whatever the hop before paid out; only the last has a minimum, the
+// chained quote of the hops less Slippage, which bounds the whole route.
+func (r *Rebalancer) executorRoute(swap Swap, amount *big.Int) (executor.Route, error) {
+	if len(swap.Route) == 0 {
+		return executor.Route{}, fmt.Errorf("%s has no route", swap)
+	}
+	in, _ := r.Inventory.Token(swap.From)
+	route := executor.Route{TokenIn: in.Address, AmountIn: amount}
+	quote := amount
+	for i, hop := range swap.Route {
+		tokenIn, ok := r.Inventory.Token(hop.AssetIn)
+		if !ok {
+			return executor.Route{}, fmt.Errorf("%s is not a tracked token", hop.AssetIn)
+		}
+		tokenOut, ok := r.Inventory.Token(hop.AssetOut)
+		if !ok {
+			return executor.Route{}, fmt.Errorf("%s is not a tracked token", hop.AssetOut)
+		}
+		var err error
+		if quote, err = hop.Pair.Quote(hop.AssetIn, quote); err != nil {
+			return executor.Route{}, fmt.Errorf("failed to quote %s: %v", hop, err)
+		}
+
+		var minOut *big.Int
+		if i == len(swap.Route)-1 {
+			minOut = lessSlippage(quote, r.Slippage)
+			route.TokenOut, route.MinAmountOut = tokenOut.Address, minOut
+		} else {
+			route.Sweep = append(route.Sweep, tokenOut.Address)
+		}
+		calls, err := hop.Pair.SwapCalls(r.Executor.Address, tokenIn.Address, hop.AssetIn, minOut)
+		if err != nil {
+			return executor.Route{}, err
+		}
+		route.Calls = append(route.Calls, calls...)
+	}
+	route.Sweep = append(route.Sweep, in.Address)
+	return route, nil
+}
+
+// approve lets the executor take amount of asset from the account if it may
+// not yet, returning the approval or nil. The executor is approved for all
+// of it at once: only the account can make it spend the allowance.
+func (r *Rebalancer) approve(ctx context.Context, asset string, amount *big.Int) (*txmanager.Tx, error) {
+	allowance, err := r.Inventory.Allowance(ctx, asset, r.Executor.Address)
+	if err != nil {
+		return nil, err
+	}
+	if allowance.Cmp(amount) >= 0 {
+		return nil, nil
+	}
+	return r.Txs.Transact(ctx, fmt.Sprintf("approve %s for the executor", asset), func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return r.Inventory.Approve(opts, asset, r.Executor.Address, abi.MaxUint256)
+	})
+}
+
+// lessSlippage returns amount less a fraction of it, rounded down.
+func lessSlippage(amount *big.Int, slippage float64) *big.Int {
+	kept := big.NewInt(int64(math.Round((1 - slippage) * 1e6)))
+	return kept.Quo(kept.Mul(kept, amount), big.NewInt(1e6))
+}
@@ -0,0 +1,205 @@
+package rebalance
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"bb/contracts/uniswapv2"
+	"bb/inventory"
+	"bb/risk"
+	"bb/testutil"
+	"bb/txmanager"
+	"bb/types"
+)
+
+// simulatedRebalancer returns a rebalancer of an account holding 10 ETH on a
+// simulated chain that mines a block every 20ms until ctx ends, with a route
+// from ETH into USDC through DAI.
+func simulatedRebalancer(ctx context.Context, t *testing.T) (*Rebalancer, *testutil.Chain, map[string]common.Address, []Hop) {
+	chain := testutil.NewChain(t)
+	factory := chain.DeployFactory(t)
+	decimals := map[string]int64{"ETH": 18, "DAI": 18, "USDC": 6}
+	tokens := make(map[string]common.Address)
+	var held []inventory.Token
+	for _, asset := range []string{"ETH", "DAI", "USDC"} {
+		tokens[asset] = chain.DeployToken(t, asset, uint8(decimals[asset]))
+		held = append(held, inventory.Token{Asset: asset, Address: tokens[asset], Decimals: decimals[asset]})
+	}
+	names := map[common.Address]string{tokens["ETH"]: "ETH", tokens["DAI"]: "DAI", tokens["USDC"]: "USDC"}
+	pair := func(a, b string, reserveA, reserveB float64) *uniswapv2pair.Instance {
+		token0, token1 := testutil.SortTokens(tokens[a], tokens[b])
+		reserves := map[common.Address]*big.Int{tokens[a]: testutil.Amount(reserveA, decimals[a]), tokens[b]: testutil.Amount(reserveB, decimals[b])}
+		address := chain.DeployPair(t, testutil.PairState{Factory: factory, Token0: token0, Token1: token1, Reserve0: reserves[token0], Reserve1: reserves[token1]})
+		fork := uniswapv2pair.Fork{Name: "uni", Factory: factory, InitCodeHash: testutil.PairInitCodeHash(), FeePips: 3000}
+		p, err := uniswapv2pair.NewInstance(address.Hex(), chain.Client, fork, names[token0], names[token1], decimals[names[token0]], decimals[names[token1]])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	route := []Hop{
+		{Pair: pair("ETH", "DAI", 100, 200000), AssetIn: "ETH", AssetOut: "DAI"},
+		{Pair: pair("DAI", "USDC", 1000000, 1000000), AssetIn: "DAI", AssetOut: "USDC"},
+	}
+	chain.Mint(t, tokens["ETH"], chain.Auth.From, testutil.Amount(10, 18))
+	exec := chain.DeployExecutor(t)
+
+	go func() {
+		ticker := time.NewTicker(20 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				chain.Backend.Commit()
+			}
+		}
+	}()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	inv, err := inventory.New(chain.Client, chain.Auth.From, "ETH", held, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := inv.Load(ctx); err != nil {
+		t.Fatal(err)
+	}
+	engine, err := risk.NewEngine(risk.Limits{MaxNotionalUSD: 1e6, MaxDailyLossUSD: 1e6}, "", logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := txmanager.NewManager(chain.Client, chain.Auth, logger)
+	manager.Poll = 10 * time.Millisecond
+	go manager.Run(ctx)
+
+	r := &Rebalancer{
+		Slippage:  0.005,
+		Pairs:     func() []types.Pair { return []types.Pair{route[0].Pair, route[1].Pair} },
+		Inventory: inv,
+		Executor:  exec,
+		Risk:      engine,
+		Txs:       manager,
+		Prices:    func() map[string]float64 { return map[string]float64{"ETH": 2000, "DAI": 1, "USDC": 1} },
+		Client:    chain.Client,
+		Native:    "ETH",
+		Log:       logger,
+	}
+	return r, chain, tokens, route
+}
+
+// TestSwapThroughExecutor rebalances ETH into USDC through DAI on a simulated
+// chain. The route goes out as one executor transaction that pays out the
+// chained quote, and one that cannot reach its minimum never goes out.
+func TestSwapThroughExecutor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r, chain, tokens, route := simulatedRebalancer(ctx, t)
+	inv, exec := r.Inventory, r.Executor
+	swap := Swap{From: "ETH", To: "USDC", AmountIn: 1, Route: route}
+	amountIn := testutil.Amount(1, 18)
+	quote := amountIn
+	for _, hop := range route {
+		var err error
+		if quote, err = hop.Pair.Quote(hop.AssetIn, quote); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Without a price for the native token the swap's gas, and so its PnL,
+	// cannot be valued
+	prices := r.Prices
+	r.Prices = func() map[string]float64 { return map[string]float64{"DAI": 1, "USDC": 1} }
+	if err := r.swap(ctx, swap); err == nil {
+		t.Fatal("swap without an ETH price succeeded")
+	}
+	r.Prices = prices
+
+	// A route that must pay out more than its quote is refused before it is
+	// sent; the approval went out and stays
+	r.Slippage = -0.01
+	if err := r.swap(ctx, swap); err == nil {
+		t.Fatal("swap above its quote succeeded")
+	}
+	if got := chain.BalanceOf(t, tokens["ETH"], chain.Auth.From); got.Cmp(testutil.Amount(10, 18)) != 0 {
+		t.Fatalf("%s ETH left after a refused swap", got)
+	}
+	if allowance, err := inv.Allowance(ctx, "ETH", exec.Address); err != nil || allowance.Cmp(amountIn) < 0 {
+		t.Fatalf("executor allowed %s %v", allowance, err)
+	}
+
+	r.Slippage = 0.005
+	before, err := chain.Client.NonceAt(ctx, chain.Auth.From, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.swap(ctx, swap); err != nil {
+		t.Fatal(err)
+	}
+	if got := chain.BalanceOf(t, tokens["USDC"], chain.Auth.From); got.Cmp(quote) != 0 {
+		t.Errorf("received %s USDC, quoted %s", got, quote)
+	}
+	if got := chain.BalanceOf(t, tokens["ETH"], chain.Auth.From); got.Cmp(testutil.Amount(9, 18)) != 0 {
+		t.Errorf("%s ETH left, want 9", got)
+	}
+	for asset, token := range tokens {
+		if got := chain.BalanceOf(t, token, exec.Address); got.Sign() != 0 {
+			t.Errorf("executor holds %s %s", got, asset)
+		}
+	}
+	if after, err := chain.Client.NonceAt(ctx, chain.Auth.From, nil); err != nil || after != before+1 {
+		t.Errorf("swap sent %d transactions %v, want 1", after-before, err)
+	}
+}
+
+// TestRunDetached ends a rebalancer's Run while its swap is in flight. Run
+// returns at once, and the swap is still followed to its end on the detached
+// context, which is only released after it.
+func TestRunDetached(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r, chain, tokens, _ := simulatedRebalancer(ctx, t)
+	r.Targets = map[string]float64{"ETH": 1, "USDC": 1}
+	r.Threshold = 0.1
+	r.MaxHops = 2
+	r.Interval = 10 * time.Millisecond
+	released := make(chan struct{})
+	r.Detach = func(ctx context.Context) (context.Context, func()) {
+		return context.WithoutCancel(ctx), func() { close(released) }
+	}
+
+	runCtx, stop := context.WithCancel(ctx)
+	ran := make(chan struct{})
+	go func() {
+		defer close(ran)
+		r.Run(runCtx)
+	}()
+	deadline := time.After(10 * time.Second)
+	for len(r.Txs.Pending()) == 0 {
+		select {
+		case <-deadline:
+			t.Fatal("no rebalance sent")
+		case <-time.After(time.Millisecond):
+		}
+	}
+	stop()
+	select {
+	case <-ran:
+	case <-deadline:
+		t.Fatal("Run waited for the swap in flight")
+	}
+	select {
+	case <-released:
+	case <-deadline:
+		t.Fatal("rebalance never released")
+	}
+	if got := chain.BalanceOf(t, tokens["USDC"], chain.Auth.From); got.Sign() <= 0 {
+		t.Errorf("received %s USDC after Run ended", got)
+	}
+}
@@ -0,0 +1,314 @@
+package risk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// Limits bound what the engine lets through. MaxNotionalUSD and
+// MaxDailyLossUSD are required; the other limits are not enforced at zero.
+type Limits struct {
+	MaxNotionalUSD        float64            // value sent into a single trade
+	MaxDailyLossUSD       float64            // realized loss, gas included, per UTC day
+	MaxGasPerHour         float64            // gas spent in the native token over the last hour
+	MaxConsecutiveReverts int                // reverted trades in a row
+	MaxExposureUSD        map[string]float64 // value of an asset in open trades, by asset
+}
+
+// Validate checks that the required limits are set.
+func (l Limits) Validate() error {
+	if l.MaxNotionalUSD <= 0 || l.MaxDailyLossUSD <= 0 {
+		return errors.New("maxNotionalUSD and maxDailyLossUSD must be set to trade")
+	}
+	return nil
+}
+
+// Trade is a proposed trade, valued by the caller.
+type Trade struct {
+	Asset       string  // asset the trade starts from
+	NotionalUSD float64 // value of the input
+	GasCost     float64 // estimated gas in the native token
+}
+
+// Outcome is the final result of a trade.
+type Outcome struct {
+	Reverted bool
+	PnLUSD   float64 // realized profit, negative for a loss, gas included
+	GasCost  float64 // gas spent in the native token
+}
+
+// ErrHalted is returned for every trade while the kill switch is tripped.
+var ErrHalted = errors.New("trading halted by kill switch")
+
+// Engine sits between the strategy and execution. Every trade must be opened
+// with Open before it is sent and closed with its outcome. Breaching the
+// daily loss or the consecutive revert limit trips the kill switch, which
+// halts all trading until an operator resets it. The tripped state is kept
+// in StateFile so that restarting does not reset it; an operator resetting
+// the file, e.g. with ResetStateFile, also resets a running engine.
+type Engine struct {
+	Limits    Limits
+	StateFile string
+	Log       *slog.Logger
+
+	// OnTrip, if set, is called with the reason when the kill switch trips.
+	// It is called with the engine locked and must not call back into it.
+	OnTrip func(reason string)
+
+	mu    sync.Mutex
+	state state
+	gas   []spend // gas spent in the last hour
+	open  map[*Position]bool
+	now   func() time.Time
+}
+
+// state is what survives restarts.
+type state struct {
+	Halted   bool      `json:"halted"`
+	Reason   string    `json:"reason,omitempty"`
+	HaltedAt time.Time `json:"haltedAt,omitempty"`
+	Day      string    `json:"day"`
+	DailyPnL float64   `json:"dailyPnLUSD"`
+	Reverts  int       `json:"consecutiveReverts"`
+}
+
+type spend struct {
+	at     time.Time
+	amount float64
+}
+
+// Position is an open trade.
+type Position struct {
+	Trade  Trade
+	Opened time.Time
+}
+
+// NewEngine creates an engine, restoring the kill switch and daily figures
+// from stateFile if it exists. An empty stateFile keeps the state in memory.
+func NewEngine(limits Limits, stateFile string, logger *slog.Logger) (*Engine, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	e := &Engine{
+		Limits:    limits,
+		StateFile: stateFile,
+		Log:       logger,
+		open:      make(map[*Position]bool),
+		now:       time.Now,
+	}
+	if stateFile != "" {
+		data, err := os.ReadFile(stateFile)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+		case err != nil:
+			return nil, fmt.Errorf("failed to read risk state: %v", err)
+		default:
+			if err := json.Unmarshal(data, &e.state); err != nil {
+				return nil, fmt.Errorf("failed to parse risk state %s: %v", stateFile, err)
+			}
+		}
+	}
+	if e.state.Halted {
+		logger.Warn("kill switch is tripped", "since", e.state.HaltedAt, "reason", e.state.Reason)
+	}
+	return e, nil
+}
+
+// Open checks a trade against the limits and, if it passes, counts it as
+// open until Close. Without the required limits no trade passes.
+func (e *Engine) Open(t Trade) (*Position, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.state.Halted && !e.resetOnDisk() {
+		return nil, ErrHalted
+	}
+	e.rollDay()
+
+	l := e.Limits
+	if err := l.Validate(); err != nil {
+		return nil, err
+	}
+	if t.NotionalUSD > l.MaxNotionalUSD {
+		return nil, fmt.Errorf("notional $%.2f exceeds $%.2f per trade", t.NotionalUSD, l.MaxNotionalUSD)
+	}
+	if -e.state.DailyPnL >= l.MaxDailyLossUSD {
+		return nil, fmt.Errorf("daily loss $%.2f reached the $%.2f limit", -e.state.DailyPnL, l.MaxDailyLossUSD)
+	}
+	if l.MaxGasPerHour > 0 {
+		if spent := e.gasLastHour(); spent+t.GasCost > l.MaxGasPerHour {
+			return nil, fmt.Errorf("gas %.6f spent in the last hour, %.6f more exceeds %.6f", spent, t.GasCost, l.MaxGasPerHour)
+		}
+	}
+	if max, ok := l.MaxExposureUSD[t.Asset]; ok {
+		if exposure := e.exposure(t.Asset); exposure+t.NotionalUSD > max {
+			return nil, fmt.Errorf("%s exposure $%.2f plus $%.2f exceeds $%.2f", t.Asset, exposure, t.NotionalUSD, max)
+		}
+	}
+
+	p := &Position{Trade: t, Opened: e.now()}
+	e.open[p] = true
+	return p, nil
+}
+
+// Close records the outcome of an open trade. A nil outcome means the trade
+// was never sent.
+func (e *Engine) Close(p *Position, outcome *Outcome) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	delete(e.open, p)
+	if outcome == nil {
+		return
+	}
+	e.rollDay()
+
+	e.state.DailyPnL += outcome.PnLUSD
+	if outcome.GasCost > 0 {
+		e.gas = append(e.gas, spend{at: e.now(), amount: outcome.GasCost})
+	}
+	if outcome.Reverted {
+		e.state.Reverts++
+	} else {
+		e.state.Reverts = 0
+	}
+
+	l := e.Limits
+	switch {
+	case l.MaxDailyLossUSD > 0 && -e.state.DailyPnL >= l.MaxDailyLossUSD:
+		e.trip(fmt.Sprintf("daily loss $%.2f reached the $%.2f limit", -e.state.DailyPnL, l.MaxDailyLossUSD))
+	case l.MaxConsecutiveReverts > 0 && e.state.Reverts >= l.MaxConsecutiveReverts:
+		e.trip(fmt.Sprintf("%d consecutive reverts", e.state.Reverts))
+	}
+	e.save()
+}
+
+// Halt trips the kill switch by hand.
+func (e *Engine) Halt(reason string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.trip(reason)
+	e.save()
+}
+
+// Reset clears the kill switch and the consecutive revert count. It is meant
+// to be called only on an operator's command.
+func (e *Engine) Reset(operator string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.state.Halted {
+		e.Log.Warn("kill switch reset", "operator", operator, "reason", e.state.Reason)
+	}
+	e.state.Halted = false
+	e.state.Reason = ""
+	e.state.HaltedAt = time.Time{}
+	e.state.Reverts = 0
+	e.save()
+}
+
+// Status reports whether trading is halted, and why.
+func (e *Engine) Status() (bool, string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.state.Halted, e.state.Reason
+}
+
+// resetOnDisk reports whether an operator has reset the kill switch in the
+// state file, and clears it in memory if so. e.mu must be held.
+func (e *Engine) resetOnDisk() bool {
+	if e.StateFile == "" {
+		return false
+	}
+	data, err := os.ReadFile(e.StateFile)
+	if err != nil {
+		return false
+	}
+	var onDisk state
+	if err := json.Unmarshal(data, &onDisk); err != nil || onDisk.Halted {
+		return false
+	}
+	e.Log.Warn("kill switch reset by operator", "reason", e.state.Reason)
+	e.state = onDisk
+	return true
+}
+
+// trip halts trading. e.mu must be held.
+func (e *Engine) trip(reason string) {
+	if e.state.Halted {
+		return
+	}
+	e.state.Halted = true
+	e.state.Reason = reason
+	e.state.HaltedAt = e.now()
+	e.Log.Error("KILL SWITCH TRIPPED, trading halted until an operator resets it", "reason", reason)
+	if e.OnTrip != nil {
+		e.OnTrip(reason)
+	}
+}
+
+// rollDay starts a new daily loss count at UTC midnight. e.mu must be held.
+func (e *Engine) rollDay() {
+	day := e.now().UTC().Format(time.DateOnly)
+	if e.state.Day != day {
+		e.state.Day = day
+		e.state.DailyPnL = 0
+	}
+}
+
+// gasLastHour drops spends older than an hour and sums the rest. e.mu must
+// be held.
+func (e *Engine) gasLastHour() float64 {
+	cutoff := e.now().Add(-time.Hour)
+	kept := e.gas[:0]
+	var total float64
+	for _, s := range e.gas {
+		if s.at.After(cutoff) {
+			kept = append(kept, s)
+			total += s.amount
+		}
+	}
+	e.gas = kept
+	return total
+}
+
+// exposure sums the open notional in an asset. e.mu must be held.
+func (e *Engine) exposure(asset string) float64 {
+	var total float64
+	for p := range e.open {
+		if p.Trade.Asset == asset {
+			total += p.Trade.NotionalUSD
+		}
+	}
+	return total
+}
+
+// save writes the state file. e.mu must be held.
+func (e *Engine) save() {
+	if e.StateFile == "" {
+		return
+	}
+	data, err := json.MarshalIndent(e.state, "", "  ")
+	if err != nil {
+		e.Log.Error("failed to encode risk state", "err", err)
+		return
+	}
+	if err := os.WriteFile(e.StateFile, data, 0o644); err != nil {
+		e.Log.Error("failed to write risk state", "err", err)
+	}
+}
+
+// ResetStateFile clears a tripped kill switch in a state file, for an
+// operator resetting it while the bot is not running.
+func ResetStateFile(stateFile, operator string) error {
+	e, err := NewEngine(Limits{}, stateFile, nil)
+	if err != nil {
+		return err
+	}
+	e.Reset(operator)
+	return nil
+}
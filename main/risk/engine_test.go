@@ -0,0 +1,178 @@
+package risk
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var limits = Limits{
+	MaxNotionalUSD:        1000,
+	MaxDailyLossUSD:       100,
+	MaxConsecutiveReverts: 3,
+	MaxExposureUSD:        map[string]float64{"ETH": 1500},
+}
+
+// clock is a settable time for an engine.
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time { return c.t }
+
+func newTestEngine(t *testing.T, limits Limits, stateFile string, c *clock) *Engine {
+	t.Helper()
+	e, err := NewEngine(limits, stateFile, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.now = c.now
+	return e
+}
+
+// trade opens a trade of a given value and closes it with its PnL.
+func trade(t *testing.T, e *Engine, notional, pnl float64, reverted bool) {
+	t.Helper()
+	p, err := e.Open(Trade{Asset: "USDC", NotionalUSD: notional})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.Close(p, &Outcome{PnLUSD: pnl, Reverted: reverted})
+}
+
+func TestOpenLimits(t *testing.T) {
+	c := &clock{time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	for _, missing := range []Limits{{}, {MaxNotionalUSD: 1000}, {MaxDailyLossUSD: 100}} {
+		e := newTestEngine(t, missing, "", c)
+		if _, err := e.Open(Trade{Asset: "USDC", NotionalUSD: 1}); err == nil {
+			t.Errorf("limits %+v let a trade through", missing)
+		}
+	}
+
+	e := newTestEngine(t, limits, "", c)
+	if _, err := e.Open(Trade{Asset: "USDC", NotionalUSD: 1001}); err == nil {
+		t.Error("trade over the notional limit let through")
+	}
+	first, err := e.Open(Trade{Asset: "ETH", NotionalUSD: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Open(Trade{Asset: "ETH", NotionalUSD: 600}); err == nil {
+		t.Error("trade over the ETH exposure limit let through")
+	}
+	e.Close(first, nil)
+	if _, err := e.Open(Trade{Asset: "ETH", NotionalUSD: 600}); err != nil {
+		t.Errorf("exposure not released: %v", err)
+	}
+}
+
+func TestTripAndReset(t *testing.T) {
+	c := &clock{time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	tests := []struct {
+		name   string
+		trades func(t *testing.T, e *Engine)
+	}{
+		{name: "daily loss", trades: func(t *testing.T, e *Engine) {
+			trade(t, e, 500, -60, false)
+			trade(t, e, 500, -40, false)
+		}},
+		{name: "consecutive reverts", trades: func(t *testing.T, e *Engine) {
+			trade(t, e, 500, -1, true)
+			trade(t, e, 500, -1, true)
+			trade(t, e, 500, 5, false)
+			trade(t, e, 500, -1, true)
+			trade(t, e, 500, -1, true)
+			if halted, _ := e.Status(); halted {
+				t.Fatal("tripped by reverts that were not in a row")
+			}
+			trade(t, e, 500, -1, true)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEngine(t, limits, "", c)
+			var tripped []string
+			e.OnTrip = func(reason string) { tripped = append(tripped, reason) }
+
+			tt.trades(t, e)
+			halted, reason := e.Status()
+			if !halted || len(tripped) != 1 || tripped[0] != reason {
+				t.Fatalf("halted %v (%q), tripped %q", halted, reason, tripped)
+			}
+			if _, err := e.Open(Trade{Asset: "USDC", NotionalUSD: 1}); !errors.Is(err, ErrHalted) {
+				t.Fatalf("open while halted: %v", err)
+			}
+
+			e.Reset("test")
+			if halted, _ := e.Status(); halted {
+				t.Fatal("still halted after reset")
+			}
+			if tt.name == "daily loss" {
+				// The day's loss stands until the day ends
+				if _, err := e.Open(Trade{Asset: "USDC", NotionalUSD: 1}); err == nil || errors.Is(err, ErrHalted) {
+					t.Fatalf("open after reset, daily loss reached: %v", err)
+				}
+				return
+			}
+			trade(t, e, 500, 1, false)
+		})
+	}
+}
+
+func TestRestartFromStateFile(t *testing.T) {
+	c := &clock{time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	stateFile := filepath.Join(t.TempDir(), "risk.json")
+	running := newTestEngine(t, limits, stateFile, c)
+	running.Halt("by hand")
+
+	restarted := newTestEngine(t, limits, stateFile, c)
+	if halted, reason := restarted.Status(); !halted || reason != "by hand" {
+		t.Fatalf("restarted halted %v (%q)", halted, reason)
+	}
+	if _, err := restarted.Open(Trade{Asset: "USDC", NotionalUSD: 1}); !errors.Is(err, ErrHalted) {
+		t.Fatalf("open after restart: %v", err)
+	}
+
+	// An operator resets the file while both run
+	if err := ResetStateFile(stateFile, "test"); err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range []*Engine{running, restarted} {
+		if _, err := e.Open(Trade{Asset: "USDC", NotionalUSD: 1}); err != nil {
+			t.Fatalf("open after the file was reset: %v", err)
+		}
+	}
+
+	// The day's figures survive too
+	trade(t, running, 500, -60, false)
+	if got := newTestEngine(t, limits, stateFile, c).state.DailyPnL; got != -60 {
+		t.Fatalf("restored daily PnL %g, want -60", got)
+	}
+}
+
+func TestRollDay(t *testing.T) {
+	c := &clock{time.Date(2026, 1, 1, 23, 0, 0, 0, time.UTC)}
+	e := newTestEngine(t, limits, "", c)
+	trade(t, e, 500, -99, false)
+	if _, err := e.Open(Trade{Asset: "USDC", NotionalUSD: 1}); err != nil {
+		t.Fatalf("open below the daily loss: %v", err)
+	}
+
+	// A loss the next UTC day counts from zero
+	c.t = c.t.Add(2 * time.Hour)
+	trade(t, e, 500, -99, false)
+	if halted, _ := e.Status(); halted {
+		t.Fatal("yesterday's loss counted today")
+	}
+	if e.state.Day != "2026-01-02" || e.state.DailyPnL != -99 {
+		t.Fatalf("day %s PnL %g", e.state.Day, e.state.DailyPnL)
+	}
+
+	// A tripped kill switch stays tripped into the next day
+	trade(t, e, 500, -1, false)
+	c.t = c.t.Add(24 * time.Hour)
+	if _, err := e.Open(Trade{Asset: "USDC", NotionalUSD: 1}); !errors.Is(err, ErrHalted) {
+		t.Fatalf("open the day after tripping: %v", err)
+	}
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"bb/alert"
+	"bb/api"
+	"bb/config"
+	"bb/feed"
+	"bb/ledger"
+	"bb/metrics"
+	"bb/pipeline"
+	"bb/strategy"
+	"bb/txmanager"
+)
+
+// runCommand monitors the selected chains and trades.
+func runCommand(args []string) error {
+	opts := newOptions("run", "[flags]", "Monitors every chain, or the one named by --chain, and trades the opportunities found.")
+	if _, err := opts.parse(args); err != nil {
+		return err
+	}
+	return serve(opts, false, "")
+}
+
+// monitorCommand monitors the selected chains with execution paused. Trading
+// can be resumed through the API.
+func monitorCommand(args []string) error {
+	opts := newOptions("monitor", "[flags]", "Monitors every chain, or the one named by --chain, evaluating and recording\nopportunities with trading paused.")
+	record := opts.flags.String("record", "", "file to append every swap event to, for replay")
+	if _, err := opts.parse(args); err != nil {
+		return err
+	}
+	return serve(opts, true, *record)
+}
+
+// serve runs a pipeline per selected chain, with the ledger, metrics, feed,
+// alerts and API of the config, until SIGINT or SIGTERM. Pipelines start
+// paused if paused is set; every swap event is appended to the event log at
+// record if it is set.
+//
+// On the first signal evaluations stop and the trades in flight are handled
+// by the shutdown policy; the API, metrics and alerts keep serving until then.
+// A second signal exits at once.
+func serve(opts *options, paused bool, record string) error {
+	started := time.Now()
+
+	cfg, err := opts.load(true)
+	if err != nil {
+		return err
+	}
+	chains, err := opts.chains(cfg)
+	if err != nil {
+		return err
+	}
+
+	LEDGER, err := ledger.Open(cfg.Ledger)
+	if err != nil {
+		return err
+	}
+	defer LEDGER.Close()
+
+	var EVENTS *pipeline.EventLog
+	if record != "" {
+		if EVENTS, err = pipeline.CreateEventLog(record); err != nil {
+			return err
+		}
+		defer EVENTS.Close()
+		slog.Info("recording swap events", "path", record)
+	}
+
+	// ctx ends on the first signal and stops the pipelines; serveCtx ends
+	// once their trades are handled and stops everything else
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	serveCtx, stopServing := context.WithCancel(context.Background())
+	defer stopServing()
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	go func() {
+		select {
+		case sig := <-signals:
+			slog.Info("shutting down", "signal", sig.String(), "policy", cfg.Shutdown.Policy)
+			stop()
+		case <-serveCtx.Done():
+			return
+		}
+		select {
+		case sig := <-signals:
+			slog.Error("forced exit, trades in flight are not followed", "signal", sig.String())
+			os.Exit(1)
+		case <-serveCtx.Done():
+		}
+	}()
+
+	SIGNER, err := loadSigner(ctx)
+	if err != nil {
+		return err
+	}
+	slog.Info("trading", "account", SIGNER.Address().Hex())
+
+	strategy.Announce()
+
+	METRICS := metrics.New()
+	FEED := feed.New()
+
+	var ALERTS *alert.Alerter
+	if cfg.Alerts != nil {
+		ALERTS = newAlerter(cfg.Alerts)
+		go ALERTS.Run(serveCtx)
+	}
+	var servers sync.WaitGroup
+	if cfg.MetricsAddr != "" {
+		servers.Add(1)
+		go func() {
+			defer servers.Done()
+			if err := METRICS.Serve(serveCtx, cfg.MetricsAddr); err != nil {
+				log.Fatalf("%v", err)
+			}
+		}()
+	}
+
+	// One independent pipeline per chain
+	var pipelines []*pipeline.Pipeline
+	for _, chain := range chains {
+		if len(chain.NodeURLs) == 0 {
+			slog.Warn("skipped, no node URL configured", "chain", chain.Name)
+			continue
+		}
+		p, err := pipeline.New(ctx, chain, SIGNER)
+		if err != nil {
+			return err
+		}
+		if err := p.Risk.Limits.Validate(); err != nil {
+			if !paused {
+				return fmt.Errorf("chain %s: %v", chain.Name, err)
+			}
+			p.Log.Warn("trading cannot be resumed", "err", err)
+		}
+		p.Ledger = LEDGER
+		p.SetMetrics(METRICS)
+		p.Feed = FEED
+		p.Events = EVENTS
+		if ALERTS != nil {
+			p.SetAlerts(ALERTS)
+		}
+		if paused {
+			p.Pause()
+		}
+		pipelines = append(pipelines, p)
+	}
+	if len(pipelines) == 0 {
+		return fmt.Errorf("no chains to run")
+	}
+
+	if cfg.APIAddr != "" {
+		API := api.New(pipelines, cfg.APIToken, FEED)
+		servers.Add(1)
+		go func() {
+			defer servers.Done()
+			if err := API.Serve(serveCtx, cfg.APIAddr); err != nil {
+				log.Fatalf("%v", err)
+			}
+		}()
+	}
+
+	var wg sync.WaitGroup
+	for _, p := range pipelines {
+		wg.Add(1)
+		go func(p *pipeline.Pipeline) {
+			defer wg.Done()
+			p.Run(ctx)
+		}(p)
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				pipeline.LogSummary(pipelines)
+			}
+		}
+	}()
+
+	wg.Wait()
+	return shutdown(pipelines, cfg.Shutdown, started, func() {
+		stopServing()
+		servers.Wait()
+		flushCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		ALERTS.Flush(flushCtx)
+	})
+}
+
+// shutdown handles the trades in flight of stopped pipelines by the shutdown
+// policy, then calls stopServing and logs a summary. It fails if
+// transactions are left pending.
+func shutdown(pipelines []*pipeline.Pipeline, policy config.Shutdown, started time.Time, stopServing func()) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(policy.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	pending := make([][]*txmanager.Tx, len(pipelines))
+	var wg sync.WaitGroup
+	for i, p := range pipelines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pending[i] = p.Shutdown(ctx, policy.Policy)
+		}()
+	}
+	wg.Wait()
+	stopServing()
+
+	pipeline.LogSummary(pipelines)
+	left := 0
+	for i, p := range pipelines {
+		for _, tx := range pending[i] {
+			p.Log.Warn("transaction still pending", "label", tx.Label, "tx", tx.Hash().Hex(), "nonce", tx.Current().Nonce(), "status", tx.Status().String())
+		}
+		left += len(pending[i])
+	}
+	slog.Info("stopped", "uptime", time.Since(started).Round(time.Second), "pendingTransactions", left)
+	if left > 0 {
+		return fmt.Errorf("%d transactions still pending at exit", left)
+	}
+	return nil
+}
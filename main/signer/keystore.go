@@ -0,0 +1,60 @@
+package signer
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"golang.org/x/term"
+)
+
+// LoadKeystore decrypts a go-ethereum keystore file. The passphrase is read
+// from passphraseFile, or prompted for on the terminal if it is empty.
+func LoadKeystore(path, passphraseFile string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore: %v", err)
+	}
+
+	var passphrase string
+	if passphraseFile != "" {
+		content, err := os.ReadFile(passphraseFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read passphrase file: %v", err)
+		}
+		passphrase = strings.TrimRight(string(content), "\r\n")
+	} else {
+		if passphrase, err = prompt(fmt.Sprintf("Passphrase for %s: ", path)); err != nil {
+			return nil, err
+		}
+	}
+
+	key, err := keystore.DecryptKey(data, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore %s: %v", path, err)
+	}
+	return NewKey(key.PrivateKey), nil
+}
+
+// prompt reads a passphrase from the terminal without echoing it, or a line
+// from stdin when it is not a terminal.
+func prompt(message string) (string, error) {
+	fmt.Fprint(os.Stderr, message)
+	defer fmt.Fprintln(os.Stderr)
+
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		passphrase, err := term.ReadPassword(fd)
+		if err != nil {
+			return "", fmt.Errorf("failed to read passphrase: %v", err)
+		}
+		return string(passphrase), nil
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read passphrase: %v", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,199 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// SendTxArgs is the transaction a remote signer is asked to sign, in the
+// format of Clef's account_signTransaction.
+type SendTxArgs struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+	Value                hexutil.Big     `json:"value"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	Data                 *hexutil.Bytes  `json:"data"`
+	ChainID              *hexutil.Big    `json:"chainId,omitempty"`
+}
+
+// SignTransactionResult is the answer of account_signTransaction.
+type SignTransactionResult struct {
+	Raw hexutil.Bytes         `json:"raw"`
+	Tx  *ethtypes.Transaction `json:"tx"`
+}
+
+// Remote forwards signing to a Clef-compatible JSON-RPC signer, which may ask
+// its operator to approve every transaction.
+type Remote struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+// DialRemote connects to a remote signer. A zero address selects the first
+// account the signer lists.
+func DialRemote(ctx context.Context, url string, address common.Address) (*Remote, error) {
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial signer: %v", err)
+	}
+
+	var accounts []common.Address
+	if err := client.CallContext(ctx, &accounts, "account_list"); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to list signer accounts: %v", err)
+	}
+	if address == (common.Address{}) {
+		if len(accounts) == 0 {
+			client.Close()
+			return nil, fmt.Errorf("signer at %s has no accounts", url)
+		}
+		address = accounts[0]
+	} else {
+		found := false
+		for _, account := range accounts {
+			found = found || account == address
+		}
+		if !found {
+			client.Close()
+			return nil, fmt.Errorf("signer at %s does not hold %s", url, address.Hex())
+		}
+	}
+	return &Remote{client: client, address: address}, nil
+}
+
+func (r *Remote) Address() common.Address {
+	return r.address
+}
+
+func (r *Remote) SignTx(ctx context.Context, tx *ethtypes.Transaction, chainID *big.Int) (*ethtypes.Transaction, error) {
+	data := hexutil.Bytes(tx.Data())
+	args := SendTxArgs{
+		From:    r.address,
+		To:      tx.To(),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   hexutil.Big(*tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Data:    &data,
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	if tx.Type() == ethtypes.DynamicFeeTxType {
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	} else {
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	}
+
+	var result SignTransactionResult
+	if err := r.client.CallContext(ctx, &result, "account_signTransaction", args); err != nil {
+		return nil, fmt.Errorf("remote signer refused to sign: %v", err)
+	}
+	signed := new(ethtypes.Transaction)
+	if err := signed.UnmarshalBinary(result.Raw); err != nil {
+		return nil, fmt.Errorf("remote signer returned an invalid transaction: %v", err)
+	}
+	if sender, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(chainID), signed); err != nil || sender != r.address {
+		return nil, fmt.Errorf("remote signer did not sign as %s", r.address.Hex())
+	}
+	return signed, nil
+}
+
+func (r *Remote) Close() {
+	r.client.Close()
+}
+
+// StandIn serves the account API of a remote signer over HTTP, signing with
+// a local signer and approving every request. It stands in for Clef when
+// developing and testing.
+type StandIn struct {
+	server *rpc.Server
+}
+
+// NewStandIn creates a stand-in signing with signer.
+func NewStandIn(signer Signer) (*StandIn, error) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", &accountAPI{signer: signer}); err != nil {
+		return nil, err
+	}
+	return &StandIn{server: server}, nil
+}
+
+func (s *StandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.server.ServeHTTP(w, r)
+}
+
+func (s *StandIn) Close() {
+	s.server.Stop()
+}
+
+type accountAPI struct {
+	signer Signer
+}
+
+func (a *accountAPI) List(ctx context.Context) ([]common.Address, error) {
+	return []common.Address{a.signer.Address()}, nil
+}
+
+func (a *accountAPI) SignTransaction(ctx context.Context, args SendTxArgs, methodSelector *string) (*SignTransactionResult, error) {
+	if args.From != a.signer.Address() {
+		return nil, fmt.Errorf("unknown account %s", args.From.Hex())
+	}
+	if args.ChainID == nil {
+		return nil, fmt.Errorf("chainId is required")
+	}
+	var data []byte
+	if args.Data != nil {
+		data = *args.Data
+	}
+
+	var tx *ethtypes.Transaction
+	if args.MaxFeePerGas != nil {
+		tip := new(big.Int)
+		if args.MaxPriorityFeePerGas != nil {
+			tip = args.MaxPriorityFeePerGas.ToInt()
+		}
+		tx = ethtypes.NewTx(&ethtypes.DynamicFeeTx{
+			ChainID:   args.ChainID.ToInt(),
+			Nonce:     uint64(args.Nonce),
+			GasTipCap: tip,
+			GasFeeCap: args.MaxFeePerGas.ToInt(),
+			Gas:       uint64(args.Gas),
+			To:        args.To,
+			Value:     args.Value.ToInt(),
+			Data:      data,
+		})
+	} else {
+		gasPrice := new(big.Int)
+		if args.GasPrice != nil {
+			gasPrice = args.GasPrice.ToInt()
+		}
+		tx = ethtypes.NewTx(&ethtypes.LegacyTx{
+			Nonce:    uint64(args.Nonce),
+			GasPrice: gasPrice,
+			Gas:      uint64(args.Gas),
+			To:       args.To,
+			Value:    args.Value.ToInt(),
+			Data:     data,
+		})
+	}
+
+	signed, err := a.signer.SignTx(ctx, tx, args.ChainID.ToInt())
+	if err != nil {
+		return nil, err
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &SignTransactionResult{Raw: raw, Tx: signed}, nil
+}
@@ -0,0 +1,75 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Signer signs transactions for one account. Implementations hold a raw key,
+// an unlocked keystore file or forward to a remote signer.
+type Signer interface {
+	Address() common.Address
+	SignTx(ctx context.Context, tx *ethtypes.Transaction, chainID *big.Int) (*ethtypes.Transaction, error)
+}
+
+// Key signs with a private key held in memory.
+type Key struct {
+	key *ecdsa.PrivateKey
+}
+
+// NewKey wraps a private key.
+func NewKey(key *ecdsa.PrivateKey) *Key {
+	return &Key{key: key}
+}
+
+// ParseKey reads a hex private key, with or without 0x prefix.
+func ParseKey(hex string) (*Key, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(hex), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %v", err)
+	}
+	return NewKey(key), nil
+}
+
+func (k *Key) Address() common.Address {
+	return crypto.PubkeyToAddress(k.key.PublicKey)
+}
+
+func (k *Key) SignTx(ctx context.Context, tx *ethtypes.Transaction, chainID *big.Int) (*ethtypes.Transaction, error) {
+	return ethtypes.SignTx(tx, ethtypes.LatestSignerForChainID(chainID), k.key)
+}
+
+// TransactOpts is the factory for transactors on a chain: every transactor
+// shares one signer.
+type TransactOpts struct {
+	Signer  Signer
+	ChainID *big.Int
+}
+
+// NewTransactOpts creates the transactor factory for a chain.
+func NewTransactOpts(signer Signer, chainID *big.Int) *TransactOpts {
+	return &TransactOpts{Signer: signer, ChainID: chainID}
+}
+
+// Opts returns fresh transact options signing through the shared signer.
+func (t *TransactOpts) Opts(ctx context.Context) *bind.TransactOpts {
+	from := t.Signer.Address()
+	return &bind.TransactOpts{
+		From:    from,
+		Context: ctx,
+		Signer: func(address common.Address, tx *ethtypes.Transaction) (*ethtypes.Transaction, error) {
+			if address != from {
+				return nil, bind.ErrNotAuthorized
+			}
+			return t.Signer.SignTx(ctx, tx, t.ChainID)
+		},
+	}
+}
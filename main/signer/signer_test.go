@@ -0,0 +1,137 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// transactions returns a legacy and a dynamic fee transaction to sign.
+func transactions() []*ethtypes.Transaction {
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	return []*ethtypes.Transaction{
+		ethtypes.NewTx(&ethtypes.LegacyTx{Nonce: 7, GasPrice: big.NewInt(2e9), Gas: 21000, To: &to, Value: big.NewInt(1), Data: []byte{1, 2}}),
+		ethtypes.NewTx(&ethtypes.DynamicFeeTx{ChainID: big.NewInt(1337), Nonce: 8, GasTipCap: big.NewInt(1e9), GasFeeCap: big.NewInt(3e9), Gas: 50000, To: &to, Data: []byte{3}}),
+	}
+}
+
+// checkSigns signs every test transaction with s and checks that it is signed
+// by s's account and otherwise unchanged.
+func checkSigns(t *testing.T, s Signer) {
+	t.Helper()
+	chainID := big.NewInt(1337)
+	for _, tx := range transactions() {
+		signed, err := s.SignTx(context.Background(), tx, chainID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sender, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(chainID), signed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sender != s.Address() {
+			t.Errorf("type %d transaction signed by %s, want %s", tx.Type(), sender.Hex(), s.Address().Hex())
+		}
+		if signed.Type() != tx.Type() || signed.Nonce() != tx.Nonce() || *signed.To() != *tx.To() || !bytes.Equal(signed.Data(), tx.Data()) || signed.GasFeeCap().Cmp(tx.GasFeeCap()) != 0 {
+			t.Errorf("type %d transaction changed by signing", tx.Type())
+		}
+	}
+}
+
+func newKey(t *testing.T) *Key {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewKey(key)
+}
+
+func TestKey(t *testing.T) {
+	checkSigns(t, newKey(t))
+}
+
+func TestLoadKeystore(t *testing.T) {
+	key := newKey(t)
+	dir := t.TempDir()
+	account, err := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP).ImportECDSA(key.key, "hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, passphrase := account.URL.Path, filepath.Join(dir, "passphrase")
+	if err := os.WriteFile(passphrase, []byte("hunter2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadKeystore(path, passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Address() != key.Address() {
+		t.Fatalf("loaded %s, want %s", loaded.Address().Hex(), key.Address().Hex())
+	}
+	checkSigns(t, loaded)
+}
+
+// TestRemote signs through a Clef stand-in served over HTTP, directly and
+// through a remote signer dialed to it.
+func TestRemote(t *testing.T) {
+	key := newKey(t)
+	standIn, err := NewStandIn(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer standIn.Close()
+	server := httptest.NewServer(standIn)
+	defer server.Close()
+
+	remote, err := DialRemote(context.Background(), server.URL, common.Address{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer remote.Close()
+	if remote.Address() != key.Address() {
+		t.Fatalf("remote signs as %s, want the stand-in's %s", remote.Address().Hex(), key.Address().Hex())
+	}
+	checkSigns(t, remote)
+
+	if _, err := DialRemote(context.Background(), server.URL, newKey(t).Address()); err == nil {
+		t.Error("dialed a signer for an account it does not hold")
+	}
+}
+
+// impostor lists one account but signs with another key.
+type impostor struct {
+	*Key
+	address common.Address
+}
+
+func (i impostor) Address() common.Address { return i.address }
+
+func TestRemoteRejectsOtherSender(t *testing.T) {
+	listed := newKey(t).Address()
+	standIn, err := NewStandIn(impostor{Key: newKey(t), address: listed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer standIn.Close()
+	server := httptest.NewServer(standIn)
+	defer server.Close()
+
+	remote, err := DialRemote(context.Background(), server.URL, listed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer remote.Close()
+	if _, err := remote.SignTx(context.Background(), transactions()[1], big.NewInt(1337)); err == nil {
+		t.Fatal("accepted a transaction signed by another account")
+	}
+}
@@ -0,0 +1,467 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"math/big"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"bb/testutil"
+	"bb/types"
+)
+
+// Cycles gaining at most noGain, as the log of the product of their rates,
+// must not be reported, and cycles gaining at least sureGain must be found.
+// Rate graphs with cycles in between are too close to call and skipped.
+const (
+	noGain   = 1e-10
+	sureGain = 1e-6
+)
+
+// simpleCycles calls visit with every simple cycle of a graph of n nodes, as
+// node indexes from its lowest node back to it, and its gain, the sum of the
+// gains of its edges. gain reports the gain of an edge and whether there is
+// one. Enumeration stops when visit returns false.
+func simpleCycles(n int, gain func(i, j int) (float64, bool), visit func(cycle []int, gain float64) bool) {
+	var walk func(start int, path []int, total float64) bool
+	walk = func(start int, path []int, total float64) bool {
+		last := path[len(path)-1]
+		for next := start; next < n; next++ {
+			g, ok := gain(last, next)
+			if !ok {
+				continue
+			}
+			if next == start {
+				if !visit(append(append([]int(nil), path...), start), total+g) {
+					return false
+				}
+				continue
+			}
+			if containsInt(path, next) {
+				continue
+			}
+			if !walk(start, append(path, next), total+g) {
+				return false
+			}
+		}
+		return true
+	}
+	for start := 0; start < n; start++ {
+		if !walk(start, []int{start}, 0) {
+			return
+		}
+	}
+}
+
+func containsInt(s []int, v int) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// matrixGain is the gain of an edge of a matrix, log(rate), for positive
+// finite rates.
+func matrixGain(matrix map[AssetDEX]map[AssetDEX]*big.Float, from, to AssetDEX) (float64, bool) {
+	rate, ok := matrix[from][to]
+	if !ok {
+		return 0, false
+	}
+	r, _ := rate.Float64()
+	if r <= 0 || math.IsInf(r, 0) {
+		return 0, false
+	}
+	return math.Log(r), true
+}
+
+// eventGains is the oracle's graph, built from the raw events rather than the
+// matrix: every pair's latest event makes an edge each way between its assets
+// on its DEX, whose gain is the log of the best rate of the pairs making it,
+// and the nodes of an asset on different DEXes are joined both ways without
+// gain. Rates that are not positive and finite make no edge.
+func eventGains(pairs []types.Pair, events []types.SwapEvent) map[[2]AssetDEX]float64 {
+	gains := make(map[[2]AssetDEX]float64)
+	nodes := make(map[AssetDEX]bool)
+	for _, pair := range pairs {
+		var latest *types.SwapEvent
+		for i := range events {
+			e := &events[i]
+			if e.Address == pair.Address() && e.Asset1Name == pair.Asset1() && e.Asset2Name == pair.Asset2() {
+				latest = e
+			}
+		}
+		if latest == nil {
+			continue
+		}
+		from, to := AssetDEX{pair.Asset1(), pair.DEX()}, AssetDEX{pair.Asset2(), pair.DEX()}
+		nodes[from], nodes[to] = true, true
+		for _, edge := range []struct {
+			key  [2]AssetDEX
+			rate *big.Float
+		}{{[2]AssetDEX{from, to}, latest.AmountOut.Amount1}, {[2]AssetDEX{to, from}, latest.AmountOut.Amount2}} {
+			r, _ := edge.rate.Float64()
+			if r <= 0 || math.IsInf(r, 0) {
+				continue
+			}
+			if gain, ok := gains[edge.key]; !ok || math.Log(r) > gain {
+				gains[edge.key] = math.Log(r)
+			}
+		}
+	}
+	for a := range nodes {
+		for b := range nodes {
+			if a.Asset == b.Asset && a.DEX != b.DEX {
+				gains[[2]AssetDEX{a, b}] = 0
+			}
+		}
+	}
+	return gains
+}
+
+// classify tells whether a graph of edge gains has a cycle gaining at least
+// sureGain, and whether it has one too close to call.
+func classify(gains map[[2]AssetDEX]float64) (profitable, unclear bool) {
+	seen := make(map[AssetDEX]bool)
+	var nodes []AssetDEX
+	for edge := range gains {
+		for _, node := range edge {
+			if !seen[node] {
+				seen[node] = true
+				nodes = append(nodes, node)
+			}
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool { return lessAssetDEX(nodes[i], nodes[j]) })
+	simpleCycles(len(nodes), func(i, j int) (float64, bool) {
+		gain, ok := gains[[2]AssetDEX{nodes[i], nodes[j]}]
+		return gain, ok
+	}, func(cycle []int, gain float64) bool {
+		switch {
+		case gain >= sureGain:
+			profitable = true
+		case gain > noGain:
+			unclear = true
+		}
+		return true
+	})
+	return profitable, unclear
+}
+
+func sortedNodes(matrix map[AssetDEX]map[AssetDEX]*big.Float) []AssetDEX {
+	nodes := make([]AssetDEX, 0, len(matrix))
+	for node := range matrix {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return lessAssetDEX(nodes[i], nodes[j]) })
+	return nodes
+}
+
+// checkCycle fails the test unless path is a simple cycle of the matrix
+// gaining more than noGain, and returns its edges.
+func checkCycle(t *testing.T, matrix map[AssetDEX]map[AssetDEX]*big.Float, path []AssetDEX) [][2]AssetDEX {
+	t.Helper()
+	if len(path) < 2 || path[0] != path[len(path)-1] {
+		t.Fatalf("path %v is not a cycle", path)
+	}
+	seen := make(map[AssetDEX]bool)
+	var edges [][2]AssetDEX
+	total := 0.0
+	for i := 0; i+1 < len(path); i++ {
+		if seen[path[i]] {
+			t.Fatalf("path %v visits %v twice", path, path[i])
+		}
+		seen[path[i]] = true
+		gain, ok := matrixGain(matrix, path[i], path[i+1])
+		if !ok {
+			t.Fatalf("path %v takes %v > %v, which is no edge", path, path[i], path[i+1])
+		}
+		total += gain
+		edges = append(edges, [2]AssetDEX{path[i], path[i+1]})
+	}
+	if total <= noGain {
+		t.Fatalf("path %v gains %g", path, total)
+	}
+	return edges
+}
+
+// plantedGraph is a random rate graph in which the only cycles that can gain
+// go through a boosted edge.
+type plantedGraph struct {
+	pairs      []types.Pair
+	events     []types.SwapEvent
+	boosted    map[[2]AssetDEX]bool
+	duplicated bool // some DEX lists a pair twice
+}
+
+// randomGraph prices assets between 1e-12 and 1e12 and quotes every pair at
+// those prices less a fee, so that no cycle gains. It then boosts a few
+// edges, whose cycles may or may not gain. Some graphs are split into two
+// components, some DEXes list a pair twice with different rates, and some
+// pools are empty, with a zero and an infinite rate.
+func randomGraph(r *rand.Rand) plantedGraph {
+	nAssets, nDEXes := 2+r.Intn(3), 1+r.Intn(3)
+	prices := make([]float64, nAssets)
+	for i := range prices {
+		prices[i] = math.Exp((2*r.Float64() - 1) * 12 * math.Ln10)
+	}
+	split := nAssets // assets below split never trade with the others
+	if nAssets >= 4 && r.Intn(3) == 0 {
+		split = 2
+	}
+	fees := []float64{1, 0.997, 0.9975, 0.999}
+	boosts := []float64{1.01, 1.05, 1.5, 1e3, 1e9}
+
+	g := plantedGraph{boosted: make(map[[2]AssetDEX]bool)}
+	block := uint64(1)
+	for d := range nDEXes {
+		dex := fmt.Sprintf("dex%d", d)
+		for i := range nAssets {
+			for j := i + 1; j < nAssets; j++ {
+				if (i < split) != (j < split) || r.Intn(5) < 2 {
+					continue
+				}
+				copies := 1 + r.Intn(4)/3
+				g.duplicated = g.duplicated || copies > 1
+				for ; copies > 0; copies-- {
+					a1, a2 := fmt.Sprintf("A%d", i), fmt.Sprintf("A%d", j)
+					p1, p2 := prices[i], prices[j]
+					if r.Intn(2) == 0 {
+						a1, a2, p1, p2 = a2, a1, p2, p1
+					}
+					pair := testutil.NewScript(dex, a1, a2)
+					pair.Addr = fmt.Sprintf("%s-%d", pair.Addr, copies)
+					fee := fees[r.Intn(len(fees))]
+					forward, backward := big.NewFloat(p1/p2*fee), big.NewFloat(p2/p1*fee)
+					switch r.Intn(10) {
+					case 0:
+						forward, backward = new(big.Float), new(big.Float).SetInf(false)
+					case 1:
+						forward.Mul(forward, big.NewFloat(boosts[r.Intn(len(boosts))]))
+						g.boosted[[2]AssetDEX{{a1, dex}, {a2, dex}}] = true
+					case 2:
+						backward.Mul(backward, big.NewFloat(boosts[r.Intn(len(boosts))]))
+						g.boosted[[2]AssetDEX{{a2, dex}, {a1, dex}}] = true
+					}
+					g.pairs = append(g.pairs, pair)
+					g.events = append(g.events, types.SwapEvent{
+						DEXName:     dex,
+						Asset1Name:  a1,
+						Asset2Name:  a2,
+						Address:     pair.Addr,
+						AmountOut:   types.AmountOut{Amount1: forward, Amount2: backward},
+						BlockNumber: block,
+					})
+					block++
+				}
+			}
+		}
+	}
+	r.Shuffle(len(g.pairs), func(i, j int) { g.pairs[i], g.pairs[j] = g.pairs[j], g.pairs[i] })
+	return g
+}
+
+func newCycleStrategy(pairs []types.Pair) *Strategy {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewStrategy("test", testutil.NewClock(1, big.NewInt(1)), pairs, nil, GasModel{}, logger)
+}
+
+// TestDetectPlantedCycles checks the detector against every simple cycle of
+// random graphs, built from the events: it finds a cycle whenever one gains,
+// the cycle it reports gains and goes through a boosted edge, and once the
+// boosted edges of the cycles it reports are removed one by one, it runs out
+// of cycles exactly when the graph does. Where a DEX lists a pair twice, only
+// the better rate of the two can make the cycle.
+func TestDetectPlantedCycles(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	ctx := context.Background()
+	checked, planted, duplicated := 0, 0, 0
+	for iteration := 0; iteration < 2000; iteration++ {
+		g := randomGraph(r)
+		s := newCycleStrategy(g.pairs)
+		matrix, _ := s.buildMatrix(ctx, g.events, 1)
+		gains := eventGains(g.pairs, g.events)
+		if g.duplicated {
+			duplicated++
+		}
+
+		for removed := 0; ; removed++ {
+			profitable, unclear := classify(gains)
+			if unclear {
+				break
+			}
+			checked++
+			if removed == 0 && profitable {
+				planted++
+			}
+
+			path, _ := s.detectArbitrageOpportunity(ctx, matrix)
+			if !profitable {
+				if path != nil {
+					t.Fatalf("iteration %d: reported %v in a graph without gaining cycles", iteration, path)
+				}
+				break
+			}
+			if path == nil {
+				t.Fatalf("iteration %d: missed a gaining cycle after removing %d edges", iteration, removed)
+			}
+			var boosted *[2]AssetDEX
+			for _, edge := range checkCycle(t, matrix, path) {
+				if g.boosted[edge] {
+					boosted = &edge
+					break
+				}
+			}
+			if boosted == nil {
+				t.Fatalf("iteration %d: reported %v, which has no boosted edge", iteration, path)
+			}
+			delete(matrix[boosted[0]], boosted[1])
+			delete(gains, *boosted)
+		}
+	}
+	if planted < 200 || checked < 1000 || duplicated < 200 {
+		t.Errorf("only %d graphs with gaining cycles and %d with duplicate pairs in %d checks", planted, duplicated, checked)
+	}
+}
+
+// TestDetectDisconnected plants the only gaining cycle in each of two
+// components in turn; the detector must find it whichever node it starts
+// from.
+func TestDetectDisconnected(t *testing.T) {
+	ctx := context.Background()
+	for _, profitable := range []string{"left", "right"} {
+		for range 50 {
+			var pairs []types.Pair
+			var events []types.SwapEvent
+			for _, side := range []string{"left", "right"} {
+				for _, assets := range [][2]string{{"A", "B"}, {"B", "C"}, {"C", "A"}} {
+					pair := testutil.NewScript(side, side+assets[0], side+assets[1])
+					rate := 0.999
+					if side == profitable {
+						rate = 1.01
+					}
+					event := testutil.RateEvent(side, pair.Asset1(), pair.Asset2(), rate, 1)
+					event.AmountOut.Amount2 = big.NewFloat(0.5)
+					pairs = append(pairs, pair)
+					events = append(events, event)
+				}
+			}
+			s := newCycleStrategy(pairs)
+			matrix, _ := s.buildMatrix(ctx, events, 1)
+			path, _ := s.detectArbitrageOpportunity(ctx, matrix)
+			if path == nil {
+				t.Fatalf("missed the cycle of the %s component", profitable)
+			}
+			checkCycle(t, matrix, path)
+			if path[0].DEX != profitable {
+				t.Fatalf("reported %v, expected the %s component", path, profitable)
+			}
+		}
+	}
+}
+
+// TestDetectCrossDEXEdges lists one asset on many DEXes: the rate 1 edges
+// between them make many cycles that gain exactly nothing, and a pair whose
+// rates are each other's inverse makes cycles that gain only rounding
+// errors. Neither is arbitrage, but a pair priced apart on one DEX is.
+func TestDetectCrossDEXEdges(t *testing.T) {
+	ctx := context.Background()
+	var pairs []types.Pair
+	var events []types.SwapEvent
+	for d := range 6 {
+		dex := fmt.Sprintf("dex%d", d)
+		pair := testutil.NewScript(dex, "ETH", "USDC")
+		pairs = append(pairs, pair)
+		events = append(events, testutil.RateEvent(dex, "ETH", "USDC", 1e9/3, 1))
+		events[d].AmountOut.Amount2 = new(big.Float).Quo(big.NewFloat(1), events[d].AmountOut.Amount1)
+	}
+	s := newCycleStrategy(pairs)
+	matrix, _ := s.buildMatrix(ctx, events, 1)
+	if path, _ := s.detectArbitrageOpportunity(ctx, matrix); path != nil {
+		t.Fatalf("reported %v", path)
+	}
+
+	// The same pools with a fee, priced 1% apart from one DEX to the next
+	for d := range events {
+		rate := new(big.Float).Mul(events[d].AmountOut.Amount1, big.NewFloat(1+0.01*float64(d)))
+		events[d].AmountOut.Amount1 = new(big.Float).Mul(rate, big.NewFloat(0.997))
+		events[d].AmountOut.Amount2 = new(big.Float).Quo(big.NewFloat(0.997), rate)
+	}
+	matrix, _ = s.buildMatrix(ctx, events, 1)
+	path, _ := s.detectArbitrageOpportunity(ctx, matrix)
+	if path == nil {
+		t.Fatal("missed buying ETH on one DEX and selling it on another")
+	}
+	checkCycle(t, matrix, path)
+}
+
+// TestDetectExtremeRates checks rates far from one, and rates that are no
+// edge: zero, infinite and beyond the range of a float64.
+func TestDetectExtremeRates(t *testing.T) {
+	ctx := context.Background()
+	huge := new(big.Float).SetMantExp(big.NewFloat(1), 2000)
+	tiny := new(big.Float).SetMantExp(big.NewFloat(1), -2000)
+	tests := []struct {
+		name  string
+		rates [][2]*big.Float // of the pairs A/B, B/C and C/A, forward and backward
+		found bool
+	}{
+		{name: "empty pool", rates: [][2]*big.Float{{new(big.Float), new(big.Float).SetInf(false)}}},
+		{name: "beyond float64", rates: [][2]*big.Float{{huge, tiny}}},
+		{name: "far apart, no gain", rates: [][2]*big.Float{{big.NewFloat(1e300), big.NewFloat(0.99e-300)}}},
+		{name: "far apart, gain", rates: [][2]*big.Float{{big.NewFloat(1e300), big.NewFloat(1.01e-300)}}, found: true},
+		{name: "tiny, gain", rates: [][2]*big.Float{{big.NewFloat(1e-300), big.NewFloat(1.01e300)}}, found: true},
+		{name: "triangle, no gain", rates: [][2]*big.Float{
+			{big.NewFloat(1e150), big.NewFloat(0.99e-150)},
+			{big.NewFloat(1e150), big.NewFloat(0.99e-150)},
+			{big.NewFloat(0.99e-300), big.NewFloat(0.99e300)},
+		}},
+		{name: "triangle, gain", rates: [][2]*big.Float{
+			{big.NewFloat(1e150), big.NewFloat(0.99e-150)},
+			{big.NewFloat(1e150), big.NewFloat(0.99e-150)},
+			{big.NewFloat(1.01e-300), big.NewFloat(0.99e300)},
+		}, found: true},
+		{name: "triangle through an empty pool", rates: [][2]*big.Float{
+			{big.NewFloat(1e150), big.NewFloat(0.99e-150)},
+			{new(big.Float).SetInf(false), new(big.Float)},
+			{big.NewFloat(1.01e-300), big.NewFloat(0.99e300)},
+		}},
+	}
+	assets := [][2]string{{"A", "B"}, {"B", "C"}, {"C", "A"}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pairs []types.Pair
+			var events []types.SwapEvent
+			for i, rates := range tt.rates {
+				pair := testutil.NewScript("uni", assets[i][0], assets[i][1])
+				pairs = append(pairs, pair)
+				events = append(events, types.SwapEvent{
+					DEXName:    "uni",
+					Address:    pair.Addr,
+					Asset1Name: assets[i][0],
+					Asset2Name: assets[i][1],
+					AmountOut:  types.AmountOut{Amount1: rates[0], Amount2: rates[1]},
+				})
+			}
+			s := newCycleStrategy(pairs)
+			matrix, _ := s.buildMatrix(ctx, events, 1)
+			path, _ := s.detectArbitrageOpportunity(ctx, matrix)
+			if !tt.found {
+				if path != nil {
+					t.Fatalf("reported %v", path)
+				}
+				return
+			}
+			if path == nil {
+				t.Fatal("missed the cycle")
+			}
+			checkCycle(t, matrix, path)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package strategy
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Execution is the outcome of a transaction sent for an opportunity.
+type Execution struct {
+	Opportunity *Opportunity
+	TxHash      common.Hash
+	Status      string // mined, reverted, cancelled or dropped
+	GasUsed     uint64
+	GasCost     *big.Int                    // in wei
+	Outputs     map[common.Address]*big.Int // net token flows of the account
+}
+
+// maxExecutions bounds the execution history kept by a strategy.
+const maxExecutions = 1000
+
+type executions struct {
+	mu      sync.Mutex
+	history []Execution
+}
+
+// Record reports the final state of a trade back to the strategy.
+func (s *Strategy) Record(ctx context.Context, e Execution) {
+	s.executions.mu.Lock()
+	s.executions.history = append(s.executions.history, e)
+	if len(s.executions.history) > maxExecutions {
+		s.executions.history = s.executions.history[len(s.executions.history)-maxExecutions:]
+	}
+	s.executions.mu.Unlock()
+
+	outputs := make([]any, 0, len(e.Outputs))
+	for token, amount := range e.Outputs {
+		outputs = append(outputs, slog.String(token.Hex(), amount.String()))
+	}
+	s.Log.InfoContext(ctx, "trade final", "tx", e.TxHash.Hex(), "status", e.Status, "gasUsed", e.GasUsed, "gasCost", formatWei(e.GasCost), slog.Group("outputs", outputs...))
+}
+
+// Executions returns the recorded trades, oldest first.
+func (s *Strategy) Executions() []Execution {
+	s.executions.mu.Lock()
+	defer s.executions.mu.Unlock()
+	return append([]Execution(nil), s.executions.history...)
+}
@@ -0,0 +1,290 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"bb/types"
+)
+
+// PairRule is a set of admission thresholds for a pair. A zero field disables
+// the corresponding check.
+type PairRule struct {
+	MinLiquidityUSD float64 `json:"minLiquidityUSD"` // minimum value of both reserves combined
+	MaxStaleBlocks  uint64  `json:"maxStaleBlocks"`  // maximum age of the latest reserve update
+}
+
+// Exclusion records why a pair is currently kept out of the matrix.
+type Exclusion struct {
+	DEX     string
+	Asset1  string
+	Asset2  string
+	Address string
+	Reason  string
+	Since   time.Time
+}
+
+// Filter drops pairs from the matrix while they are illiquid or stale. It is
+// re-evaluated on every matrix build, so pairs are re-admitted as soon as they
+// recover, or as soon as their rules are relaxed.
+type Filter struct {
+	stableAssets map[string]bool
+
+	mu        sync.Mutex
+	global    PairRule
+	overrides map[string]PairRule
+	excluded  map[string]Exclusion
+
+	Log *slog.Logger
+}
+
+// NewFilter creates a filter with a global rule, per-pair overrides keyed by
+// pair address and the assets valued at one USD.
+func NewFilter(global PairRule, overrides map[string]PairRule, stableAssets []string) *Filter {
+	f := &Filter{
+		stableAssets: make(map[string]bool),
+		excluded:     make(map[string]Exclusion),
+		Log:          slog.Default(),
+	}
+	f.SetRules(global, overrides)
+	for _, asset := range stableAssets {
+		f.stableAssets[asset] = true
+	}
+	return f
+}
+
+// SetRules replaces the global rule and the per-pair overrides, keyed by pair
+// address. They apply from the next matrix build on.
+func (f *Filter) SetRules(global PairRule, overrides map[string]PairRule) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.global = global
+	f.overrides = make(map[string]PairRule, len(overrides))
+	for address, rule := range overrides {
+		f.overrides[strings.ToLower(address)] = rule
+	}
+}
+
+// Rules returns the global rule and the per-pair overrides.
+func (f *Filter) Rules() (PairRule, map[string]PairRule) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	overrides := make(map[string]PairRule, len(f.overrides))
+	for address, rule := range f.overrides {
+		overrides[address] = rule
+	}
+	return f.global, overrides
+}
+
+// Rule returns the effective rule for a pair: override fields that are set
+// replace the global ones.
+func (f *Filter) Rule(address string) PairRule {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rule := f.global
+	override, ok := f.overrides[strings.ToLower(address)]
+	if !ok {
+		return rule
+	}
+	if override.MinLiquidityUSD != 0 {
+		rule.MinLiquidityUSD = override.MinLiquidityUSD
+	}
+	if override.MaxStaleBlocks != 0 {
+		rule.MaxStaleBlocks = override.MaxStaleBlocks
+	}
+	return rule
+}
+
+// Admit reports whether a pair may contribute edges to the matrix, given its
+// latest swap event, the USD prices derived from all events and the head
+// block. Changes in admission are logged with ctx.
+func (f *Filter) Admit(ctx context.Context, pair types.Pair, event *types.SwapEvent, prices map[string]float64, head uint64) bool {
+	if f == nil {
+		return true
+	}
+
+	reason := f.check(pair, event, prices, head)
+	key := pairKey(pair)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	previous, wasExcluded := f.excluded[key]
+	if reason == "" {
+		if wasExcluded {
+			f.Log.InfoContext(ctx, "pair re-admitted", pairAttrs(pair)...)
+			delete(f.excluded, key)
+		}
+		return true
+	}
+
+	if !wasExcluded || previous.Reason != reason {
+		f.Log.InfoContext(ctx, "pair excluded", append(pairAttrs(pair), "reason", reason)...)
+	}
+	since := time.Now()
+	if wasExcluded {
+		since = previous.Since
+	}
+	f.excluded[key] = Exclusion{
+		DEX:     pair.DEX(),
+		Asset1:  pair.Asset1(),
+		Asset2:  pair.Asset2(),
+		Address: pair.Address(),
+		Reason:  reason,
+		Since:   since,
+	}
+	return false
+}
+
+func (f *Filter) check(pair types.Pair, event *types.SwapEvent, prices map[string]float64, head uint64) string {
+	rule := f.Rule(pair.Address())
+
+	if rule.MaxStaleBlocks > 0 && head > event.BlockNumber && head-event.BlockNumber > rule.MaxStaleBlocks {
+		return fmt.Sprintf("stale, last update %d blocks ago (max %d)", head-event.BlockNumber, rule.MaxStaleBlocks)
+	}
+
+	if rule.MinLiquidityUSD > 0 {
+		liquidity, ok := liquidityUSD(event, prices)
+		if !ok {
+			return "liquidity unknown, no USD price for either asset"
+		}
+		if liquidity < rule.MinLiquidityUSD {
+			return fmt.Sprintf("liquidity $%.0f below $%.0f", liquidity, rule.MinLiquidityUSD)
+		}
+	}
+
+	return ""
+}
+
+// Forget drops the exclusion of a pair, e.g. once it is no longer monitored.
+func (f *Filter) Forget(pair types.Pair) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.excluded, pairKey(pair))
+}
+
+// Report returns the currently excluded pairs ordered by DEX and assets.
+func (f *Filter) Report() []Exclusion {
+	if f == nil {
+		return nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	report := make([]Exclusion, 0, len(f.excluded))
+	for _, exclusion := range f.excluded {
+		report = append(report, exclusion)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].DEX != report[j].DEX {
+			return report[i].DEX < report[j].DEX
+		}
+		return report[i].Asset1+"/"+report[i].Asset2 < report[j].Asset1+"/"+report[j].Asset2
+	})
+	return report
+}
+
+// LogReport writes the currently excluded pairs to the debug log.
+func (f *Filter) LogReport(ctx context.Context) {
+	report := f.Report()
+	if len(report) == 0 {
+		return
+	}
+	f.Log.DebugContext(ctx, "excluded pairs", "count", len(report))
+	for _, e := range report {
+		f.Log.DebugContext(ctx, "pair excluded", "dex", e.DEX, "asset1", e.Asset1, "asset2", e.Asset2, "pair", e.Address, "reason", e.Reason, "since", e.Since)
+	}
+}
+
+// pairAttrs identifies a pair in a log record.
+func pairAttrs(pair types.Pair) []any {
+	return []any{"dex", pair.DEX(), "asset1", pair.Asset1(), "asset2", pair.Asset2(), "pair", pair.Address()}
+}
+
+// usdPrices values every asset reachable from a stable asset through the
+// latest swap events. Stable assets are worth one USD.
+func (f *Filter) usdPrices(swapEvents []types.SwapEvent) map[string]float64 {
+	prices := make(map[string]float64)
+	if f == nil {
+		return prices
+	}
+	for asset := range f.stableAssets {
+		prices[asset] = 1
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for i := len(swapEvents) - 1; i >= 0; i-- {
+			event := swapEvents[i]
+			price1, ok1 := prices[event.Asset1Name]
+			price2, ok2 := prices[event.Asset2Name]
+			switch {
+			case ok1 && !ok2 && event.AmountOut.Amount2 != nil:
+				rate, _ := event.AmountOut.Amount2.Float64()
+				prices[event.Asset2Name] = rate * price1
+				changed = true
+			case ok2 && !ok1 && event.AmountOut.Amount1 != nil:
+				rate, _ := event.AmountOut.Amount1.Float64()
+				prices[event.Asset1Name] = rate * price2
+				changed = true
+			}
+		}
+	}
+
+	return prices
+}
+
+func liquidityUSD(event *types.SwapEvent, prices map[string]float64) (float64, bool) {
+	if event.Reserve1 == nil || event.Reserve2 == nil {
+		return 0, false
+	}
+	reserve1, _ := event.Reserve1.Float64()
+	reserve2, _ := event.Reserve2.Float64()
+	price1, ok1 := prices[event.Asset1Name]
+	price2, ok2 := prices[event.Asset2Name]
+
+	switch {
+	case ok1 && ok2:
+		return reserve1*price1 + reserve2*price2, true
+	case ok1:
+		return 2 * reserve1 * price1, true
+	case ok2:
+		return 2 * reserve2 * price2, true
+	}
+	return 0, false
+}
+
+// pairKey identifies a pair by address and assets, since pools with more than
+// two assets expose several pairs at one address.
+func pairKey(pair types.Pair) string {
+	return strings.ToLower(pair.Address()) + ":" + pair.Asset1() + "/" + pair.Asset2()
+}
+
+func latestEvent(swapEvents []types.SwapEvent, pair types.Pair) *types.SwapEvent {
+	for i := len(swapEvents) - 1; i >= 0; i-- {
+		event := &swapEvents[i]
+		if strings.EqualFold(event.Address, pair.Address()) && event.Asset1Name == pair.Asset1() && event.Asset2Name == pair.Asset2() {
+			return event
+		}
+	}
+	return nil
+}
+
+func latestBlock(swapEvents []types.SwapEvent) uint64 {
+	var head uint64
+	for _, event := range swapEvents {
+		if event.BlockNumber > head {
+			head = event.BlockNumber
+		}
+	}
+	return head
+}
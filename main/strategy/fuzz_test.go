@@ -0,0 +1,319 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"bb/testutil"
+	"bb/types"
+)
+
+// FuzzBellmanFord runs the cycle search on graphs of up to six nodes whose
+// weights are multiples of 1/8, so that distances are exact, and checks it
+// against every simple cycle: a violated edge is left iff there is a
+// negative cycle, and the cycle traced back from it is negative.
+func FuzzBellmanFord(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{1, 0xf8})
+	f.Add([]byte{3, 0, 0x09, 0, 0, 0, 0x09, 0xe9, 0, 0})
+	f.Add([]byte{4, 0, 0x11, 0, 0, 0xf1, 0, 0, 0, 0, 0, 0, 0x05, 0, 0, 0xfd, 0})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if len(data) == 0 {
+			return
+		}
+		n := int(data[0]) % 7
+		data = data[1:]
+		graph := make([][]float64, n)
+		for i := range graph {
+			graph[i] = make([]float64, n)
+			for j := range graph[i] {
+				graph[i][j] = math.Inf(1)
+				if k := i*n + j; k < len(data) && data[k]%4 != 0 {
+					graph[i][j] = float64(int8(data[k])) / 8
+				}
+			}
+		}
+
+		negative := false
+		simpleCycles(n, func(i, j int) (float64, bool) {
+			return -graph[i][j], !math.IsInf(graph[i][j], 1)
+		}, func(cycle []int, gain float64) bool {
+			negative = gain > 0
+			return !negative
+		})
+
+		distances, predecessors := bellmanFord(graph, n)
+		if len(distances) != n || len(predecessors) != n {
+			t.Fatalf("%d distances and %d predecessors for %d nodes", len(distances), len(predecessors), n)
+		}
+		for i := range n {
+			for j := range n {
+				if !(distances[j] > distances[i]+graph[i][j]+cycleTolerance) {
+					continue
+				}
+				if !negative {
+					t.Fatalf("edge %d > %d still relaxes without a negative cycle", i, j)
+				}
+				predecessors[j] = i
+				cycle := cyclePath(predecessors, j)
+				if len(cycle) < 2 || cycle[0] != cycle[len(cycle)-1] {
+					t.Fatalf("traced %v from edge %d > %d", cycle, i, j)
+				}
+				weight := 0.0
+				for k := 0; k+1 < len(cycle); k++ {
+					weight += graph[cycle[k]][cycle[k+1]]
+				}
+				if !(weight < 0) {
+					t.Fatalf("traced %v weighing %g", cycle, weight)
+				}
+				return
+			}
+		}
+		if negative {
+			t.Fatal("missed a negative cycle")
+		}
+	})
+}
+
+// fuzzRate maps a byte to a rate that is zero, negative, infinite, beyond
+// the range of a float64 either way, or a power of two.
+func fuzzRate(b byte) *big.Float {
+	one := big.NewFloat(1)
+	switch b % 8 {
+	case 0:
+		return new(big.Float)
+	case 1:
+		return big.NewFloat(-1)
+	case 2:
+		return new(big.Float).SetInf(false)
+	case 3:
+		return new(big.Float).SetMantExp(one, 2000)
+	case 4:
+		return new(big.Float).SetMantExp(one, -2000)
+	}
+	return new(big.Float).SetMantExp(one, int(int8(b))*8)
+}
+
+// FuzzBuildGraph checks that every rate of a matrix becomes its -log weight,
+// and that rates that are no edge, and missing edges, weigh +Inf.
+func FuzzBuildGraph(f *testing.F) {
+	f.Add([]byte{0, 1, 5, 1, 0, 5})
+	f.Add([]byte{0, 1, 0, 1, 0, 2, 1, 2, 3, 2, 1, 4, 3, 4, 0x45, 4, 3, 0xfd})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		matrix := make(map[AssetDEX]map[AssetDEX]*big.Float)
+		for ; len(data) >= 3; data = data[3:] {
+			from := AssetDEX{fmt.Sprintf("A%d", data[0]%6), "dex"}
+			to := AssetDEX{fmt.Sprintf("A%d", data[1]%6), "dex"}
+			for _, node := range []AssetDEX{from, to} {
+				if matrix[node] == nil {
+					matrix[node] = make(map[AssetDEX]*big.Float)
+				}
+			}
+			matrix[from][to] = fuzzRate(data[2])
+		}
+
+		graph, nodes := buildGraph(matrix)
+		if len(graph) != len(matrix) || len(nodes) != len(matrix) {
+			t.Fatalf("%d rows and %d nodes for %d nodes", len(graph), len(nodes), len(matrix))
+		}
+		seen := make(map[AssetDEX]bool)
+		for _, node := range nodes {
+			if _, ok := matrix[node]; !ok || seen[node] {
+				t.Fatalf("nodes %v", nodes)
+			}
+			seen[node] = true
+		}
+		for i, from := range nodes {
+			for j, to := range nodes {
+				weight := graph[i][j]
+				want := math.Inf(1)
+				if rate, ok := matrix[from][to]; ok {
+					if r, _ := rate.Float64(); r > 0 && !math.IsInf(r, 1) {
+						want = -math.Log(r)
+					}
+				}
+				if math.IsNaN(weight) || math.IsInf(weight, -1) || weight != want {
+					t.Fatalf("%v > %v weighs %g, want %g", from, to, weight, want)
+				}
+			}
+		}
+	})
+}
+
+// fuzzPairs reads pairs of up to four assets on up to three DEXes from data,
+// four bytes each: DEX and assets, and two bytes that make the rates of its
+// event, if it has one.
+func fuzzPairs(data []byte, rate func(b byte) *big.Float) ([]types.Pair, []types.SwapEvent) {
+	var pairs []types.Pair
+	var events []types.SwapEvent
+	for block := uint64(1); len(data) >= 4 && block <= 8; data, block = data[4:], block+1 {
+		dex := fmt.Sprintf("dex%d", data[0]%3)
+		i := int(data[0]/3) % 4
+		j := (i + 1 + int(data[1])%3) % 4
+		a1, a2 := fmt.Sprintf("A%d", i), fmt.Sprintf("A%d", j)
+		pair := testutil.NewScript(dex, a1, a2)
+		pair.Addr = fmt.Sprint(block)
+		pairs = append(pairs, pair)
+		if data[1]%5 == 0 {
+			continue // not seen yet
+		}
+		events = append(events, types.SwapEvent{
+			DEXName:     dex,
+			Asset1Name:  a1,
+			Asset2Name:  a2,
+			Address:     pair.Addr,
+			AmountOut:   types.AmountOut{Amount1: rate(data[2]), Amount2: rate(data[3])},
+			BlockNumber: block,
+		})
+	}
+	return pairs, events
+}
+
+// bestRate is the rate from one asset to another on a DEX: the best of the
+// rates of the latest events of the pairs of the two on the DEX, if any is
+// positive and finite. seen reports whether any such pair has an event.
+func bestRate(pairs []types.Pair, events []types.SwapEvent, dex, from, to string) (best *big.Float, seen bool) {
+	for _, pair := range pairs {
+		if pair.DEX() != dex {
+			continue
+		}
+		var latest *types.SwapEvent
+		for i := range events {
+			if events[i].Address == pair.Address() && events[i].Asset1Name == pair.Asset1() && events[i].Asset2Name == pair.Asset2() {
+				latest = &events[i]
+			}
+		}
+		var rate *big.Float
+		switch {
+		case latest == nil:
+			continue
+		case pair.Asset1() == from && pair.Asset2() == to:
+			rate = latest.AmountOut.Amount1
+		case pair.Asset1() == to && pair.Asset2() == from:
+			rate = latest.AmountOut.Amount2
+		default:
+			continue
+		}
+		seen = true
+		if r, _ := rate.Float64(); r > 0 && !math.IsInf(r, 1) && (best == nil || rate.Cmp(best) > 0) {
+			best = rate
+		}
+	}
+	return best, seen
+}
+
+// checkRate fails the test unless the matrix rate of an edge is want, or is
+// no edge when want is nil.
+func checkRate(t *testing.T, from, to AssetDEX, rate, want *big.Float) {
+	t.Helper()
+	if want == nil {
+		if r, _ := rate.Float64(); r > 0 && !math.IsInf(r, 1) {
+			t.Fatalf("%v > %v at %v, want no edge", from, to, rate)
+		}
+		return
+	}
+	if rate == nil || rate.Cmp(want) != 0 {
+		t.Fatalf("%v > %v at %v, want %v", from, to, rate, want)
+	}
+}
+
+// FuzzBuildMatrix checks that the matrix has an edge each way for every pair
+// seen, at the best rate of the latest events of the pairs of its assets on
+// its DEX, and a rate 1 edge each way between an asset's nodes on different
+// DEXes, and no other edge.
+func FuzzBuildMatrix(f *testing.F) {
+	f.Add([]byte{0, 1, 2, 3})
+	f.Add([]byte{0, 1, 2, 3, 1, 1, 4, 5, 3, 2, 6, 7, 0, 1, 8, 9})
+	f.Add([]byte{0, 1, 2, 3, 0, 4, 2, 3, 0, 5, 2, 3, 2, 1, 2, 3})
+	f.Add([]byte{0, 1, 0x15, 0x16, 0, 1, 0x17, 0x0d, 0, 1, 0x00, 0x1e})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		pairs, events := fuzzPairs(data, fuzzRate)
+		s := newCycleStrategy(pairs)
+		matrix, _ := s.buildMatrix(context.Background(), events, 8)
+
+		want := make(map[AssetDEX]bool)
+		for _, pair := range pairs {
+			from, to := AssetDEX{pair.Asset1(), pair.DEX()}, AssetDEX{pair.Asset2(), pair.DEX()}
+			forward, ok := bestRate(pairs, events, pair.DEX(), from.Asset, to.Asset)
+			if !ok {
+				continue
+			}
+			backward, _ := bestRate(pairs, events, pair.DEX(), to.Asset, from.Asset)
+			checkRate(t, from, to, matrix[from][to], forward)
+			checkRate(t, to, from, matrix[to][from], backward)
+			want[from], want[to] = true, true
+		}
+		if len(matrix) != len(want) {
+			t.Fatalf("%d nodes, want %d", len(matrix), len(want))
+		}
+		for from, edges := range matrix {
+			if !want[from] {
+				t.Fatalf("node %v of no pair seen", from)
+			}
+			for to := range want {
+				if to.Asset == from.Asset && to.DEX != from.DEX && (edges[to] == nil || edges[to].Cmp(big.NewFloat(1)) != 0) {
+					t.Fatalf("%v > %v at %v, want 1", from, to, edges[to])
+				}
+			}
+			for to, rate := range edges {
+				switch {
+				case to.DEX == from.DEX && to.Asset != from.Asset:
+					want, _ := bestRate(pairs, events, from.DEX, from.Asset, to.Asset)
+					checkRate(t, from, to, rate, want)
+				case to.Asset == from.Asset && to.DEX != from.DEX:
+				default:
+					t.Fatalf("edge %v > %v", from, to)
+				}
+			}
+		}
+	})
+}
+
+// FuzzDetect runs the whole detection on matrices built from rates that are
+// powers of two, whose cycles either gain at least double or nothing at all,
+// and checks it against every simple cycle. Cycles of a rate and its inverse
+// gain exactly nothing, though their logarithms may not cancel.
+func FuzzDetect(f *testing.F) {
+	f.Add([]byte{0, 1, 0x01, 0xff})
+	f.Add([]byte{0, 1, 0x01, 0x01})
+	f.Add([]byte{0, 1, 0x03, 0xfd, 1, 1, 0x03, 0xfc, 12, 1, 0x01, 0xff, 13, 1, 0x02, 0xfe})
+	f.Add([]byte{0, 1, 0x01, 0xfe, 3, 1, 0x01, 0xfe, 9, 2, 0x01, 0xfe, 6, 3, 0x01, 0xfe})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		pairs, events := fuzzPairs(data, func(b byte) *big.Float {
+			return new(big.Float).SetMantExp(big.NewFloat(1), int(int8(b))%8)
+		})
+		s := newCycleStrategy(pairs)
+		ctx := context.Background()
+		matrix, _ := s.buildMatrix(ctx, events, 8)
+
+		nodes := sortedNodes(matrix)
+		exponent := func(i, j int) (float64, bool) {
+			rate, ok := matrix[nodes[i]][nodes[j]]
+			if !ok {
+				return 0, false
+			}
+			r, _ := rate.Float64()
+			return math.Log2(r), true
+		}
+		profitable := false
+		simpleCycles(len(nodes), exponent, func(cycle []int, gain float64) bool {
+			profitable = gain > 0
+			return !profitable
+		})
+
+		path, _ := s.detectArbitrageOpportunity(ctx, matrix)
+		if !profitable {
+			if path != nil {
+				t.Fatalf("reported %v without a gaining cycle", path)
+			}
+			return
+		}
+		if path == nil {
+			t.Fatal("missed a gaining cycle")
+		}
+		checkCycle(t, matrix, path)
+	})
+}
@@ -0,0 +1,33 @@
+package strategy
+
+import (
+	"context"
+	"math/big"
+)
+
+// GasModel estimates what executing a cycle costs on a chain.
+type GasModel struct {
+	BaseGas         uint64  // fixed gas per transaction
+	GasPerHop       uint64  // gas per swap in the cycle
+	PriceMultiplier float64 // applied to the suggested gas price, 0 means 1
+}
+
+// Estimate returns the gas units and gas price for a cycle of hops swaps.
+func (g GasModel) Estimate(ctx context.Context, client ChainReader, hops int) (uint64, *big.Int, error) {
+	units := g.BaseGas + g.GasPerHop*uint64(hops)
+
+	price, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return units, nil, err
+	}
+	if g.PriceMultiplier != 0 && g.PriceMultiplier != 1 {
+		scaled, _ := new(big.Float).Mul(new(big.Float).SetInt(price), big.NewFloat(g.PriceMultiplier)).Int(nil)
+		price = scaled
+	}
+	return units, price, nil
+}
+
+// formatWei renders a wei amount in whole native tokens.
+func formatWei(wei *big.Int) string {
+	return new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(1e18)).Text('f', 6)
+}
@@ -0,0 +1,154 @@
+package strategy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Edge is a directed edge of the graph: swapping whole tokens of From into To
+// yields Rate whole tokens each, weighted -log(Rate) for cycle detection.
+type Edge struct {
+	From   AssetDEX `json:"from"`
+	To     AssetDEX `json:"to"`
+	Rate   float64  `json:"rate"`
+	Weight float64  `json:"weight"`
+}
+
+// Graph is the graph of one evaluation, nodes and edges in a stable order.
+type Graph struct {
+	Chain string     `json:"chain"`
+	Block uint64     `json:"block"`
+	Time  time.Time  `json:"time"`
+	Nodes []AssetDEX `json:"nodes"`
+	Edges []Edge     `json:"edges"`
+}
+
+func newGraph(chain string, block uint64, matrix map[AssetDEX]map[AssetDEX]*big.Float) *Graph {
+	g := &Graph{Chain: chain, Block: block, Time: time.Now(), Nodes: make([]AssetDEX, 0, len(matrix))}
+	for from, edges := range matrix {
+		g.Nodes = append(g.Nodes, from)
+		for to, rate := range edges {
+			r, _ := rate.Float64()
+			if r <= 0 || math.IsInf(r, 0) {
+				continue // never part of a cycle
+			}
+			weight := negativeLog(rate)
+			if weight == 0 {
+				weight = 0 // not -0
+			}
+			g.Edges = append(g.Edges, Edge{From: from, To: to, Rate: r, Weight: weight})
+		}
+	}
+	sort.Slice(g.Nodes, func(i, j int) bool { return lessAssetDEX(g.Nodes[i], g.Nodes[j]) })
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return lessAssetDEX(g.Edges[i].From, g.Edges[j].From)
+		}
+		return lessAssetDEX(g.Edges[i].To, g.Edges[j].To)
+	})
+	return g
+}
+
+func lessAssetDEX(a, b AssetDEX) bool {
+	if a.Asset != b.Asset {
+		return a.Asset < b.Asset
+	}
+	return a.DEX < b.DEX
+}
+
+// maxOpportunities bounds the opportunities kept by a strategy.
+const maxOpportunities = 100
+
+type opportunities struct {
+	mu     sync.Mutex
+	recent []Opportunity
+}
+
+func (o *opportunities) add(opportunity Opportunity) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.recent = append(o.recent, opportunity)
+	if len(o.recent) > maxOpportunities {
+		o.recent = o.recent[len(o.recent)-maxOpportunities:]
+	}
+}
+
+// Opportunities returns the latest opportunities found, oldest first.
+func (s *Strategy) Opportunities() []Opportunity {
+	s.opportunities.mu.Lock()
+	defer s.opportunities.mu.Unlock()
+	return append([]Opportunity(nil), s.opportunities.recent...)
+}
+
+// Formats a graph can be written in.
+const (
+	DOT  = "dot"
+	JSON = "json"
+)
+
+// Write writes the graph in a format, DOT or JSON.
+func (g *Graph) Write(w io.Writer, format string) error {
+	switch format {
+	case DOT:
+		return g.WriteDOT(w)
+	case JSON:
+		return g.WriteJSON(w)
+	}
+	return fmt.Errorf("unknown graph format %q, expected dot or json", format)
+}
+
+// WriteJSON writes the graph as an indented JSON object.
+func (g *Graph) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(g)
+}
+
+// WriteDOT writes the graph for Graphviz, with the nodes of each DEX in a
+// cluster. Edges are labeled with their rate and carry it, and the -log
+// weight, as rate and nlog attributes.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "digraph %q {\n", fmt.Sprintf("%s@%d", g.Chain, g.Block))
+	fmt.Fprintf(b, "  label=%q;\n  node [shape=box];\n", fmt.Sprintf("%s at block %d, %s", g.Chain, g.Block, g.Time.UTC().Format(time.RFC3339)))
+
+	var dexes []string
+	byDEX := make(map[string][]AssetDEX)
+	for _, node := range g.Nodes {
+		if byDEX[node.DEX] == nil {
+			dexes = append(dexes, node.DEX)
+		}
+		byDEX[node.DEX] = append(byDEX[node.DEX], node)
+	}
+	sort.Strings(dexes)
+	for i, dex := range dexes {
+		fmt.Fprintf(b, "  subgraph cluster_%d {\n    label=%q;\n", i, dex)
+		for _, node := range byDEX[dex] {
+			fmt.Fprintf(b, "    %q [label=%q];\n", node.String(), node.Asset)
+		}
+		b.WriteString("  }\n")
+	}
+	for _, e := range g.Edges {
+		style := ""
+		if e.From.Asset == e.To.Asset {
+			style = ", style=dashed" // between DEXes, at par
+		}
+		fmt.Fprintf(b, "  %q -> %q [label=%q, rate=%q, nlog=%q%s];\n", e.From.String(), e.To.String(),
+			strconv.FormatFloat(e.Rate, 'g', 6, 64), strconv.FormatFloat(e.Rate, 'g', -1, 64), strconv.FormatFloat(e.Weight, 'g', -1, 64), style)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (n AssetDEX) String() string {
+	return n.Asset + "@" + n.DEX
+}
@@ -3,67 +3,237 @@ package strategy
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"math"
 	"math/big"
+	"sync"
+	"time"
+	"bb/metrics"
 	"bb/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
 type AssetDEX struct {
-	Asset string
-	DEX   string
+	Asset string `json:"asset"`
+	DEX   string `json:"dex"`
 }
 
 func GetGasPrice(client *ethclient.Client) (*big.Int, error) {
 	return client.SuggestGasPrice(context.Background())
 }
 
-func findRate(swapEvents []types.SwapEvent, baseToken, quoteToken, dexName string) (*big.Float, error) {
-	for i := len(swapEvents) - 1; i >= 0; i-- {
-		event := swapEvents[i]
-		if event.Asset1Name == baseToken && event.Asset2Name == quoteToken && event.DEXName == dexName {
-			return event.AmountOut.Amount1, nil
-		}
-		if event.Asset1Name == quoteToken && event.Asset2Name == baseToken && event.DEXName == dexName {
-			return event.AmountOut.Amount2, nil
-		}
+// ChainReader is the chain access the strategy needs besides the pairs.
+type ChainReader interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+}
+
+// Holdings reports what the trading account can put into a trade.
+type Holdings interface {
+	// Available returns the whole tokens of an asset held and not committed
+	// to other trades.
+	Available(asset string) float64
+}
+
+// Strategy evaluates the pairs of one chain for arbitrage cycles.
+type Strategy struct {
+	Chain  string
+	Client ChainReader
+	Filter *Filter
+	Gas    GasModel
+	Log    *slog.Logger
+
+	// Holdings, if set, limits cycles to those starting from an asset held,
+	// sized to its available balance or to the depth of the cycle's pools,
+	// whichever is less.
+	Holdings Holdings
+
+	Metrics *metrics.Chain // nil records nothing
+
+	executions    executions
+	opportunities opportunities
+
+	pairsMu sync.RWMutex
+	pairs   []types.Pair
+
+	pricesMu sync.RWMutex
+	prices   map[string]float64
+	graph    *Graph
+}
+
+// Opportunity is a profitable cycle through the graph.
+type Opportunity struct {
+	Chain    string
+	Path     []AssetDEX
+	AmountIn float64      // whole tokens of the first asset; 0 without holdings
+	Rates    []float64    // rate of each step of Path, in whole tokens
+	Pairs    []types.Pair // pair each step of Path trades on; nil between DEXes
+	Block    uint64       // head block the graph was evaluated at
+	Found    time.Time
+	GasUnits uint64
+	GasPrice *big.Int
+}
+
+// NewStrategy creates the strategy for a chain. Filter may be nil to admit
+// every pair; a nil logger uses the default logger.
+func NewStrategy(chain string, client ChainReader, pairs []types.Pair, filter *Filter, gas GasModel, logger *slog.Logger) *Strategy {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if filter != nil {
+		filter.Log = logger
+	}
+	return &Strategy{
+		Chain:  chain,
+		Client: client,
+		pairs:  pairs,
+		Filter: filter,
+		Gas:    gas,
+		Log:    logger,
 	}
-	return nil, fmt.Errorf("  - %s %s/%s (NA / NA)", dexName, baseToken, quoteToken)
 }
 
-func TradeArbitrageStrategy(ctx context.Context, client *ethclient.Client, pairs []types.Pair, swapEvents []types.SwapEvent) {
-	log.Printf("Checking for arbitrage opportunities...")
+// Evaluate builds the graph from the latest swap events and returns the
+// first arbitrage cycle found, or nil. Everything is logged with ctx, which
+// carries the correlation ID of the triggering event.
+func (s *Strategy) Evaluate(ctx context.Context, swapEvents []types.SwapEvent) *Opportunity {
+	s.Log.DebugContext(ctx, "checking for arbitrage opportunities")
+	started := time.Now()
+
+	head, err := s.Client.BlockNumber(ctx)
+	if err != nil {
+		s.Log.WarnContext(ctx, "failed to fetch head block, using latest event", "err", err)
+		head = latestBlock(swapEvents)
+	}
+
+	matrix, hops := s.buildMatrix(ctx, swapEvents, head)
+	graph := newGraph(s.Chain, head, matrix)
+	s.pricesMu.Lock()
+	s.graph = graph
+	s.pricesMu.Unlock()
+	s.Filter.LogReport(ctx)
+	path, amountIn := s.detectArbitrageOpportunity(ctx, matrix)
+	if path != nil && amountIn > 0 {
+		if optimal := optimalAmountIn(path, matrix, hops); optimal < amountIn {
+			s.Log.InfoContext(ctx, "sized trade to pool depth", "available", amountIn, "amountIn", optimal)
+			amountIn = optimal
+		}
+	}
+	edges := 0
+	for _, to := range matrix {
+		edges += len(to)
+	}
+	s.Metrics.Evaluation(time.Since(started), len(matrix), edges)
+	if path == nil {
+		return nil
+	}
 
-	matrix := buildMatrix(pairs, swapEvents)
-	detectArbitrageOpportunity(matrix, client)
+	opportunity := &Opportunity{Chain: s.Chain, Path: path, AmountIn: amountIn, Block: head, Found: time.Now()}
+	for i := 0; i+1 < len(path); i++ {
+		rate, _ := matrix[path[i]][path[i+1]].Float64()
+		opportunity.Rates = append(opportunity.Rates, rate)
+		opportunity.Pairs = append(opportunity.Pairs, hops[step{path[i], path[i+1]}].pair)
+	}
+	opportunity.GasUnits, opportunity.GasPrice, err = s.Gas.Estimate(ctx, s.Client, len(path)-1)
+	if err != nil {
+		s.Log.WarnContext(ctx, "failed to estimate gas", "err", err)
+	} else {
+		s.Log.InfoContext(ctx, "estimated gas", "units", opportunity.GasUnits, "price", opportunity.GasPrice, "cost", formatWei(opportunity.GasCost()))
+	}
 
 	select {
 	case <-ctx.Done():
-		log.Println("TradeArbitrageStrategy interrupted before trade execution")
-		return
+		s.Log.InfoContext(ctx, "evaluation interrupted before trade execution")
+		return nil
 	default:
 	}
+	s.opportunities.add(*opportunity)
+	return opportunity
+}
+
+// Pairs returns the pairs the graph is built from.
+func (s *Strategy) Pairs() []types.Pair {
+	s.pairsMu.RLock()
+	defer s.pairsMu.RUnlock()
+	return append([]types.Pair(nil), s.pairs...)
+}
+
+// SetPairs replaces the pairs the graph is built from, from the next
+// evaluation on.
+func (s *Strategy) SetPairs(pairs []types.Pair) {
+	s.pairsMu.Lock()
+	defer s.pairsMu.Unlock()
+	s.pairs = append([]types.Pair(nil), pairs...)
+}
+
+// Graph returns the graph of the latest evaluation, or nil before the first.
+func (s *Strategy) Graph() *Graph {
+	s.pricesMu.RLock()
+	defer s.pricesMu.RUnlock()
+	return s.graph
+}
+
+// Prices returns the USD price of every asset valued by the latest
+// evaluation, derived from the stable assets through the swap events.
+func (s *Strategy) Prices() map[string]float64 {
+	s.pricesMu.RLock()
+	defer s.pricesMu.RUnlock()
+	prices := make(map[string]float64, len(s.prices))
+	for asset, price := range s.prices {
+		prices[asset] = price
+	}
+	return prices
+}
+
+// GasCost is the estimated gas cost of the cycle in wei.
+func (o *Opportunity) GasCost() *big.Int {
+	if o.GasPrice == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Mul(o.GasPrice, new(big.Int).SetUint64(o.GasUnits))
+}
+
+// step is an edge of the graph, from one node to another.
+type step struct {
+	from, to AssetDEX
 }
 
-func buildMatrix(pairs []types.Pair, swapEvents []types.SwapEvent) map[AssetDEX]map[AssetDEX]*big.Float {
+// hop is the pair an edge between two assets of a DEX trades on, and its
+// latest swap event, which the rate of the edge comes from.
+type hop struct {
+	pair  types.Pair
+	event *types.SwapEvent
+}
+
+// buildMatrix returns the rate of every edge of the graph and the hop behind
+// every edge between two assets. Edges between the nodes of an asset on
+// different DEXes have rate 1 and no hop.
+func (s *Strategy) buildMatrix(ctx context.Context, swapEvents []types.SwapEvent, head uint64) (map[AssetDEX]map[AssetDEX]*big.Float, map[step]hop) {
 	matrix := make(map[AssetDEX]map[AssetDEX]*big.Float)
+	hops := make(map[step]hop)
+	prices := s.Filter.usdPrices(swapEvents)
+	s.pricesMu.Lock()
+	s.prices = prices
+	s.pricesMu.Unlock()
 
-	for _, pair := range pairs {
+	for _, pair := range s.Pairs() {
 		p := pair
 
-		rateForward, err := findRate(swapEvents, p.Asset1(), p.Asset2(), p.DEX())
-		if err != nil {
-			log.Printf("%v", err)
+		event := latestEvent(swapEvents, p)
+		if event == nil {
+			s.Log.DebugContext(ctx, "pair left out", append(pairAttrs(p), "err", "no swap event yet")...)
 			continue
 		}
-		rateBackward, err := findRate(swapEvents, p.Asset2(), p.Asset1(), p.DEX())
-		if err != nil {
-			log.Printf("%v", err)
+		if head >= event.BlockNumber {
+			s.Metrics.PairLag(p.DEX(), p.Address(), head-event.BlockNumber)
+		}
+		if !s.Filter.Admit(ctx, p, event, prices, head) {
 			continue
 		}
 
-		log.Printf("  - %s %s/%s (%f / %f) (%s)", p.DEX(), p.Asset1(), p.Asset2(), rateForward, rateBackward, p.Address())
+		rateForward, rateBackward := event.AmountOut.Amount1, event.AmountOut.Amount2
+
+		s.Log.DebugContext(ctx, "pair rates", append(pairAttrs(p), "forward", rateForward, "backward", rateBackward)...)
 
 		fromAssetDEX := AssetDEX{p.Asset1(), p.DEX()}
 		toAssetDEX := AssetDEX{p.Asset2(), p.DEX()}
@@ -75,8 +245,16 @@ func buildMatrix(pairs []types.Pair, swapEvents []types.SwapEvent) map[AssetDEX]
 			matrix[toAssetDEX] = make(map[AssetDEX]*big.Float)
 		}
 
-		matrix[fromAssetDEX][toAssetDEX] = rateForward
-		matrix[toAssetDEX][fromAssetDEX] = rateBackward
+		// Pools of the same assets on one DEX share their edges, which take
+		// the best rate of them each way
+		if better(rateForward, matrix[fromAssetDEX][toAssetDEX]) {
+			matrix[fromAssetDEX][toAssetDEX] = rateForward
+			hops[step{fromAssetDEX, toAssetDEX}] = hop{pair: p, event: event}
+		}
+		if better(rateBackward, matrix[toAssetDEX][fromAssetDEX]) {
+			matrix[toAssetDEX][fromAssetDEX] = rateBackward
+			hops[step{toAssetDEX, fromAssetDEX}] = hop{pair: p, event: event}
+		}
 	}
 
 	for assetDEX1 := range matrix {
@@ -94,27 +272,129 @@ func buildMatrix(pairs []types.Pair, swapEvents []types.SwapEvent) map[AssetDEX]
 		}
 	}
 
-	return matrix
+	return matrix, hops
+}
+
+// better tells whether a rate makes a better edge than the current one: it
+// is an edge, and the current rate is none or a lower one.
+func better(rate, current *big.Float) bool {
+	if !isEdge(rate) {
+		return current == nil
+	}
+	return current == nil || !isEdge(current) || rate.Cmp(current) > 0
+}
+
+// isEdge tells whether a rate makes an edge: it is positive and finite as a
+// float64.
+func isEdge(rate *big.Float) bool {
+	r, _ := rate.Float64()
+	return r > 0 && !math.IsInf(r, 1)
 }
 
-func detectArbitrageOpportunity(matrix map[AssetDEX]map[AssetDEX]*big.Float, client *ethclient.Client) {
-	graph := buildGraph(matrix)
+// detectArbitrageOpportunity returns the first negative cycle in the graph
+// and the amount to put into it. With holdings, cycles are rotated to start
+// from the held asset worth the most, and cycles through no held asset are
+// passed over.
+func (s *Strategy) detectArbitrageOpportunity(ctx context.Context, matrix map[AssetDEX]map[AssetDEX]*big.Float) ([]AssetDEX, float64) {
+	graph, nodes := buildGraph(matrix)
 	distances, predecessors := bellmanFord(graph, len(graph))
 
+	unheld := 0
 	for i := range graph {
 		for j := range graph[i] {
-			if distances[j] > distances[i]+graph[i][j] {
-				log.Printf("Arbitrage opportunity detected!")
-				logPath(predecessors, i, j)
-				return
+			if distances[j] > distances[i]+graph[i][j]+cycleTolerance {
+				predecessors[j] = i
+				cycle := cyclePath(predecessors, j)
+				if cycle == nil {
+					continue
+				}
+				path := make([]AssetDEX, 0, len(cycle))
+				for _, k := range cycle {
+					path = append(path, nodes[k])
+				}
+				path, amountIn := s.startFromHeld(path)
+				if path == nil {
+					unheld++
+					continue
+				}
+				s.Log.InfoContext(ctx, "arbitrage opportunity detected", "path", fmt.Sprint(path), "amountIn", amountIn)
+				return path, amountIn
 			}
 		}
 	}
 
-	log.Println("No arbitrage opportunity detected.")
+	if unheld > 0 {
+		s.Log.InfoContext(ctx, "no arbitrage opportunity from held assets", "unheldCycles", unheld)
+		return nil, 0
+	}
+	s.Log.InfoContext(ctx, "no arbitrage opportunity detected")
+	return nil, 0
 }
 
-func buildGraph(matrix map[AssetDEX]map[AssetDEX]*big.Float) [][]float64 {
+// startFromHeld rotates a cycle, given from its start back to the start, to
+// start from the held asset with the most available value, and returns the
+// available amount of it. It returns nil if no asset of the cycle is held, and
+// the cycle unchanged if there are no holdings to go by.
+func (s *Strategy) startFromHeld(cycle []AssetDEX) ([]AssetDEX, float64) {
+	if s.Holdings == nil {
+		return cycle, 0
+	}
+	prices := s.Prices()
+
+	start, amountIn, value := -1, 0.0, -1.0
+	for k, node := range cycle[:len(cycle)-1] {
+		available := s.Holdings.Available(node.Asset)
+		if available <= 0 {
+			continue
+		}
+		if v := available * prices[node.Asset]; v > value {
+			start, amountIn, value = k, available, v
+		}
+	}
+	if start == -1 {
+		return nil, 0
+	}
+
+	nodes := cycle[:len(cycle)-1]
+	rotated := make([]AssetDEX, 0, len(cycle))
+	rotated = append(rotated, nodes[start:]...)
+	rotated = append(rotated, nodes[:start]...)
+	return append(rotated, nodes[start]), amountIn
+}
+
+// optimalAmountIn is the amount of the first asset of a cycle that gains
+// the most. Every hop is taken for a constant-product pool of its rate, whose
+// input reserve is that of its latest event: out(x) = rate·x/(1 + x/reserve).
+// The cycle then returns A·x/(1 + B·x), which gains the most at
+// x = (√A - 1)/B. Without any known reserve it is +Inf.
+func optimalAmountIn(path []AssetDEX, matrix map[AssetDEX]map[AssetDEX]*big.Float, hops map[step]hop) float64 {
+	a, b := 1.0, 0.0
+	for i := 0; i+1 < len(path); i++ {
+		rate, _ := matrix[path[i]][path[i+1]].Float64()
+		if h, ok := hops[step{path[i], path[i+1]}]; ok {
+			reserve := h.event.Reserve1
+			if path[i].Asset != h.event.Asset1Name {
+				reserve = h.event.Reserve2
+			}
+			if reserve != nil && reserve.Sign() > 0 {
+				r, _ := reserve.Float64()
+				b += a / r
+			}
+		}
+		a *= rate
+	}
+	if a <= 1 {
+		return 0
+	}
+	if b == 0 {
+		return math.Inf(1)
+	}
+	return (math.Sqrt(a) - 1) / b
+}
+
+// buildGraph turns the matrix into an adjacency matrix of -log(rate) weights.
+// nodes maps each index back to its AssetDEX.
+func buildGraph(matrix map[AssetDEX]map[AssetDEX]*big.Float) ([][]float64, []AssetDEX) {
 	n := len(matrix)
 	graph := make([][]float64, n)
 	for i := range graph {
@@ -124,44 +404,53 @@ func buildGraph(matrix map[AssetDEX]map[AssetDEX]*big.Float) [][]float64 {
 		}
 	}
 
-	nodes := make(map[AssetDEX]int)
-	i := 0
+	index := make(map[AssetDEX]int)
+	nodes := make([]AssetDEX, 0, n)
 	for assetDEX1 := range matrix {
-		nodes[assetDEX1] = i
-		i++
+		index[assetDEX1] = len(nodes)
+		nodes = append(nodes, assetDEX1)
 	}
 
 	for assetDEX1, edges := range matrix {
 		for assetDEX2, rate := range edges {
-			graph[nodes[assetDEX1]][nodes[assetDEX2]] = negativeLog(rate)
+			graph[index[assetDEX1]][index[assetDEX2]] = negativeLog(rate)
 		}
 	}
 
-	return graph
+	return graph, nodes
 }
 
+// negativeLog returns the weight of an edge of the given rate. Rates that
+// are not positive and finite, such as those of an emptied pool, make no
+// edge.
 func negativeLog(rate *big.Float) float64 {
 	rateFloat, _ := rate.Float64()
-	if rateFloat <= 0 {
-		log.Printf("Invalid rate value for logarithm: %f", rateFloat)
+	if rateFloat <= 0 || math.IsInf(rateFloat, 1) {
+		slog.Warn("invalid rate value for logarithm", "rate", rateFloat)
 		return math.Inf(1) // Treat invalid rates as infinite cost
 	}
 	return -math.Log(rateFloat)
 }
 
+// cycleTolerance is the least decrease of a distance that counts, so that
+// cycles gaining nothing but the rounding errors of the logarithms, such as
+// a rate and its inverse, are not taken for arbitrage.
+const cycleTolerance = 1e-9
+
+// bellmanFord relaxes the edges from a virtual source joined to every node,
+// so that the negative cycles of every component of the graph are found,
+// not only those reachable from node 0.
 func bellmanFord(graph [][]float64, n int) ([]float64, []int) {
 	distances := make([]float64, n)
 	predecessors := make([]int, n)
 	for i := range distances {
-		distances[i] = math.Inf(1)
 		predecessors[i] = -1
 	}
-	distances[0] = 0
 
 	for k := 0; k < n-1; k++ {
 		for i := 0; i < n; i++ {
 			for j := 0; j < n; j++ {
-				if distances[j] > distances[i] + graph[i][j] {
+				if distances[j] > distances[i] + graph[i][j] + cycleTolerance {
 					distances[j] = distances[i] + graph[i][j]
 					predecessors[j] = i
 				}
@@ -172,15 +461,25 @@ func bellmanFord(graph [][]float64, n int) ([]float64, []int) {
 	return distances, predecessors
 }
 
-func logPath(predecessors []int, start, end int) {
-	path := []int{end}
-	for predecessors[end] != -1 {
-		path = append([]int{predecessors[end]}, path...)
+// cyclePath returns the negative cycle that node end was relaxed through, as
+// node indexes from its start back to the start again, or nil if end is not
+// reachable from a cycle. Walking n predecessors first guarantees the walk is
+// inside the cycle.
+func cyclePath(predecessors []int, end int) []int {
+	for k := 0; k < len(predecessors); k++ {
+		if predecessors[end] == -1 {
+			return nil
+		}
 		end = predecessors[end]
 	}
-	log.Printf("Arbitrage path: %v", path)
+
+	path := []int{end}
+	for node := predecessors[end]; node != -1 && node != end; node = predecessors[node] {
+		path = append([]int{node}, path...)
+	}
+	return append([]int{end}, path...)
 }
 
 func Announce() {
-	log.Printf("x-dex x-token arb")
+	slog.Info("x-dex x-token arb")
 }
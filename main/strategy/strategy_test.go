@@ -0,0 +1,203 @@
+package strategy
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"bb/testutil"
+	"bb/types"
+)
+
+// newTestStrategy evaluates pools at a fixed head and gas price of 10 gwei.
+func newTestStrategy(pools ...*testutil.Pool) *Strategy {
+	pairs := make([]types.Pair, len(pools))
+	for i, pool := range pools {
+		pairs[i] = pool
+	}
+	gas := GasModel{BaseGas: 100000, GasPerHop: 50000}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewStrategy("test", testutil.NewClock(100, big.NewInt(10e9)), pairs, nil, gas, logger)
+}
+
+func snapshots(t *testing.T, pools ...*testutil.Pool) []types.SwapEvent {
+	t.Helper()
+	events := make([]types.SwapEvent, len(pools))
+	for i, pool := range pools {
+		event, err := pool.Snapshot(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		events[i] = event
+	}
+	return events
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name      string
+		sushiUSDC float64 // per 100 ETH, against 200000 on uni
+		holdings  testutil.Holdings
+		found     bool
+		start     string  // first asset of the cycle, any if empty
+		amountIn  float64 // -1 for the amount that gains the most
+	}{
+		{name: "fair", sushiUSDC: 200000},
+		{name: "within fees", sushiUSDC: 201000},
+		{name: "mispriced", sushiUSDC: 210000, found: true},
+		{name: "from held asset", sushiUSDC: 210000, holdings: testutil.Holdings{"USDC": 1000}, found: true, start: "USDC", amountIn: 1000},
+		{name: "sized to pool depth", sushiUSDC: 210000, holdings: testutil.Holdings{"USDC": 5000}, found: true, start: "USDC", amountIn: -1},
+		{name: "nothing held", sushiUSDC: 210000, holdings: testutil.Holdings{"DAI": 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uni := testutil.NewPool("uni", "ETH", "USDC", testutil.Amount(100, 18), testutil.Amount(200000, 18))
+			sushi := testutil.NewPool("sushi", "ETH", "USDC", testutil.Amount(100, 18), testutil.Amount(tt.sushiUSDC, 18))
+			s := newTestStrategy(uni, sushi)
+			if tt.holdings != nil {
+				s.Holdings = tt.holdings
+			}
+
+			o := s.Evaluate(context.Background(), snapshots(t, uni, sushi))
+			if !tt.found {
+				if o != nil {
+					t.Fatalf("found %v", o.Path)
+				}
+				return
+			}
+			if o == nil {
+				t.Fatal("no opportunity")
+			}
+			if (tt.start != "" && o.Path[0].Asset != tt.start) || o.Path[0] != o.Path[len(o.Path)-1] || (tt.amountIn >= 0 && o.AmountIn != tt.amountIn) {
+				t.Errorf("path %v amountIn %g", o.Path, o.AmountIn)
+			}
+			if tt.amountIn < 0 {
+				gain := cycleGain(t, o, o.AmountIn)
+				for _, x := range []float64{0.9, 0.99, 1.01, 1.1} {
+					if other := cycleGain(t, o, x*o.AmountIn); other > gain {
+						t.Errorf("%g in gains %g, %g in gains %g", o.AmountIn, gain, x*o.AmountIn, other)
+					}
+				}
+			}
+			product := 1.0
+			for _, rate := range o.Rates {
+				product *= rate
+			}
+			if product <= 1 {
+				t.Errorf("rates %v multiply to %g", o.Rates, product)
+			}
+			if o.Block != 100 || o.GasUnits != 100000+50000*uint64(len(o.Path)-1) || o.GasPrice.Cmp(big.NewInt(10e9)) != 0 {
+				t.Errorf("block %d, gas %d at %s", o.Block, o.GasUnits, o.GasPrice)
+			}
+		})
+	}
+}
+
+// cycleGain quotes amountIn whole tokens through the pairs of an
+// opportunity's steps and returns what it gains, in whole tokens of 18
+// decimals.
+func cycleGain(t *testing.T, o *Opportunity, amountIn float64) float64 {
+	t.Helper()
+	amount := testutil.Amount(amountIn, 18)
+	for i, pair := range o.Pairs {
+		if pair == nil {
+			continue
+		}
+		out, err := pair.Quote(o.Path[i].Asset, amount)
+		if err != nil {
+			t.Fatal(err)
+		}
+		amount = out
+	}
+	out, _ := new(big.Float).Quo(new(big.Float).SetInt(amount), big.NewFloat(1e18)).Float64()
+	return out - amountIn
+}
+
+// TestEvaluateMonitoredSwap finds the cycle a swap opens, from the event the
+// pool's monitor sends for it.
+func TestEvaluateMonitoredSwap(t *testing.T) {
+	uni := testutil.NewPool("uni", "ETH", "USDC", testutil.Amount(100, 18), testutil.Amount(200000, 18))
+	sushi := testutil.NewPool("sushi", "ETH", "USDC", testutil.Amount(100, 18), testutil.Amount(200000, 18))
+	s := newTestStrategy(uni, sushi)
+	events := snapshots(t, uni, sushi)
+	if o := s.Evaluate(context.Background(), events); o != nil {
+		t.Fatalf("found %v before the swap", o.Path)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	swaps := make(chan types.SwapEvent)
+	go sushi.Monitor(ctx, swaps)
+	<-sushi.Monitored(1)
+
+	// Someone buys ETH on sushi, leaving it dearer there than on uni
+	if _, err := sushi.Trade("USDC", testutil.Amount(10000, 18)); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case event := <-swaps:
+		events = append(events, event)
+	case <-time.After(time.Second):
+		t.Fatal("no swap event")
+	}
+
+	o := s.Evaluate(context.Background(), events)
+	if o == nil {
+		t.Fatal("no opportunity after the swap")
+	}
+	// The cycle sells ETH on sushi and buys it back on uni
+	sells, buys := false, false
+	for i, node := range o.Path[:len(o.Path)-1] {
+		next := o.Path[i+1]
+		sells = sells || node == AssetDEX{"ETH", "sushi"} && next == AssetDEX{"USDC", "sushi"}
+		buys = buys || node == AssetDEX{"USDC", "uni"} && next == AssetDEX{"ETH", "uni"}
+	}
+	if !sells || !buys {
+		t.Errorf("path %v", o.Path)
+	}
+}
+
+// TestEvaluateDuplicatePools lists ETH/USDC twice on uni, once with ETH
+// dearer: ETH is sold on the dearer pool and bought on the other, whichever
+// of the two is listed first.
+func TestEvaluateDuplicatePools(t *testing.T) {
+	for _, mispricedFirst := range []bool{false, true} {
+		fair := testutil.NewPool("uni", "ETH", "USDC", testutil.Amount(100, 18), testutil.Amount(200000, 18))
+		mispriced := testutil.NewPool("uni", "ETH", "USDC", testutil.Amount(100, 18), testutil.Amount(210000, 18))
+		mispriced.Addr = "0x0000000000000000000000000000000000000002"
+		sushi := testutil.NewPool("sushi", "ETH", "USDC", testutil.Amount(100, 18), testutil.Amount(200000, 18))
+		pools := []*testutil.Pool{fair, mispriced, sushi}
+		if mispricedFirst {
+			pools[0], pools[1] = mispriced, fair
+		}
+		s := newTestStrategy(pools...)
+
+		o := s.Evaluate(context.Background(), snapshots(t, pools...))
+		if o == nil {
+			t.Fatalf("mispriced first %v: no opportunity", mispricedFirst)
+		}
+		if len(o.Pairs) != len(o.Path)-1 {
+			t.Fatalf("%d pairs for path %v", len(o.Pairs), o.Path)
+		}
+		for i, pair := range o.Pairs {
+			from, to := o.Path[i], o.Path[i+1]
+			switch {
+			case from.Asset == to.Asset:
+				if pair != nil {
+					t.Errorf("step %v > %v trades on %s", from, to, pair.Address())
+				}
+			case from.DEX == "uni" && from.Asset == "ETH":
+				if pair != types.Pair(mispriced) {
+					t.Errorf("step %v > %v trades on %v", from, to, pair)
+				}
+			case from.DEX == "uni":
+				if pair != types.Pair(fair) {
+					t.Errorf("step %v > %v trades on %v", from, to, pair)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package testutil
+
+import (
+	"context"
+	"math/big"
+	"sync"
+)
+
+// Clock is the chain access of a strategy, with a head block and gas price
+// set by the test.
+type Clock struct {
+	mu       sync.Mutex
+	head     uint64
+	gasPrice *big.Int
+}
+
+// NewClock creates a clock at a head block and gas price in wei.
+func NewClock(head uint64, gasPrice *big.Int) *Clock {
+	return &Clock{head: head, gasPrice: new(big.Int).Set(gasPrice)}
+}
+
+// SetHead moves the head block.
+func (c *Clock) SetHead(head uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.head = head
+}
+
+func (c *Clock) BlockNumber(ctx context.Context) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.head, nil
+}
+
+func (c *Clock) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return new(big.Int).Set(c.gasPrice), nil
+}
+
+// Holdings are balances available to trades, in whole tokens by asset.
+type Holdings map[string]float64
+
+func (h Holdings) Available(asset string) float64 {
+	return h[asset]
+}
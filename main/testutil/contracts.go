@@ -0,0 +1,73 @@
+package testutil
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// The contracts deployed on a simulated Chain are compiled from the sources
+// in contracts/: Uniswap V2 core ported to Solidity 0.8, a mintable ERC-20
+// and a fixed-price stand-in for a Uniswap V3 pool. combined.json is the
+// output of solc 0.8.21.
+//
+//go:generate sh -c "cd contracts && solc --optimize --evm-version paris --combined-json abi,bin MockUniswapV3Pool.sol TestToken.sol UniswapV2.sol > combined.json"
+//go:embed contracts/combined.json
+var combinedJSON []byte
+
+// Compiled is a contract's ABI and creation code.
+type Compiled struct {
+	ABI abi.ABI
+	Bin []byte
+}
+
+var compiled = sync.OnceValues(func() (map[string]Compiled, error) {
+	var combined struct {
+		Contracts map[string]struct {
+			ABI json.RawMessage `json:"abi"`
+			Bin string          `json:"bin"`
+		} `json:"contracts"`
+	}
+	if err := json.Unmarshal(combinedJSON, &combined); err != nil {
+		return nil, err
+	}
+	contracts := make(map[string]Compiled, len(combined.Contracts))
+	for key, c := range combined.Contracts {
+		parsed, err := abi.JSON(strings.NewReader(string(c.ABI)))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", key, err)
+		}
+		name := key[strings.LastIndex(key, ":")+1:]
+		contracts[name] = Compiled{ABI: parsed, Bin: common.FromHex(c.Bin)}
+	}
+	return contracts, nil
+})
+
+// Contract returns a compiled contract by name, e.g. "UniswapV2Pair".
+func Contract(name string) (Compiled, error) {
+	contracts, err := compiled()
+	if err != nil {
+		return Compiled{}, err
+	}
+	c, ok := contracts[name]
+	if !ok {
+		return Compiled{}, fmt.Errorf("no compiled contract %s", name)
+	}
+	return c, nil
+}
+
+// PairInitCodeHash is the init code hash of the pairs created by a
+// UniswapV2Factory deployed on a simulated chain, to derive their addresses.
+func PairInitCodeHash() common.Hash {
+	pair, err := Contract("UniswapV2Pair")
+	if err != nil {
+		panic(err)
+	}
+	return crypto.Keccak256Hash(pair.Bin)
+}
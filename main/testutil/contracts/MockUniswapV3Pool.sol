@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.21;
+
+interface IERC20 {
+    function balanceOf(address account) external view returns (uint);
+    function transfer(address to, uint value) external returns (bool);
+}
+
+interface IUniswapV3SwapCallback {
+    function uniswapV3SwapCallback(int amount0Delta, int amount1Delta, bytes calldata data) external;
+}
+
+// MockUniswapV3Pool swaps exact inputs at a fixed price, in token1 per token0
+// scaled by 1e18, out of the tokens it holds. Like a Uniswap V3 pool it pays
+// the output first and takes the input through the caller's swap callback.
+contract MockUniswapV3Pool {
+    address public immutable token0;
+    address public immutable token1;
+    uint public immutable price;
+
+    constructor(address _token0, address _token1, uint _price) {
+        token0 = _token0;
+        token1 = _token1;
+        price = _price;
+    }
+
+    function swap(address recipient, bool zeroForOne, int amountSpecified, uint160, bytes calldata data) external returns (int amount0, int amount1) {
+        require(amountSpecified > 0, "MockUniswapV3Pool: exact input only");
+        uint amountIn = uint(amountSpecified);
+        uint amountOut = zeroForOne ? amountIn * price / 1e18 : amountIn * 1e18 / price;
+        (address tokenIn, address tokenOut) = zeroForOne ? (token0, token1) : (token1, token0);
+        (amount0, amount1) = zeroForOne ? (int(amountIn), -int(amountOut)) : (-int(amountOut), int(amountIn));
+
+        IERC20(tokenOut).transfer(recipient, amountOut);
+        uint before = IERC20(tokenIn).balanceOf(address(this));
+        IUniswapV3SwapCallback(msg.sender).uniswapV3SwapCallback(amount0, amount1, data);
+        require(IERC20(tokenIn).balanceOf(address(this)) >= before + amountIn, "MockUniswapV3Pool: input not paid");
+    }
+}
@@ -0,0 +1,294 @@
+// Package testutil provides pairs and chains to exercise the strategy, the
+// pipeline and the pair integrations offline: an in-memory V2 pool, a pair
+// that plays scripted swap events, a fake chain reader and a simulated chain
+// that Uniswap V2 and token contracts are deployed on.
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"bb/executor"
+	"bb/types"
+)
+
+// Pool is an in-memory Uniswap V2 pool. It quotes and takes trades with the
+// pair's constant-product formula and fee, and every change of its reserves
+// is one block, seen by its monitors as a swap event. Its SwapCalls are those
+// of a V2 pair at its address, which the pool itself does not see. The
+// exported fields may be changed before the pool is used.
+type Pool struct {
+	Name     string // DEX
+	Addr     string
+	Assets   [2]string
+	Decimals [2]int64
+	FeePips  int64 // swap fee in hundredths of a basis point
+
+	mu       sync.Mutex
+	reserves [2]*big.Int // raw units
+	block    uint64
+	monitors []*queue
+	waiters  []monitorWaiter
+}
+
+// monitorWaiter is a channel to close once a pool has n monitors.
+type monitorWaiter struct {
+	n    int
+	done chan struct{}
+}
+
+// NewPool creates a pool of two 18 decimal assets with the given raw
+// reserves and a 0.3% fee, at an address derived from the DEX and assets.
+func NewPool(dex, asset1, asset2 string, reserve1, reserve2 *big.Int) *Pool {
+	return &Pool{
+		Name:     dex,
+		Addr:     pairAddress(dex, asset1, asset2).Hex(),
+		Assets:   [2]string{asset1, asset2},
+		Decimals: [2]int64{18, 18},
+		FeePips:  3000,
+		reserves: [2]*big.Int{new(big.Int).Set(reserve1), new(big.Int).Set(reserve2)},
+		block:    1,
+	}
+}
+
+// pairAddress is the address of the test pair of two assets on a DEX.
+func pairAddress(dex, asset1, asset2 string) common.Address {
+	return common.BytesToAddress(crypto.Keccak256([]byte(dex + "|" + asset1 + "|" + asset2)))
+}
+
+func (p *Pool) Asset1() string  { return p.Assets[0] }
+func (p *Pool) Asset2() string  { return p.Assets[1] }
+func (p *Pool) DEX() string     { return p.Name }
+func (p *Pool) Address() string { return p.Addr }
+
+// Reserves returns the raw reserves of asset1 and asset2.
+func (p *Pool) Reserves() (*big.Int, *big.Int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return new(big.Int).Set(p.reserves[0]), new(big.Int).Set(p.reserves[1])
+}
+
+// Block returns the block of the latest change of the reserves.
+func (p *Pool) Block() uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.block
+}
+
+// SetReserves replaces the raw reserves, as a mint, burn or sync would.
+func (p *Pool) SetReserves(reserve1, reserve2 *big.Int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.reserves = [2]*big.Int{new(big.Int).Set(reserve1), new(big.Int).Set(reserve2)}
+	p.changed()
+}
+
+// Quote returns what swapping amountIn raw units of assetIn yields.
+func (p *Pool) Quote(assetIn string, amountIn *big.Int) (*big.Int, error) {
+	in, err := p.index(assetIn)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.amountOut(in, amountIn)
+}
+
+// Trade swaps amountIn raw units of assetIn into the pool, as another trader
+// would, and returns the amount out.
+func (p *Pool) Trade(assetIn string, amountIn *big.Int) (*big.Int, error) {
+	in, err := p.index(assetIn)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	amountOut, err := p.amountOut(in, amountIn)
+	if err != nil {
+		return nil, err
+	}
+	p.reserves[in] = new(big.Int).Add(p.reserves[in], amountIn)
+	p.reserves[1-in] = new(big.Int).Sub(p.reserves[1-in], amountOut)
+	p.changed()
+	return amountOut, nil
+}
+
+// SwapCalls returns the calls a V2 pair at the pool's address would take:
+// the executor's swapV2 at the pool's fee. The pool itself does not see them.
+func (p *Pool) SwapCalls(exec, tokenIn common.Address, assetIn string, minOut *big.Int) ([]types.Call, error) {
+	if _, err := p.index(assetIn); err != nil {
+		return nil, err
+	}
+	call, err := executor.SwapV2(exec, common.HexToAddress(p.Addr), tokenIn, p.FeePips, minOut)
+	if err != nil {
+		return nil, err
+	}
+	return []types.Call{call}, nil
+}
+
+// Snapshot describes the pool's current reserves as a swap event.
+func (p *Pool) Snapshot(ctx context.Context) (types.SwapEvent, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.event(), nil
+}
+
+// Monitor sends a swap event for every change of the reserves from now on,
+// until ctx is cancelled. Events are queued rather than dropped while the
+// receiver is busy.
+func (p *Pool) Monitor(ctx context.Context, swapEventChan chan<- types.SwapEvent) error {
+	q := &queue{wake: make(chan struct{}, 1)}
+	p.mu.Lock()
+	p.monitors = append(p.monitors, q)
+	waiters := p.waiters[:0]
+	for _, w := range p.waiters {
+		if len(p.monitors) >= w.n {
+			close(w.done)
+		} else {
+			waiters = append(waiters, w)
+		}
+	}
+	p.waiters = waiters
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		for i, m := range p.monitors {
+			if m == q {
+				p.monitors = append(p.monitors[:i], p.monitors[i+1:]...)
+				break
+			}
+		}
+		p.mu.Unlock()
+	}()
+
+	for {
+		for _, event := range q.take() {
+			select {
+			case swapEventChan <- event:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+		select {
+		case <-q.wake:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// Monitored returns a channel closed once n monitors are registered, so
+// that changes made after it is closed are seen by them.
+func (p *Pool) Monitored(n int) <-chan struct{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	done := make(chan struct{})
+	if len(p.monitors) >= n {
+		close(done)
+	} else {
+		p.waiters = append(p.waiters, monitorWaiter{n: n, done: done})
+	}
+	return done
+}
+
+// changed moves to the next block and queues the new state for the
+// monitors. p.mu must be held.
+func (p *Pool) changed() {
+	p.block++
+	event := p.event()
+	for _, q := range p.monitors {
+		q.put(event)
+	}
+}
+
+// event describes the reserves as the V2 pair integration does: the rates
+// are the marginal rates after the fee, in whole tokens. p.mu must be held.
+func (p *Pool) event() types.SwapEvent {
+	feeComplement := big.NewFloat(float64(1000000 - p.FeePips))
+	reserve0 := new(big.Float).SetInt(p.reserves[0])
+	reserve1 := new(big.Float).SetInt(p.reserves[1])
+
+	forward := new(big.Float).Quo(reserve1, new(big.Float).Add(reserve0, big.NewFloat(1)))
+	backward := new(big.Float).Quo(big.NewFloat(1), forward)
+	forward.Mul(scale(forward, p.Decimals[0]-p.Decimals[1]-6), feeComplement)
+	backward.Mul(scale(backward, p.Decimals[1]-p.Decimals[0]-6), feeComplement)
+
+	return types.SwapEvent{
+		DEXName:     p.Name,
+		Asset1Name:  p.Assets[0],
+		Asset2Name:  p.Assets[1],
+		Address:     p.Addr,
+		AmountOut:   types.AmountOut{Amount1: forward, Amount2: backward},
+		Reserve1:    scale(reserve0, -p.Decimals[0]),
+		Reserve2:    scale(reserve1, -p.Decimals[1]),
+		BlockNumber: p.block,
+	}
+}
+
+// amountOut is the pair's getAmountOut from reserve in to the other. p.mu
+// must be held.
+func (p *Pool) amountOut(in int, amountIn *big.Int) (*big.Int, error) {
+	amountInWithFee := new(big.Int).Mul(amountIn, big.NewInt(1000000-p.FeePips))
+	numerator := new(big.Int).Mul(amountInWithFee, p.reserves[1-in])
+	denominator := new(big.Int).Add(new(big.Int).Mul(p.reserves[in], big.NewInt(1000000)), amountInWithFee)
+	if denominator.Sign() == 0 {
+		return nil, fmt.Errorf("%s %s/%s has no liquidity", p.Name, p.Assets[0], p.Assets[1])
+	}
+	return numerator.Quo(numerator, denominator), nil
+}
+
+func (p *Pool) index(asset string) (int, error) {
+	switch asset {
+	case p.Assets[0]:
+		return 0, nil
+	case p.Assets[1]:
+		return 1, nil
+	}
+	return 0, fmt.Errorf("%s is not traded on %s %s/%s", asset, p.Name, p.Assets[0], p.Assets[1])
+}
+
+// scale returns value times 10^x.
+func scale(value *big.Float, x int64) *big.Float {
+	factor := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(max(x, -x)), nil))
+	if x < 0 {
+		return new(big.Float).Quo(value, factor)
+	}
+	return new(big.Float).Mul(value, factor)
+}
+
+// queue holds the events a monitor has yet to send.
+type queue struct {
+	mu     sync.Mutex
+	events []types.SwapEvent
+	wake   chan struct{}
+}
+
+func (q *queue) put(event types.SwapEvent) {
+	q.mu.Lock()
+	q.events = append(q.events, event)
+	q.mu.Unlock()
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *queue) take() []types.SwapEvent {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	events := q.events
+	q.events = nil
+	return events
+}
+
+// Amount converts whole tokens to raw units of a token with the given
+// decimals.
+func Amount(whole float64, decimals int64) *big.Int {
+	raw, _ := scale(big.NewFloat(whole), decimals).Int(nil)
+	return raw
+}
@@ -0,0 +1,82 @@
+package testutil
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"bb/types"
+)
+
+func TestPoolTrade(t *testing.T) {
+	pool := NewPool("uni", "ETH", "USDC", Amount(100, 18), Amount(200000, 18))
+	quoted, err := pool.Quote("ETH", Amount(1, 18))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan types.SwapEvent)
+	go pool.Monitor(ctx, events)
+	<-pool.Monitored(1)
+
+	// A trade pays out what it was quoted
+	out, err := pool.Trade("ETH", Amount(1, 18))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Cmp(quoted) != 0 {
+		t.Fatalf("traded for %s, quoted %s", out, quoted)
+	}
+	reserve1, reserve2 := pool.Reserves()
+	if want := Amount(101, 18); reserve1.Cmp(want) != 0 {
+		t.Errorf("reserve1 %s, want %s", reserve1, want)
+	}
+	if want := new(big.Int).Sub(Amount(200000, 18), quoted); reserve2.Cmp(want) != 0 {
+		t.Errorf("reserve2 %s, want %s", reserve2, want)
+	}
+
+	select {
+	case event := <-events:
+		if event.BlockNumber != pool.Block() {
+			t.Errorf("event at block %d, pool at %d", event.BlockNumber, pool.Block())
+		}
+		rate, _ := event.AmountOut.Amount1.Float64()
+		if rate >= 2000*0.997 || rate < 1900 {
+			t.Errorf("rate after selling ETH %g", rate)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no swap event")
+	}
+
+	if _, err := pool.Trade("DAI", Amount(1, 18)); err == nil {
+		t.Error("traded an asset the pool does not hold")
+	}
+}
+
+func TestScript(t *testing.T) {
+	script := NewScript("uni", "ETH", "USDC").Then(0, 2000, 1).Then(time.Millisecond, 2010, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan types.SwapEvent)
+	done := make(chan struct{})
+	go func() {
+		script.Monitor(ctx, events)
+		close(done)
+	}()
+
+	for i, want := range []float64{2000, 2010} {
+		event := <-events
+		if rate, _ := event.AmountOut.Amount1.Float64(); rate != want || event.BlockNumber != uint64(i+1) || event.Address != script.Address() {
+			t.Errorf("event %d: %+v", i, event)
+		}
+	}
+	select {
+	case <-done:
+		t.Fatal("monitor returned before ctx was cancelled")
+	case <-time.After(10 * time.Millisecond):
+	}
+	cancel()
+	<-done
+}
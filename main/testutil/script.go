@@ -0,0 +1,91 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"bb/types"
+)
+
+// Script is a pair whose monitor plays a fixed sequence of swap events, to
+// drive a strategy or a pipeline through a scenario. It cannot be quoted or
+// traded.
+type Script struct {
+	Name   string // DEX
+	Addr   string
+	Assets [2]string
+	Steps  []Step
+}
+
+// Step is one event of a Script.
+type Step struct {
+	Delay time.Duration // before the event is sent
+	Event types.SwapEvent
+}
+
+// NewScript creates a script without steps for a pair at an address derived
+// from the DEX and assets.
+func NewScript(dex, asset1, asset2 string) *Script {
+	pool := NewPool(dex, asset1, asset2, new(big.Int), new(big.Int))
+	return &Script{Name: dex, Addr: pool.Addr, Assets: pool.Assets}
+}
+
+// Then appends a step sending the pair's rate at a block after delay.
+func (s *Script) Then(delay time.Duration, rate float64, block uint64) *Script {
+	event := RateEvent(s.Name, s.Assets[0], s.Assets[1], rate, block)
+	event.Address = s.Addr
+	s.Steps = append(s.Steps, Step{Delay: delay, Event: event})
+	return s
+}
+
+func (s *Script) Asset1() string  { return s.Assets[0] }
+func (s *Script) Asset2() string  { return s.Assets[1] }
+func (s *Script) DEX() string     { return s.Name }
+func (s *Script) Address() string { return s.Addr }
+
+// Monitor sends the steps' events in order, then waits for ctx to be
+// cancelled, as a monitor of a pair that stopped trading would.
+func (s *Script) Monitor(ctx context.Context, swapEventChan chan<- types.SwapEvent) error {
+	for _, step := range s.Steps {
+		select {
+		case <-time.After(step.Delay):
+		case <-ctx.Done():
+			return nil
+		}
+		select {
+		case swapEventChan <- step.Event:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func (s *Script) SwapCalls(executor, tokenIn common.Address, assetIn string, minOut *big.Int) ([]types.Call, error) {
+	return nil, fmt.Errorf("scripted pair %s cannot be traded", s.Addr)
+}
+
+func (s *Script) Quote(assetIn string, amountIn *big.Int) (*big.Int, error) {
+	return nil, fmt.Errorf("scripted pair %s cannot be quoted", s.Addr)
+}
+
+// RateEvent is a swap event of a pair trading at rate asset2 per asset1 both
+// ways, without fee, with a million asset1 of liquidity. It comes from the
+// address NewPool and NewScript give the pair.
+func RateEvent(dex, asset1, asset2 string, rate float64, block uint64) types.SwapEvent {
+	return types.SwapEvent{
+		DEXName:     dex,
+		Address:     pairAddress(dex, asset1, asset2).Hex(),
+		Asset1Name:  asset1,
+		Asset2Name:  asset2,
+		AmountOut:   types.AmountOut{Amount1: big.NewFloat(rate), Amount2: big.NewFloat(1 / rate)},
+		Reserve1:    big.NewFloat(1e6),
+		Reserve2:    big.NewFloat(1e6 * rate),
+		BlockNumber: block,
+	}
+}
@@ -0,0 +1,190 @@
+package testutil
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+
+	"bb/executor"
+)
+
+// Chain is a simulated chain with one funded account. Blocks are only mined
+// by Commit.
+type Chain struct {
+	Backend *simulated.Backend
+	Client  simulated.Client
+	Key     *ecdsa.PrivateKey
+	Auth    *bind.TransactOpts // signs as the funded account
+}
+
+// PairState is the initial state of a pair deployed on a Chain: its tokens
+// and the reserves of each, which are minted to the pair as its first
+// liquidity. The pair is created by Factory, or by a new factory if Factory
+// is zero.
+type PairState struct {
+	Factory            common.Address
+	Token0, Token1     common.Address
+	Reserve0, Reserve1 *big.Int
+}
+
+// NewChain starts a simulated chain, closed when the test ends.
+func NewChain(t testing.TB) *Chain {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	balance := new(big.Int).Mul(big.NewInt(1000), big.NewInt(params.Ether))
+	backend := simulated.NewBackend(ethtypes.GenesisAlloc{from: {Balance: balance}})
+	t.Cleanup(func() { backend.Close() })
+
+	client := backend.Client()
+	chainID, err := client.ChainID(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth, err := bind.NewKeyedTransactorWithChainID(key, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Chain{Backend: backend, Client: client, Key: key, Auth: auth}
+}
+
+// Commit mines the pending transactions into a block and returns its number.
+func (c *Chain) Commit(t testing.TB) uint64 {
+	t.Helper()
+	hash := c.Backend.Commit()
+	header, err := c.Client.HeaderByHash(context.Background(), hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return header.Number.Uint64()
+}
+
+// Deploy deploys a contract compiled from contracts/ with constructor
+// arguments, mines it and returns its address and binding.
+func (c *Chain) Deploy(t testing.TB, name string, args ...interface{}) (common.Address, *bind.BoundContract) {
+	t.Helper()
+	compiled, err := Contract(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	address, _, contract, err := bind.DeployContract(c.Auth, compiled.ABI, compiled.Bin, c.Client, args...)
+	if err != nil {
+		t.Fatalf("failed to deploy %s: %v", name, err)
+	}
+	c.Commit(t)
+	return address, contract
+}
+
+// Bind binds a contract compiled from contracts/ at an address.
+func (c *Chain) Bind(t testing.TB, name string, address common.Address) *bind.BoundContract {
+	t.Helper()
+	compiled, err := Contract(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bind.NewBoundContract(address, compiled.ABI, c.Client, c.Client, c.Client)
+}
+
+// Transact calls a method as the funded account, mines it and fails the test
+// unless it succeeded.
+func (c *Chain) Transact(t testing.TB, contract *bind.BoundContract, method string, args ...interface{}) *ethtypes.Receipt {
+	t.Helper()
+	tx, err := contract.Transact(c.Auth, method, args...)
+	if err != nil {
+		t.Fatalf("%s failed: %v", method, err)
+	}
+	c.Commit(t)
+	receipt, err := c.Client.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.Status != ethtypes.ReceiptStatusSuccessful {
+		t.Fatalf("%s reverted", method)
+	}
+	return receipt
+}
+
+// DeployToken deploys a mintable ERC-20 token.
+func (c *Chain) DeployToken(t testing.TB, symbol string, decimals uint8) common.Address {
+	t.Helper()
+	address, _ := c.Deploy(t, "TestToken", symbol, symbol, decimals)
+	return address
+}
+
+// DeployExecutor deploys an executor owned by the funded account.
+func (c *Chain) DeployExecutor(t testing.TB) *executor.Executor {
+	t.Helper()
+	deployed, _, err := executor.Deploy(c.Auth, c.Client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Commit(t)
+	return deployed
+}
+
+// Mint mints amount of a token deployed by DeployToken to an account.
+func (c *Chain) Mint(t testing.TB, token, to common.Address, amount *big.Int) {
+	t.Helper()
+	c.Transact(t, c.Bind(t, "TestToken", token), "mint", to, amount)
+}
+
+// BalanceOf returns an account's balance of an ERC-20 token.
+func (c *Chain) BalanceOf(t testing.TB, token, account common.Address) *big.Int {
+	t.Helper()
+	var out []interface{}
+	if err := c.Bind(t, "TestToken", token).Call(&bind.CallOpts{}, &out, "balanceOf", account); err != nil {
+		t.Fatal(err)
+	}
+	return out[0].(*big.Int)
+}
+
+// DeployFactory deploys a UniswapV2Factory. Its pairs' addresses derive from
+// PairInitCodeHash.
+func (c *Chain) DeployFactory(t testing.TB) common.Address {
+	t.Helper()
+	address, _ := c.Deploy(t, "UniswapV2Factory", c.Auth.From)
+	return address
+}
+
+// DeployPair creates a UniswapV2Pair of two tokens deployed by DeployToken,
+// adds the reserves as liquidity and returns its address. Reserve0 is of
+// Token0 whichever token the pair orders first.
+func (c *Chain) DeployPair(t testing.TB, s PairState) common.Address {
+	t.Helper()
+	if s.Factory == (common.Address{}) {
+		s.Factory = c.DeployFactory(t)
+	}
+	factory := c.Bind(t, "UniswapV2Factory", s.Factory)
+	c.Transact(t, factory, "createPair", s.Token0, s.Token1)
+
+	var out []interface{}
+	if err := factory.Call(&bind.CallOpts{}, &out, "getPair", s.Token0, s.Token1); err != nil {
+		t.Fatal(err)
+	}
+	address := out[0].(common.Address)
+
+	c.Mint(t, s.Token0, address, s.Reserve0)
+	c.Mint(t, s.Token1, address, s.Reserve1)
+	c.Transact(t, c.Bind(t, "UniswapV2Pair", address), "mint", c.Auth.From)
+	return address
+}
+
+// SortTokens returns two token addresses in the order a pair keeps them.
+func SortTokens(tokenA, tokenB common.Address) (common.Address, common.Address) {
+	if bytes.Compare(tokenA.Bytes(), tokenB.Bytes()) > 0 {
+		return tokenB, tokenA
+	}
+	return tokenA, tokenB
+}
@@ -0,0 +1,252 @@
+package testutil
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"bb/contracts/uniswapv2"
+	"bb/executor"
+	"bb/txmanager"
+	"bb/types"
+)
+
+func TestSimulatedPair(t *testing.T) {
+	chain := NewChain(t)
+	factory := chain.DeployFactory(t)
+	eth, usdc := chain.DeployToken(t, "ETH", 18), chain.DeployToken(t, "USDC", 6)
+	token0, token1 := SortTokens(eth, usdc)
+	names := map[common.Address]string{eth: "ETH", usdc: "USDC"}
+	decimals := map[common.Address]int64{eth: 18, usdc: 6}
+	reserves := map[common.Address]*big.Int{eth: Amount(100, 18), usdc: Amount(200000, 6)}
+	address := chain.DeployPair(t, PairState{
+		Factory:  factory,
+		Token0:   token0,
+		Token1:   token1,
+		Reserve0: reserves[token0],
+		Reserve1: reserves[token1],
+	})
+
+	fork := uniswapv2pair.Fork{Name: "uni", Factory: factory, InitCodeHash: PairInitCodeHash(), FeePips: 3000}
+	if derived, err := fork.PairAddress(usdc, eth); err != nil || derived != address {
+		t.Fatalf("derived pair address %s %v, deployed at %s", derived.Hex(), err, address.Hex())
+	}
+	pair, err := uniswapv2pair.NewInstance(address.Hex(), chain.Client, fork, names[token0], names[token1], decimals[token0], decimals[token1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := pair.Validate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got0, got1, err := pair.Tokens(ctx); err != nil || got0 != token0 || got1 != token1 {
+		t.Fatalf("tokens %s %s %v", got0, got1, err)
+	}
+
+	// The pair reads as the in-memory pool of the same reserves does
+	pool := NewPool("uni", names[token0], names[token1], reserves[token0], reserves[token1])
+	pool.Decimals = [2]int64{decimals[token0], decimals[token1]}
+	want, _ := pool.Snapshot(ctx)
+	got, err := pair.Snapshot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.AmountOut.Amount1.Cmp(want.AmountOut.Amount1) != 0 || got.AmountOut.Amount2.Cmp(want.AmountOut.Amount2) != 0 {
+		t.Fatalf("rates %s %s, pool %s %s", got.AmountOut.Amount1, got.AmountOut.Amount2, want.AmountOut.Amount1, want.AmountOut.Amount2)
+	}
+
+	// Sell ETH through an executor: the pool prices the output
+	amountIn := Amount(1, 18)
+	amountOut, err := pool.Trade("ETH", amountIn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain.Mint(t, eth, chain.Auth.From, amountIn)
+	exec := chain.DeployExecutor(t)
+	chain.Transact(t, chain.Bind(t, "TestToken", eth), "approve", exec.Address, amountIn)
+	calls, err := pair.SwapCalls(exec.Address, eth, "ETH", amountOut)
+	if err != nil {
+		t.Fatal(err)
+	}
+	route := executor.Route{TokenIn: eth, AmountIn: amountIn, Calls: calls, TokenOut: usdc, MinAmountOut: amountOut}
+
+	monitorCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events := make(chan types.SwapEvent, 1)
+	go pair.Monitor(monitorCtx, events)
+	time.Sleep(100 * time.Millisecond) // let the subscription start
+
+	manager := txmanager.NewManager(chain.Client, chain.Auth, nil)
+	manager.Poll = 10 * time.Millisecond
+	go manager.Run(monitorCtx)
+	// A route paying out more than the pool can does not go out
+	greedy := route
+	greedy.MinAmountOut = new(big.Int).Add(amountOut, big.NewInt(1))
+	if _, err := manager.Transact(ctx, "greedy swap", func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return exec.Execute(opts, greedy)
+	}); err == nil {
+		t.Fatal("route below its minimum sent")
+	}
+
+	tx, err := manager.Transact(ctx, "swap", func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return exec.Execute(opts, route)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	block := chain.Commit(t)
+
+	waitCtx, stop := context.WithTimeout(ctx, 5*time.Second)
+	defer stop()
+	if status, err := tx.Wait(waitCtx); err != nil || status != txmanager.Mined {
+		t.Fatalf("swap %v %v", status, err)
+	}
+
+	// The pair paid out the USDC and moved to the pool's reserves, and the
+	// executor kept nothing
+	if got := chain.BalanceOf(t, usdc, chain.Auth.From); got.Cmp(amountOut) != 0 {
+		t.Errorf("received %s USDC, quoted %s", got, amountOut)
+	}
+	if got := tx.Outputs(); got[eth].Cmp(new(big.Int).Neg(amountIn)) != 0 || got[usdc].Cmp(amountOut) != 0 {
+		t.Errorf("outputs %v", got)
+	}
+	for _, token := range []common.Address{eth, usdc} {
+		if got := chain.BalanceOf(t, token, exec.Address); got.Sign() != 0 {
+			t.Errorf("executor holds %s of %s", got, token.Hex())
+		}
+	}
+	reserve0, reserve1 := pool.Reserves()
+	select {
+	case event := <-events:
+		if event.BlockNumber != block {
+			t.Errorf("event at block %d, swap mined at %d", event.BlockNumber, block)
+		}
+		if got, want := event.Reserve1, scale(new(big.Float).SetInt(reserve0), -decimals[token0]); got.Cmp(want) != 0 {
+			t.Errorf("reserve1 %s, pool %s", got, want)
+		}
+		if got, want := event.Reserve2, scale(new(big.Float).SetInt(reserve1), -decimals[token1]); got.Cmp(want) != 0 {
+			t.Errorf("reserve2 %s, pool %s", got, want)
+		}
+	case <-waitCtx.Done():
+		t.Fatal("no swap event")
+	}
+}
+
+func TestSimulatedPairRejectsOverdraw(t *testing.T) {
+	chain := NewChain(t)
+	tokenA, tokenB := chain.DeployToken(t, "A", 0), chain.DeployToken(t, "B", 0)
+	token0, token1 := SortTokens(tokenA, tokenB)
+	address := chain.DeployPair(t, PairState{Token0: token0, Token1: token1, Reserve0: big.NewInt(1000000), Reserve1: big.NewInt(1000000)})
+	pair, err := uniswapv2pair.NewInstance(address.Hex(), chain.Client, uniswapv2pair.Fork{Name: "uni"}, "A", "B", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, out := range [][2]int64{{1000000, 0}, {0, 2000000}, {1, 1}, {0, 0}} {
+		if _, err := pair.PairInterface.Swap(chain.Auth, big.NewInt(out[0]), big.NewInt(out[1]), chain.Auth.From, nil); err == nil {
+			t.Errorf("swap paying out %v succeeded", out)
+		}
+	}
+}
+
+// TestExecutorV3 swaps both ways through a pool that settles in the swap
+// callback, as Uniswap V3 pools do. The executor pays only the pool it is
+// swapping on.
+func TestExecutorV3(t *testing.T) {
+	chain := NewChain(t)
+	token0, token1 := SortTokens(chain.DeployToken(t, "A", 18), chain.DeployToken(t, "B", 18))
+	pool, _ := chain.Deploy(t, "MockUniswapV3Pool", token0, token1, Amount(2, 18))
+	chain.Mint(t, token0, pool, Amount(1000, 18))
+	chain.Mint(t, token1, pool, Amount(1000, 18))
+	chain.Mint(t, token0, chain.Auth.From, Amount(1, 18))
+	exec := chain.DeployExecutor(t)
+	for _, token := range []common.Address{token0, token1} {
+		chain.Transact(t, chain.Bind(t, "TestToken", token), "approve", exec.Address, Amount(10, 18))
+	}
+
+	swap := func(tokenIn, tokenOut common.Address, amountIn, minOut *big.Int) error {
+		call, err := executor.SwapV3(exec.Address, pool, tokenIn, minOut)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx, err := exec.Execute(chain.Auth, executor.Route{TokenIn: tokenIn, AmountIn: amountIn, Calls: []types.Call{call}, TokenOut: tokenOut, MinAmountOut: minOut})
+		if err != nil {
+			return err
+		}
+		chain.Commit(t)
+		if receipt, err := chain.Client.TransactionReceipt(context.Background(), tx.Hash()); err != nil || receipt.Status != ethtypes.ReceiptStatusSuccessful {
+			t.Fatalf("swap %v %v", receipt, err)
+		}
+		return nil
+	}
+
+	if err := swap(token0, token1, Amount(1, 18), Amount(2, 18)); err != nil {
+		t.Fatal(err)
+	}
+	if err := swap(token1, token0, Amount(2, 18), Amount(1.01, 18)); err == nil {
+		t.Fatal("swap below its minimum went through")
+	}
+	if err := swap(token1, token0, Amount(2, 18), Amount(1, 18)); err != nil {
+		t.Fatal(err)
+	}
+	if got0, got1 := chain.BalanceOf(t, token0, chain.Auth.From), chain.BalanceOf(t, token1, chain.Auth.From); got0.Cmp(Amount(1, 18)) != 0 || got1.Sign() != 0 {
+		t.Errorf("holding %s and %s after a round trip", got0, got1)
+	}
+
+	// Nobody else is paid through the callback, even out of stray tokens
+	chain.Mint(t, token0, exec.Address, big.NewInt(1))
+	parsed, err := abi.JSON(strings.NewReader(`[{"name":"uniswapV3SwapCallback","type":"function","inputs":[{"name":"amount0Delta","type":"int256"},{"name":"amount1Delta","type":"int256"},{"name":"data","type":"bytes"}],"outputs":[]}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := parsed.Pack("uniswapV3SwapCallback", big.NewInt(1), big.NewInt(0), common.LeftPadBytes(token0.Bytes(), 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chain.Client.EstimateGas(context.Background(), ethereum.CallMsg{From: chain.Auth.From, To: &exec.Address, Data: data}); err == nil {
+		t.Error("callback from outside a swap accepted")
+	}
+}
+
+// TestExecutorSpendsBalance has an executor approve a spender for its whole
+// balance, as it does before a pool pulls its input, and checks the amount
+// approved is the balance at the time of the call.
+func TestExecutorSpendsBalance(t *testing.T) {
+	chain := NewChain(t)
+	token := chain.DeployToken(t, "A", 18)
+	spender := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	chain.Mint(t, token, chain.Auth.From, Amount(3, 18))
+	exec := chain.DeployExecutor(t)
+	chain.Transact(t, chain.Bind(t, "TestToken", token), "approve", exec.Address, Amount(3, 18))
+
+	approve, err := executor.Approve(token, spender)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := executor.Spend(spender, approve.Data[:36], token); err == nil {
+		t.Fatal("call without the balance accepted")
+	}
+	if _, err := exec.Execute(chain.Auth, executor.Route{TokenIn: token, AmountIn: Amount(3, 18), Calls: []types.Call{approve}, TokenOut: token}); err != nil {
+		t.Fatal(err)
+	}
+	chain.Commit(t)
+
+	var out []interface{}
+	if err := chain.Bind(t, "TestToken", token).Call(&bind.CallOpts{}, &out, "allowance", exec.Address, spender); err != nil {
+		t.Fatal(err)
+	}
+	if got := out[0].(*big.Int); got.Cmp(Amount(3, 18)) != 0 {
+		t.Errorf("approved %s, the executor held %s", got, Amount(3, 18))
+	}
+	if got := chain.BalanceOf(t, token, chain.Auth.From); got.Cmp(Amount(3, 18)) != 0 {
+		t.Errorf("%s paid back, want all of it", got)
+	}
+}
@@ -0,0 +1,431 @@
+package txmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"bb/logging"
+)
+
+// Backend is the chain access the manager needs; an ethclient.Client
+// satisfies it. Nonces are read from a single node so that its pending state
+// stays consistent.
+type Backend interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*ethtypes.Header, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	SendTransaction(ctx context.Context, tx *ethtypes.Transaction) error
+	TransactionByHash(ctx context.Context, hash common.Hash) (*ethtypes.Transaction, bool, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*ethtypes.Receipt, error)
+}
+
+// Sender sends signed transactions privately, e.g. as bundles through a
+// relay. Send blocks until the transaction is included or given up on.
+type Sender interface {
+	Send(ctx context.Context, tx *ethtypes.Transaction) (included bool, err error)
+}
+
+// Manager owns the nonces of one account and follows every transaction it
+// sends until it is mined, reverted, replaced or dropped. Transactions still
+// pending after BumpAfter are re-sent with higher fees.
+type Manager struct {
+	Backend Backend
+	Auth    *bind.TransactOpts // account and signer; its nonce and fees are ignored
+
+	// Private, if set, sends transactions instead of the node. They are
+	// neither rebroadcast nor bumped, and are dropped if it gives up on them.
+	Private Sender
+
+	Poll      time.Duration // how often pending transactions are checked
+	BumpAfter time.Duration // how long a transaction may pend before a fee bump
+	BumpPct   int64         // fee increase per bump, at least the 10% nodes require
+	MaxBumps  int
+	DropAfter time.Duration // how long the node may not know a transaction before it is dropped
+
+	Log *slog.Logger
+
+	mu       sync.Mutex
+	nonce    *uint64         // next nonce to allocate, nil to resync from the node
+	reserved map[uint64]bool // allocated to transactions being built or sent
+	free     map[uint64]bool // released below nonce, to allocate first
+	pending  map[uint64]*Tx
+}
+
+// NewManager creates a manager sending from auth's account.
+func NewManager(backend Backend, auth *bind.TransactOpts, logger *slog.Logger) *Manager {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Manager{
+		Backend:   backend,
+		Auth:      auth,
+		Poll:      2 * time.Second,
+		BumpAfter: 30 * time.Second,
+		BumpPct:   15,
+		MaxBumps:  5,
+		DropAfter: 2 * time.Minute,
+		Log:       logger,
+		reserved:  make(map[uint64]bool),
+		free:      make(map[uint64]bool),
+		pending:   make(map[uint64]*Tx),
+	}
+}
+
+// Transact allocates a nonce and fees, lets build create and sign the
+// transaction with the given opts (which have NoSend set, e.g. for an abigen
+// binding or an executor's Execute), then sends and tracks it. The nonce is
+// reserved while the transaction is built and sent, so that other
+// transactions go ahead meanwhile, and released if it is not sent.
+func (m *Manager) Transact(ctx context.Context, label string, build func(opts *bind.TransactOpts) (*ethtypes.Transaction, error)) (*Tx, error) {
+	tipCap, feeCap, err := m.fees(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := m.reserve(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := *m.Auth
+	opts.Context = ctx
+	opts.Nonce = new(big.Int).SetUint64(nonce)
+	opts.GasTipCap = tipCap
+	opts.GasFeeCap = feeCap
+	opts.GasPrice = nil
+	opts.NoSend = true
+
+	signed, err := build(&opts)
+	if err != nil {
+		m.release(nonce, false)
+		return nil, err
+	}
+	if m.Private != nil {
+		return m.sendPrivate(ctx, label, signed), nil
+	}
+	// Once signed, the transaction is sent whatever becomes of ctx: a send
+	// cut short may still reach the node, leaving it untracked at a nonce
+	// that would be allocated again
+	if err := m.Backend.SendTransaction(context.WithoutCancel(ctx), signed); err != nil {
+		// The nonce may be out of step with the node; read it again next time
+		m.release(nonce, true)
+		return nil, fmt.Errorf("failed to send %s: %v", label, err)
+	}
+
+	tx := m.track(label, logging.ID(ctx), signed, false)
+	m.Log.InfoContext(ctx, "transaction sent", "label", label, "tx", signed.Hash().Hex(), "nonce", nonce)
+	return tx, nil
+}
+
+// track follows a sent transaction, whose nonce was reserved.
+func (m *Manager) track(label, id string, signed *ethtypes.Transaction, private bool) *Tx {
+	tx := newTx(label, id, signed)
+	tx.private = private
+	m.mu.Lock()
+	delete(m.reserved, signed.Nonce())
+	m.pending[signed.Nonce()] = tx
+	m.mu.Unlock()
+	return tx
+}
+
+// sendPrivate hands a transaction to Private and follows it. It is dropped,
+// and its nonce allocated again, unless it is included. Sending outlives
+// ctx, as the caller waits on the transaction rather than on Transact.
+func (m *Manager) sendPrivate(ctx context.Context, label string, signed *ethtypes.Transaction) *Tx {
+	tx := m.track(label, logging.ID(ctx), signed, true)
+	m.Log.InfoContext(ctx, "transaction sent privately", "label", label, "tx", signed.Hash().Hex(), "nonce", signed.Nonce())
+
+	go func() {
+		ctx := context.WithoutCancel(ctx)
+		included, err := m.Private.Send(ctx, signed)
+		if err != nil {
+			m.Log.WarnContext(ctx, "private transaction failed", "label", label, "tx", signed.Hash().Hex(), "err", err)
+		}
+		if included {
+			// Its receipt is picked up by the next check
+			return
+		}
+		m.finish(tx, nil, nil)
+	}()
+	return tx
+}
+
+// reserve allocates the lowest released nonce, or the next one, reading it
+// from the node when unknown.
+func (m *Manager) reserve(ctx context.Context) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.nonce == nil {
+		confirmed, err := m.Backend.PendingNonceAt(ctx, m.Auth.From)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read nonce: %v", err)
+		}
+		// Our own transactions may not have reached this node yet
+		nonce := confirmed
+		for used := range m.pending {
+			nonce = max(nonce, used+1)
+		}
+		for used := range m.reserved {
+			nonce = max(nonce, used+1)
+		}
+		// Released nonces the node counts as used are gone, and those above
+		// every nonce in use are no gap
+		for free := range m.free {
+			if free < confirmed || free >= nonce {
+				delete(m.free, free)
+			}
+		}
+		m.nonce = &nonce
+	}
+
+	var nonce uint64
+	if len(m.free) > 0 {
+		nonce = ^uint64(0)
+		for free := range m.free {
+			nonce = min(nonce, free)
+		}
+		delete(m.free, nonce)
+	} else {
+		nonce = *m.nonce
+		*m.nonce++
+	}
+	m.reserved[nonce] = true
+	return nonce, nil
+}
+
+// release gives back a reserved nonce that was not used. The highest nonce
+// allocated is simply taken back; a lower one leaves a gap that would hold
+// up the transactions above it, so it is allocated next. resync reads the
+// next nonce from the node again.
+func (m *Manager) release(nonce uint64, resync bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.reserved, nonce)
+	if m.nonce != nil && nonce+1 == *m.nonce {
+		*m.nonce = nonce
+		for *m.nonce > 0 && m.free[*m.nonce-1] {
+			delete(m.free, *m.nonce-1)
+			*m.nonce--
+		}
+	} else {
+		m.free[nonce] = true
+	}
+	if resync {
+		m.nonce = nil
+	}
+}
+
+// fees suggests an EIP-1559 tip and a fee cap covering two doublings of the
+// base fee.
+func (m *Manager) fees(ctx context.Context) (*big.Int, *big.Int, error) {
+	tipCap, err := m.Backend.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to suggest tip: %v", err)
+	}
+	head, err := m.Backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read head: %v", err)
+	}
+	feeCap := new(big.Int).Set(tipCap)
+	if head.BaseFee != nil {
+		feeCap.Add(feeCap, new(big.Int).Mul(head.BaseFee, big.NewInt(2)))
+	}
+	return tipCap, feeCap, nil
+}
+
+// Cancel replaces a pending transaction with an empty transfer to ourselves
+// at the same nonce and bumped fees.
+func (m *Manager) Cancel(ctx context.Context, tx *Tx) error {
+	current := tx.Current()
+	from := m.Auth.From
+	cancel := &ethtypes.DynamicFeeTx{
+		Nonce:     current.Nonce(),
+		GasTipCap: bump(current.GasTipCap(), m.BumpPct),
+		GasFeeCap: bump(current.GasFeeCap(), m.BumpPct),
+		Gas:       21000,
+		To:        &from,
+		Value:     new(big.Int),
+	}
+	signed, err := m.Auth.Signer(from, ethtypes.NewTx(cancel))
+	if err != nil {
+		return fmt.Errorf("failed to sign cancellation: %v", err)
+	}
+	// Once signed, the transaction is sent whatever becomes of ctx: a send
+	// cut short may still reach the node, leaving it untracked at a nonce
+	// that would be allocated again
+	if err := m.Backend.SendTransaction(context.WithoutCancel(ctx), signed); err != nil {
+		return fmt.Errorf("failed to send cancellation of %s: %v", tx.Label, err)
+	}
+	tx.replace(signed, true)
+	m.Log.InfoContext(tx.context(ctx), "cancelling transaction", "label", tx.Label, "tx", signed.Hash().Hex(), "nonce", signed.Nonce())
+	return nil
+}
+
+// Replace re-sends a pending transaction with its fees raised by BumpPct.
+func (m *Manager) Replace(ctx context.Context, tx *Tx) error {
+	current := tx.Current()
+	replacement := &ethtypes.DynamicFeeTx{
+		Nonce:      current.Nonce(),
+		GasTipCap:  bump(current.GasTipCap(), m.BumpPct),
+		GasFeeCap:  bump(current.GasFeeCap(), m.BumpPct),
+		Gas:        current.Gas(),
+		To:         current.To(),
+		Value:      current.Value(),
+		Data:       current.Data(),
+		AccessList: current.AccessList(),
+	}
+	signed, err := m.Auth.Signer(m.Auth.From, ethtypes.NewTx(replacement))
+	if err != nil {
+		return fmt.Errorf("failed to sign replacement: %v", err)
+	}
+	// Once signed, the transaction is sent whatever becomes of ctx: a send
+	// cut short may still reach the node, leaving it untracked at a nonce
+	// that would be allocated again
+	if err := m.Backend.SendTransaction(context.WithoutCancel(ctx), signed); err != nil {
+		return fmt.Errorf("failed to send replacement of %s: %v", tx.Label, err)
+	}
+	tx.replace(signed, false)
+	m.Log.InfoContext(tx.context(ctx), "transaction replaced", "label", tx.Label, "tx", signed.Hash().Hex(), "nonce", signed.Nonce(), "tip", signed.GasTipCap())
+	return nil
+}
+
+// notFound reports whether a lookup failed because the node has no such
+// transaction, including while it is still indexing transactions.
+func notFound(err error) bool {
+	return errors.Is(err, ethereum.NotFound) || (err != nil && strings.Contains(err.Error(), "indexing is in progress"))
+}
+
+func bump(value *big.Int, pct int64) *big.Int {
+	bumped := new(big.Int).Mul(value, big.NewInt(100+pct))
+	bumped.Quo(bumped, big.NewInt(100))
+	if bumped.Cmp(value) <= 0 {
+		bumped.Add(value, big.NewInt(1))
+	}
+	return bumped
+}
+
+// Pending returns the transactions that have not reached a final state,
+// ordered by nonce.
+func (m *Manager) Pending() []*Tx {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	txs := make([]*Tx, 0, len(m.pending))
+	for _, tx := range m.pending {
+		txs = append(txs, tx)
+	}
+	sortByNonce(txs)
+	return txs
+}
+
+// Run checks the pending transactions every Poll until ctx is cancelled.
+func (m *Manager) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.Poll)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.check(ctx)
+		}
+	}
+}
+
+func (m *Manager) check(ctx context.Context) {
+	confirmed, err := m.Backend.NonceAt(ctx, m.Auth.From, nil)
+	if err != nil {
+		m.Log.WarnContext(ctx, "failed to read confirmed nonce", "err", err)
+		return
+	}
+
+	for _, tx := range m.Pending() {
+		if err := m.checkTx(ctx, tx, confirmed); err != nil {
+			m.Log.WarnContext(tx.context(ctx), "failed to check transaction", "label", tx.Label, "err", err)
+		}
+	}
+}
+
+func (m *Manager) checkTx(ctx context.Context, tx *Tx, confirmed uint64) error {
+	// Any of the attempts may have been mined
+	for _, attempt := range tx.Attempts() {
+		receipt, err := m.Backend.TransactionReceipt(ctx, attempt.Hash())
+		if notFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		m.finish(tx, attempt, receipt)
+		return nil
+	}
+
+	nonce := tx.Current().Nonce()
+	if confirmed > nonce {
+		// The nonce was used by a transaction we did not track
+		m.finish(tx, nil, nil)
+		return nil
+	}
+	if tx.private {
+		// Only Private can tell whether it is still coming
+		return nil
+	}
+
+	known := false
+	for _, attempt := range tx.Attempts() {
+		if _, _, err := m.Backend.TransactionByHash(ctx, attempt.Hash()); err == nil {
+			known = true
+			break
+		}
+	}
+	if !known {
+		if time.Since(tx.Sent()) > m.DropAfter {
+			m.finish(tx, nil, nil)
+			return nil
+		}
+		// Rebroadcast in case the node lost it
+		if err := m.Backend.SendTransaction(ctx, tx.Current()); err != nil && !strings.Contains(err.Error(), "already known") {
+			m.Log.WarnContext(tx.context(ctx), "failed to rebroadcast transaction", "label", tx.Label, "err", err)
+		}
+		return nil
+	}
+
+	if time.Since(tx.Sent()) > m.BumpAfter && tx.Bumps() < m.MaxBumps && !tx.Cancelling() {
+		return m.Replace(ctx, tx)
+	}
+	return nil
+}
+
+// finish records the final state of tx. A nil receipt means it was dropped.
+func (m *Manager) finish(tx *Tx, mined *ethtypes.Transaction, receipt *ethtypes.Receipt) {
+	m.mu.Lock()
+	nonce := tx.Current().Nonce()
+	if m.pending[nonce] != tx {
+		// Already final
+		m.mu.Unlock()
+		return
+	}
+	delete(m.pending, nonce)
+	if receipt == nil {
+		// The nonce is either used by someone else or free again, which the
+		// node tells when it is read again
+		m.free[nonce] = true
+		m.nonce = nil
+	}
+	m.mu.Unlock()
+
+	tx.finish(mined, receipt, m.Auth.From)
+	m.Log.InfoContext(tx.context(context.Background()), "transaction final", "tx", tx.String())
+}
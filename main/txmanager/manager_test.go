@@ -0,0 +1,311 @@
+package txmanager
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// fakeNode is a node with a mempool that mines only what a test tells it to.
+type fakeNode struct {
+	mu        sync.Mutex
+	pending   uint64 // next nonce of the account, counting its pool
+	confirmed uint64 // next nonce of the account, counting mined transactions
+	pool      map[common.Hash]*ethtypes.Transaction
+	receipts  map[common.Hash]*ethtypes.Receipt
+	sent      []*ethtypes.Transaction
+	sendErr   error
+}
+
+func newFakeNode() *fakeNode {
+	return &fakeNode{pool: make(map[common.Hash]*ethtypes.Transaction), receipts: make(map[common.Hash]*ethtypes.Receipt)}
+}
+
+func (n *fakeNode) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.pending, nil
+}
+
+func (n *fakeNode) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.confirmed, nil
+}
+
+func (n *fakeNode) HeaderByNumber(ctx context.Context, number *big.Int) (*ethtypes.Header, error) {
+	return &ethtypes.Header{Number: big.NewInt(1), BaseFee: big.NewInt(10e9)}, nil
+}
+
+func (n *fakeNode) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(1e9), nil
+}
+
+func (n *fakeNode) SendTransaction(ctx context.Context, tx *ethtypes.Transaction) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.sendErr != nil {
+		return n.sendErr
+	}
+	n.sent = append(n.sent, tx)
+	n.pool[tx.Hash()] = tx
+	n.pending = max(n.pending, tx.Nonce()+1)
+	return nil
+}
+
+func (n *fakeNode) TransactionByHash(ctx context.Context, hash common.Hash) (*ethtypes.Transaction, bool, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if tx, ok := n.pool[hash]; ok {
+		return tx, true, nil
+	}
+	return nil, false, ethereum.NotFound
+}
+
+func (n *fakeNode) TransactionReceipt(ctx context.Context, hash common.Hash) (*ethtypes.Receipt, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if receipt, ok := n.receipts[hash]; ok {
+		return receipt, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+// mine mines a transaction with the given receipt status.
+func (n *fakeNode) mine(tx *ethtypes.Transaction, status uint64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.receipts[tx.Hash()] = &ethtypes.Receipt{Status: status, TxHash: tx.Hash(), BlockNumber: big.NewInt(2), GasUsed: 21000, EffectiveGasPrice: big.NewInt(11e9)}
+	n.confirmed = max(n.confirmed, tx.Nonce()+1)
+	n.forget(tx.Nonce())
+}
+
+// forget drops every transaction at a nonce from the pool. n.mu must be held.
+func (n *fakeNode) forget(nonce uint64) {
+	for hash, tx := range n.pool {
+		if tx.Nonce() == nonce {
+			delete(n.pool, hash)
+		}
+	}
+}
+
+func newTestManager(t *testing.T, node *fakeNode) *Manager {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewManager(node, auth, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+// transfer builds a transfer to the zero address with the manager's opts.
+func transfer(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+	tx := ethtypes.NewTx(&ethtypes.DynamicFeeTx{
+		Nonce:     opts.Nonce.Uint64(),
+		GasTipCap: opts.GasTipCap,
+		GasFeeCap: opts.GasFeeCap,
+		Gas:       21000,
+		To:        &common.Address{},
+		Value:     big.NewInt(1),
+	})
+	return opts.Signer(opts.From, tx)
+}
+
+func send(t *testing.T, m *Manager) *Tx {
+	t.Helper()
+	tx, err := m.Transact(context.Background(), "transfer", transfer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func nonces(txs ...*Tx) []uint64 {
+	var nonces []uint64
+	for _, tx := range txs {
+		nonces = append(nonces, tx.Current().Nonce())
+	}
+	return nonces
+}
+
+// TestTransactNonces builds a transaction slowly while others go ahead, and
+// fails to build or send some: every nonce is used, without gaps.
+func TestTransactNonces(t *testing.T) {
+	node := newFakeNode()
+	node.pending = 5
+	m := newTestManager(t, node)
+
+	// A slow build does not hold up the next transaction
+	building, finish := make(chan struct{}), make(chan error)
+	slow := make(chan *Tx)
+	go func() {
+		tx, err := m.Transact(context.Background(), "slow", func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+			close(building)
+			if err := <-finish; err != nil {
+				return nil, err
+			}
+			return transfer(opts)
+		})
+		if err != nil {
+			tx = nil
+		}
+		slow <- tx
+	}()
+	<-building
+	fast := send(t, m)
+	if fast.Current().Nonce() != 6 {
+		t.Fatalf("sent at nonce %d while 5 was being built", fast.Current().Nonce())
+	}
+
+	// The slow build fails: its nonce is the next one allocated
+	finish <- errors.New("no route")
+	if tx := <-slow; tx != nil {
+		t.Fatal("failed build sent")
+	}
+	if got := nonces(send(t, m), send(t, m)); got[0] != 5 || got[1] != 7 {
+		t.Fatalf("nonces %v after releasing 5, want [5 7]", got)
+	}
+
+	// The highest nonce is taken back
+	if _, err := m.Transact(context.Background(), "broken", func(*bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return nil, errors.New("broken")
+	}); err == nil {
+		t.Fatal("failed build sent")
+	}
+	if got := send(t, m).Current().Nonce(); got != 8 {
+		t.Fatalf("nonce %d, want 8", got)
+	}
+
+	// A failed send reads the nonce again, past our own pending ones
+	node.sendErr = errors.New("nonce too low")
+	if _, err := m.Transact(context.Background(), "transfer", transfer); err == nil {
+		t.Fatal("send did not fail")
+	}
+	node.sendErr = nil
+	node.mu.Lock()
+	node.pending = 3
+	node.mu.Unlock()
+	if got := send(t, m).Current().Nonce(); got != 9 {
+		t.Fatalf("nonce %d after resync, want 9", got)
+	}
+	if pending := m.Pending(); len(pending) != 5 {
+		t.Fatalf("%d pending, want 5", len(pending))
+	}
+}
+
+// TestTransactOutlivesCancel cancels the caller's ctx once the transaction
+// is signed, as a new evaluation does to the one that found a trade: it is
+// still sent and tracked at its nonce.
+func TestTransactOutlivesCancel(t *testing.T) {
+	node := newFakeNode()
+	m := newTestManager(t, node)
+	ctx, cancel := context.WithCancel(context.Background())
+	tx, err := m.Transact(ctx, "transfer", func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		defer cancel()
+		return transfer(opts)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(node.sent) != 1 || len(m.Pending()) != 1 || m.Pending()[0] != tx {
+		t.Fatalf("sent %d, tracking %d", len(node.sent), len(m.Pending()))
+	}
+	if got := send(t, m).Current().Nonce(); got != 1 {
+		t.Fatalf("nonce %d after a cancelled send, want 1", got)
+	}
+}
+
+// TestCheck follows transactions to each final state.
+func TestCheck(t *testing.T) {
+	ctx := context.Background()
+	node := newFakeNode()
+	m := newTestManager(t, node)
+	m.BumpAfter = 0
+
+	// Pending too long: replaced with higher fees, and the replacement mines
+	replaced := send(t, m)
+	first := replaced.Current()
+	m.check(ctx)
+	if replaced.Bumps() != 1 || len(replaced.Attempts()) != 2 {
+		t.Fatalf("%d bumps, %d attempts", replaced.Bumps(), len(replaced.Attempts()))
+	}
+	second := replaced.Current()
+	if second.Nonce() != first.Nonce() || second.GasTipCap().Cmp(bump(first.GasTipCap(), m.BumpPct)) != 0 || second.GasFeeCap().Cmp(bump(first.GasFeeCap(), m.BumpPct)) != 0 {
+		t.Fatalf("replacement nonce %d tip %s cap %s", second.Nonce(), second.GasTipCap(), second.GasFeeCap())
+	}
+	node.mine(second, ethtypes.ReceiptStatusSuccessful)
+
+	// Cancelled: the cancellation mines in its place
+	m.BumpAfter = time.Hour
+	cancelled := send(t, m)
+	if err := m.Cancel(ctx, cancelled); err != nil {
+		t.Fatal(err)
+	}
+	if to := cancelled.Current().To(); to == nil || *to != m.Auth.From || cancelled.Current().Value().Sign() != 0 {
+		t.Fatalf("cancellation to %v of %s", to, cancelled.Current().Value())
+	}
+	node.mine(cancelled.Current(), ethtypes.ReceiptStatusSuccessful)
+
+	// Reverted
+	reverted := send(t, m)
+	node.mine(reverted.Current(), ethtypes.ReceiptStatusFailed)
+
+	// Dropped: its nonce was used by a transaction we did not send
+	usedElsewhere := send(t, m)
+	node.mu.Lock()
+	node.confirmed = usedElsewhere.Current().Nonce() + 1
+	node.forget(usedElsewhere.Current().Nonce())
+	node.mu.Unlock()
+
+	m.check(ctx)
+	for _, tt := range []struct {
+		tx   *Tx
+		want Status
+	}{{replaced, Mined}, {cancelled, Cancelled}, {reverted, Reverted}, {usedElsewhere, Dropped}} {
+		if status := tt.tx.Status(); status != tt.want {
+			t.Errorf("%s: %s, want %s", tt.tx.Current().Hash().Hex(), status, tt.want)
+		}
+	}
+	if tx := replaced.Receipt(); tx == nil || tx.TxHash != second.Hash() || replaced.Hash() != second.Hash() {
+		t.Errorf("mined %v, want the replacement %s", tx, second.Hash().Hex())
+	}
+
+	// Dropped: the node lost it, and it did not come back; its nonce is
+	// used again
+	m.DropAfter = 0
+	lost := send(t, m)
+	node.mu.Lock()
+	node.forget(lost.Current().Nonce())
+	node.pending = lost.Current().Nonce()
+	node.mu.Unlock()
+	m.check(ctx)
+	waitCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	if status, err := lost.Wait(waitCtx); err != nil || status != Dropped {
+		t.Fatalf("lost transaction %s %v", status, err)
+	}
+	if got := send(t, m).Current().Nonce(); got != lost.Current().Nonce() {
+		t.Fatalf("nonce %d after a drop, want %d again", got, lost.Current().Nonce())
+	}
+	if len(m.Pending()) != 1 {
+		t.Fatalf("%d pending", len(m.Pending()))
+	}
+}
@@ -0,0 +1,259 @@
+package txmanager
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"bb/logging"
+)
+
+// Status is the lifecycle state of a transaction.
+type Status int
+
+const (
+	Pending Status = iota
+	Mined
+	Reverted
+	Cancelled // our cancellation was mined in its place
+	Dropped   // never mined; the nonce was used elsewhere or the node lost it
+)
+
+func (s Status) String() string {
+	switch s {
+	case Pending:
+		return "pending"
+	case Mined:
+		return "mined"
+	case Reverted:
+		return "reverted"
+	case Cancelled:
+		return "cancelled"
+	case Dropped:
+		return "dropped"
+	}
+	return fmt.Sprintf("status(%d)", int(s))
+}
+
+// Final reports whether the status will not change any more.
+func (s Status) Final() bool {
+	return s != Pending
+}
+
+// transferTopic is the topic of the ERC-20 Transfer event.
+var transferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// Tx is a transaction followed by the manager, together with every
+// replacement sent at its nonce.
+type Tx struct {
+	Label string
+	ID    string // correlation ID of the context it was sent with
+
+	private bool // sent through the manager's Private sender
+
+	mu         sync.Mutex
+	attempts   []*ethtypes.Transaction
+	sent       time.Time // when the current attempt was sent
+	bumps      int
+	cancelling bool
+	status     Status
+	receipt    *ethtypes.Receipt
+	outputs    map[common.Address]*big.Int
+	done       chan struct{}
+}
+
+func newTx(label, id string, signed *ethtypes.Transaction) *Tx {
+	return &Tx{
+		Label:    label,
+		ID:       id,
+		attempts: []*ethtypes.Transaction{signed},
+		sent:     time.Now(),
+		done:     make(chan struct{}),
+	}
+}
+
+// Current returns the latest attempt.
+func (t *Tx) Current() *ethtypes.Transaction {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.attempts[len(t.attempts)-1]
+}
+
+// Attempts returns every attempt sent, oldest first.
+func (t *Tx) Attempts() []*ethtypes.Transaction {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*ethtypes.Transaction(nil), t.attempts...)
+}
+
+// Sent returns when the latest attempt was sent.
+func (t *Tx) Sent() time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.sent
+}
+
+func (t *Tx) Bumps() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.bumps
+}
+
+func (t *Tx) Cancelling() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.cancelling
+}
+
+func (t *Tx) replace(signed *ethtypes.Transaction, cancel bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.attempts = append(t.attempts, signed)
+	t.sent = time.Now()
+	t.bumps++
+	t.cancelling = t.cancelling || cancel
+}
+
+// Hash returns the hash of the mined attempt, or of the latest one while none
+// is mined.
+func (t *Tx) Hash() common.Hash {
+	if receipt := t.Receipt(); receipt != nil {
+		return receipt.TxHash
+	}
+	return t.Current().Hash()
+}
+
+// Status returns the current state.
+func (t *Tx) Status() Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.status
+}
+
+// Receipt returns the receipt of the mined attempt, or nil.
+func (t *Tx) Receipt() *ethtypes.Receipt {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.receipt
+}
+
+// GasUsed returns the gas used by the mined attempt.
+func (t *Tx) GasUsed() uint64 {
+	if receipt := t.Receipt(); receipt != nil {
+		return receipt.GasUsed
+	}
+	return 0
+}
+
+// GasCost returns the fee paid for the mined attempt in wei.
+func (t *Tx) GasCost() *big.Int {
+	receipt := t.Receipt()
+	if receipt == nil || receipt.EffectiveGasPrice == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Mul(receipt.EffectiveGasPrice, new(big.Int).SetUint64(receipt.GasUsed))
+}
+
+// Outputs returns the realized token flows of the account, by token address,
+// from the Transfer events of the mined attempt: positive for tokens
+// received, negative for tokens sent.
+func (t *Tx) Outputs() map[common.Address]*big.Int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	outputs := make(map[common.Address]*big.Int, len(t.outputs))
+	for token, amount := range t.outputs {
+		outputs[token] = new(big.Int).Set(amount)
+	}
+	return outputs
+}
+
+// Wait blocks until the transaction reaches a final state or ctx ends.
+func (t *Tx) Wait(ctx context.Context) (Status, error) {
+	select {
+	case <-t.done:
+		return t.Status(), nil
+	case <-ctx.Done():
+		return Pending, ctx.Err()
+	}
+}
+
+func (t *Tx) finish(mined *ethtypes.Transaction, receipt *ethtypes.Receipt, account common.Address) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.receipt = receipt
+	switch {
+	case receipt == nil:
+		t.status = Dropped
+	case receipt.Status == ethtypes.ReceiptStatusFailed:
+		t.status = Reverted
+	case t.cancelling && mined == t.attempts[len(t.attempts)-1]:
+		t.status = Cancelled
+	default:
+		t.status = Mined
+	}
+
+	if receipt != nil {
+		t.outputs = transfers(receipt, account)
+	}
+	close(t.done)
+}
+
+// transfers nets the ERC-20 transfers to and from account in a receipt.
+func transfers(receipt *ethtypes.Receipt, account common.Address) map[common.Address]*big.Int {
+	outputs := make(map[common.Address]*big.Int)
+	for _, l := range receipt.Logs {
+		if len(l.Topics) != 3 || l.Topics[0] != transferTopic || len(l.Data) != 32 {
+			continue
+		}
+		from := common.BytesToAddress(l.Topics[1].Bytes())
+		to := common.BytesToAddress(l.Topics[2].Bytes())
+		amount := new(big.Int).SetBytes(l.Data)
+		if from != account && to != account {
+			continue
+		}
+
+		if outputs[l.Address] == nil {
+			outputs[l.Address] = new(big.Int)
+		}
+		if to == account {
+			outputs[l.Address].Add(outputs[l.Address], amount)
+		}
+		if from == account {
+			outputs[l.Address].Sub(outputs[l.Address], amount)
+		}
+	}
+	return outputs
+}
+
+// context returns ctx carrying the transaction's correlation ID, for logging
+// outside the context it was sent with.
+func (t *Tx) context(ctx context.Context) context.Context {
+	if t.ID == "" {
+		return ctx
+	}
+	return logging.WithID(ctx, t.ID)
+}
+
+func (t *Tx) String() string {
+	current := t.Current()
+	status := t.Status()
+	if status == Pending || status == Dropped {
+		return fmt.Sprintf("%s %s: %s (nonce %d, %d attempt(s))", t.Label, status, current.Hash().Hex(), current.Nonce(), len(t.Attempts()))
+	}
+	receipt := t.Receipt()
+	return fmt.Sprintf("%s %s: %s in block %d, gas used %d, cost %s wei", t.Label, status, receipt.TxHash.Hex(), receipt.BlockNumber, receipt.GasUsed, t.GasCost())
+}
+
+func sortByNonce(txs []*Tx) {
+	sort.Slice(txs, func(i, j int) bool {
+		return txs[i].Current().Nonce() < txs[j].Current().Nonce()
+	})
+}
@@ -3,17 +3,38 @@ package types
 import (
   "math/big"
   "context"
+
+  "github.com/ethereum/go-ethereum/common"
 )
 
 type Pair interface {
-	Monitor(ctx context.Context, swapEventChan chan<- SwapEvent)
-	ExecuteSwap(amountIn1, amountIn2 *big.Int) error
+	// Monitor sends the pair's swap events until ctx is cancelled, returning
+	// nil then, or returns the error that stopped it.
+	Monitor(ctx context.Context, swapEventChan chan<- SwapEvent) error
+	// SwapCalls returns the calls an executor contract at executor makes to
+	// sell its whole balance of assetIn, the token at tokenIn, through the
+	// pair for at least minOut raw units of the other asset. A nil minOut
+	// sets no minimum.
+	SwapCalls(executor, tokenIn common.Address, assetIn string, minOut *big.Int) ([]Call, error)
+	// Quote returns the exact output, in raw token units, of swapping amountIn
+	// of assetIn through the pair at its last known state.
+	Quote(assetIn string, amountIn *big.Int) (*big.Int, error)
 	Asset1() string
 	Asset2() string
 	DEX() string
 	Address() string
 }
 
+// Call is a call an executor contract makes as one step of a route. If Token
+// is set, the executor first writes its balance of Token into the 32-byte
+// word of Data at Offset, so the call spends whatever earlier calls paid out.
+type Call struct {
+	To     common.Address
+	Data   []byte
+	Token  common.Address
+	Offset int
+}
+
 type AmountOut struct {
   Amount1 *big.Float
   Amount2 *big.Float
@@ -25,4 +46,27 @@ type SwapEvent struct {
   Asset2Name string
   Address    string
   AmountOut  AmountOut
+  Reserve1    *big.Float // pool reserve of Asset1, in whole tokens
+  Reserve2    *big.Float // pool reserve of Asset2, in whole tokens
+  BlockNumber uint64     // block the reserves were read at
+}
+
+// Snapshotter is a pair that can read its current state from the chain and
+// describe it as a swap event, without waiting for a swap. BlockNumber is 0
+// when the state's block is not known.
+type Snapshotter interface {
+	Snapshot(ctx context.Context) (SwapEvent, error)
+}
+
+// Token is the ERC-20 token behind one of a pair's assets.
+type Token struct {
+	Asset    string
+	Address  common.Address
+	Decimals int64
+}
+
+// TokenReader is a pair that can tell the tokens of Asset1 and Asset2, in
+// that order. Their addresses are read from the chain at most once.
+type TokenReader interface {
+	PairTokens(ctx context.Context) (Token, Token, error)
 }